@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/jusunglee/leagueofren/internal/db"
 	"github.com/jusunglee/leagueofren/internal/db/postgres"
 	"github.com/jusunglee/leagueofren/internal/db/sqlite"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
 	"github.com/jusunglee/leagueofren/internal/envsetup"
 	"github.com/jusunglee/leagueofren/internal/google"
 	"github.com/jusunglee/leagueofren/internal/health"
@@ -66,7 +68,7 @@ func mainE() error {
 		databaseURL                  = fs.StringLong("database-url", "", "PostgreSQL connection URL")
 		discordToken                 = fs.StringLong("discord-token", "", "Discord bot token")
 		riotAPIKey                   = fs.StringLong("riot-api-key", "", "Riot Games API key")
-		llmProvider                  = fs.StringEnumLong("llm-provider", "LLM provider", "anthropic", "google")
+		llmProvider                  = fs.StringEnumLong("llm-provider", "LLM provider (none translates with the offline dictionary only)", "anthropic", "google", "none")
 		llmModel                     = fs.StringLong("llm-model", "", "LLM model name")
 		guildID                      = fs.StringLong("guild-id", "", "Discord guild ID for command registration")
 		anthropicAPIKey              = fs.StringLong("anthropic-api-key", "", "Anthropic API key")
@@ -82,6 +84,11 @@ func mainE() error {
 		healthPort                   = fs.Int64Long("health-port", 8080, "Port for health check HTTP server")
 		websiteURL                   = fs.StringLong("website-url", "", "Companion website URL for submitting translations (empty to disable)")
 		grafanaHost                  = fs.StringLong("grafana-host", "", "Grafana host (enables Prometheus metrics server when set)")
+		staleTranslationPolicy       = fs.StringEnumLong("stale-translation-policy", "How to handle cached translations from an older model or prompt (refresh, serve, bypass)", "refresh", "serve", "bypass")
+		translationBatchSize         = fs.IntLong("translation-batch-size", 10, "Maximum names sent to the LLM in one translation call")
+		cedictPath                   = fs.StringLong("cedict-path", "", "Path to a full CC-CEDICT file for offline translation (defaults to the bundled one)")
+		serverMonthlyBudgetUSD       = fs.Float64Long("server-monthly-budget-usd", 0, "LLM spend per Discord server per month before falling back to cached translations (0 for unlimited)")
+		serverBudgets                = fs.StringLong("server-budgets", "", "Per-server budget overrides in USD as server_id=usd, comma separated (0 for unlimited)")
 	)
 
 	if err := ff.Parse(fs, os.Args[1:], ff.WithEnvVars()); err != nil {
@@ -98,10 +105,20 @@ func mainE() error {
 	if *riotAPIKey == "" {
 		return errors.New("riot-api-key is required")
 	}
-	if *llmModel == "" {
+	if *llmModel == "" && *llmProvider != "none" {
 		return errors.New("llm-model is required")
 	}
 
+	budgets, err := parseServerBudgets(*serverBudgets)
+	if err != nil {
+		return err
+	}
+
+	dict, err := loadDictionary(*cedictPath)
+	if err != nil {
+		return err
+	}
+
 	var client llm.Client
 	switch *llmProvider {
 	case "anthropic":
@@ -113,7 +130,6 @@ func mainE() error {
 		if *googleAPIKey == "" {
 			return errors.New("google-api-key is required when using google provider")
 		}
-		var err error
 		client, err = google.NewClient(context.Background(), *googleAPIKey, google.Model(*llmModel))
 		if err != nil {
 			return fmt.Errorf("creating Google client: %w", err)
@@ -147,7 +163,17 @@ func mainE() error {
 	}
 	defer repo.Close()
 
-	translator := translation.NewTranslator(client, repo, *llmProvider, *llmModel)
+	if client == nil {
+		log.WarnContext(ctx, "no LLM provider configured, translating with the offline dictionary", "dictionary_words", dict.Len())
+	} else if _, priced := llm.Prices[*llmModel]; !priced && (*serverMonthlyBudgetUSD > 0 || len(budgets) > 0) {
+		log.WarnContext(ctx, "no price known for LLM model, server budgets won't be enforced", "model", *llmModel)
+	}
+	translator := translation.NewTranslator(client, repo, *llmProvider, *llmModel,
+		translation.WithStalePolicy(translation.StalePolicy(*staleTranslationPolicy)),
+		translation.WithMaxBatchSize(*translationBatchSize),
+		translation.WithDictionary(dict),
+		translation.WithLogger(log),
+	)
 	riotClient := riot.NewCachedClient(*riotAPIKey, repo)
 	log.InfoContext(ctx, "riot API client initialized with caching")
 
@@ -170,6 +196,9 @@ func mainE() error {
 			GuildID:                      *guildID,
 			JobBufferSize:                int(*jobBufferSize),
 			WebsiteURL:                   *websiteURL,
+			ServerMonthlyBudgetUSD:       *serverMonthlyBudgetUSD,
+			ServerBudgetsUSD:             budgets,
+			Dictionary:                   dict,
 		},
 	)
 
@@ -213,6 +242,51 @@ func mainE() error {
 	return b.Run(ctx, cancel)
 }
 
+// loadDictionary returns the bundled offline dictionary, or one built from a
+// full CC-CEDICT file plus the bundled Korean word list when path is set.
+func loadDictionary(path string) (*dictionary.Dictionary, error) {
+	if path == "" {
+		dict, err := dictionary.Bundled()
+		if err != nil {
+			return nil, fmt.Errorf("loading bundled dictionary: %w", err)
+		}
+		return dict, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening CC-CEDICT file: %w", err)
+	}
+	defer f.Close()
+
+	dict, err := dictionary.WithCEDICT(f)
+	if err != nil {
+		return nil, fmt.Errorf("loading CC-CEDICT file: %w", err)
+	}
+	return dict, nil
+}
+
+// parseServerBudgets parses "server_id=usd,server_id=usd" budget overrides.
+func parseServerBudgets(s string) (map[string]float64, error) {
+	budgets := make(map[string]float64)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		serverID, usd, ok := strings.Cut(entry, "=")
+		if !ok || serverID == "" {
+			return nil, fmt.Errorf("server-budgets entry %q must be server_id=usd", entry)
+		}
+		budget, err := strconv.ParseFloat(usd, 64)
+		if err != nil || budget < 0 {
+			return nil, fmt.Errorf("server-budgets entry %q has an invalid amount", entry)
+		}
+		budgets[serverID] = budget
+	}
+	return budgets, nil
+}
+
 func isSQLite(url string) bool {
 	if strings.HasPrefix(url, "sqlite://") {
 		return true
@@ -9,7 +9,6 @@ import (
 	"log/slog"
 	"os"
 	"time"
-	"unicode"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
@@ -22,6 +21,7 @@ import (
 	"github.com/jusunglee/leagueofren/internal/logger"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
 )
 
 func main() {
@@ -129,7 +129,7 @@ func run() error {
 
 		// Find a participant with foreign characters
 		for _, p := range game.Participants {
-			if containsForeignCharacters(p.GameName) {
+			if transliteration.ContainsScript(p.GameName, nil) {
 				targetRiotID = p.GameName
 				targetPUUID = p.PUUID
 				break
@@ -243,12 +243,3 @@ func requireEnv(key string) string {
 	}
 	return val
 }
-
-func containsForeignCharacters(s string) bool {
-	for _, r := range s {
-		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hangul, r) {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,222 @@
+// Command evalharness runs a golden set of summoner names through one or more
+// models and prompt variants and writes a comparison report, so prompt and
+// model changes can be judged before switching production over.
+//
+// In record mode every response is saved to a cassette; replay mode scores the
+// cassette again without network access.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/jusunglee/leagueofren/internal/anthropic"
+	"github.com/jusunglee/leagueofren/internal/evalharness"
+	"github.com/jusunglee/leagueofren/internal/google"
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/jusunglee/leagueofren/internal/logger"
+	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/peterbourgon/ff/v4/ffhelp"
+)
+
+func main() {
+	if err := mainE(); err != nil {
+		slog.Error("fatal", "error", err)
+		os.Exit(1)
+	}
+}
+
+func mainE() error {
+	_ = godotenv.Load()
+
+	fs := ff.NewFlagSet("leagueofren-evalharness")
+	var (
+		goldenPath      = fs.StringLong("golden", "scripts/golden.csv", "Golden set CSV (name,reference,language)")
+		targets         = fs.StringListLong("target", "Model to evaluate as provider/model, e.g. anthropic/claude-haiku-4-5-20251001 (repeatable; defaults to LLM_PROVIDER/LLM_MODEL)")
+		prompts         = fs.StringListLong("prompt", "System prompt file to compare against the production prompt (repeatable)")
+		mode            = fs.StringEnumLong("mode", "live calls providers, record also saves responses to the cassette, replay scores the cassette offline", "live", "record", "replay")
+		cassettePath    = fs.StringLong("cassette", "scripts/eval-cassette.json", "Cassette file for record and replay modes")
+		out             = fs.StringLong("out", "eval-report", "Report path prefix; writes <out>.md and <out>.json")
+		batchSize       = fs.IntLong("batch-size", 10, "Names per LLM call")
+		llmProvider     = fs.StringLong("llm-provider", "", "Default provider when no --target is given")
+		llmModel        = fs.StringLong("llm-model", "", "Default model when no --target is given")
+		anthropicAPIKey = fs.StringLong("anthropic-api-key", "", "Anthropic API key")
+		googleAPIKey    = fs.StringLong("google-api-key", "", "Google API key")
+	)
+
+	if err := ff.Parse(fs, os.Args[1:], ff.WithEnvVars()); err != nil {
+		fmt.Printf("%s\n", ffhelp.Flags(fs))
+		return fmt.Errorf("parsing flags: %w", err)
+	}
+	if *batchSize <= 0 {
+		return errors.New("batch-size must be positive")
+	}
+
+	ctx := context.Background()
+	log := logger.New()
+
+	f, err := os.Open(*goldenPath)
+	if err != nil {
+		return fmt.Errorf("opening golden set: %w", err)
+	}
+	cases, err := evalharness.LoadGolden(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("loading golden set: %w", err)
+	}
+
+	variants, err := loadVariants(*prompts)
+	if err != nil {
+		return err
+	}
+
+	var cassette *evalharness.Cassette
+	switch *mode {
+	case "record":
+		cassette = evalharness.NewCassette()
+	case "replay":
+		cf, err := os.Open(*cassettePath)
+		if err != nil {
+			return fmt.Errorf("opening cassette: %w", err)
+		}
+		cassette, err = evalharness.LoadCassette(cf)
+		cf.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	names := *targets
+	if len(names) == 0 {
+		if *mode == "replay" {
+			names = cassette.TargetNames()
+		} else if *llmProvider != "" && *llmModel != "" {
+			names = []string{*llmProvider + "/" + *llmModel}
+		}
+	}
+	if len(names) == 0 {
+		return errors.New("at least one --target is required")
+	}
+
+	evalTargets := make([]evalharness.Target, 0, len(names))
+	for _, name := range names {
+		provider, model, ok := strings.Cut(name, "/")
+		if !ok || model == "" {
+			return fmt.Errorf("target %q must be provider/model", name)
+		}
+
+		var caller evalharness.Caller
+		if *mode == "replay" {
+			caller = cassette.Replay(name)
+		} else {
+			client, err := newClient(ctx, provider, model, *anthropicAPIKey, *googleAPIKey)
+			if err != nil {
+				return fmt.Errorf("target %s: %w", name, err)
+			}
+			caller = evalharness.Live(client)
+			if cassette != nil {
+				caller = cassette.Record(name, caller)
+			}
+		}
+		evalTargets = append(evalTargets, evalharness.Target{Name: name, Model: model, Caller: caller})
+	}
+
+	log.InfoContext(ctx, "running eval",
+		"cases", len(cases),
+		"targets", len(evalTargets),
+		"variants", len(variants),
+		"mode", *mode,
+	)
+	report := evalharness.Report{
+		GeneratedAt: time.Now().UTC(),
+		Mode:        *mode,
+		Cases:       len(cases),
+		BatchSize:   *batchSize,
+		Summaries:   evalharness.Run(ctx, cases, evalTargets, variants, *batchSize),
+	}
+
+	if *mode == "record" {
+		if err := writeFile(*cassettePath, cassette.Save); err != nil {
+			return fmt.Errorf("writing cassette: %w", err)
+		}
+		log.InfoContext(ctx, "saved cassette", "path", *cassettePath)
+	}
+	if err := writeFile(*out+".json", report.WriteJSON); err != nil {
+		return fmt.Errorf("writing JSON report: %w", err)
+	}
+	if err := writeFile(*out+".md", report.WriteMarkdown); err != nil {
+		return fmt.Errorf("writing Markdown report: %w", err)
+	}
+
+	for _, s := range report.Summaries {
+		log.InfoContext(ctx, "eval result",
+			"target", s.Target,
+			"variant", s.Variant,
+			"exact", fmt.Sprintf("%.0f%%", 100*s.ExactRate()),
+			"fuzzy", fmt.Sprintf("%.0f%%", 100*s.FuzzyRate()),
+			"valid_json", fmt.Sprintf("%.0f%%", 100*s.JSONValidRate()),
+			"latency_p50", s.LatencyP50.Round(time.Millisecond),
+		)
+	}
+	log.InfoContext(ctx, "wrote report", "markdown", *out+".md", "json", *out+".json")
+	return nil
+}
+
+// loadVariants returns the production prompt followed by one variant per
+// prompt file, named after the file.
+func loadVariants(paths []string) ([]evalharness.Variant, error) {
+	production, err := translation.SystemPrompt()
+	if err != nil {
+		return nil, fmt.Errorf("building production prompt: %w", err)
+	}
+	variants := []evalharness.Variant{{Name: "production", System: production}}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading prompt variant: %w", err)
+		}
+		variants = append(variants, evalharness.Variant{Name: evalharness.VariantName(path), System: string(b)})
+	}
+	return variants, nil
+}
+
+func newClient(ctx context.Context, provider, model, anthropicAPIKey, googleAPIKey string) (llm.Client, error) {
+	switch provider {
+	case "anthropic":
+		if anthropicAPIKey == "" {
+			return nil, errors.New("anthropic-api-key is required for anthropic targets")
+		}
+		return anthropic.NewClient(anthropicAPIKey, anthropic.Model(model)), nil
+	case "google":
+		if googleAPIKey == "" {
+			return nil, errors.New("google-api-key is required for google targets")
+		}
+		client, err := google.NewClient(ctx, googleAPIKey, google.Model(model))
+		if err != nil {
+			return nil, fmt.Errorf("creating Google client: %w", err)
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+}
+
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
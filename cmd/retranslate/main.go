@@ -0,0 +1,160 @@
+// Command retranslate re-runs the most frequently seen cached names through the
+// currently configured model, so a model or prompt upgrade reaches the names
+// people actually see without waiting for the retention window to expire them.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/jusunglee/leagueofren/internal/anthropic"
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/db/postgres"
+	"github.com/jusunglee/leagueofren/internal/db/sqlite"
+	"github.com/jusunglee/leagueofren/internal/google"
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/jusunglee/leagueofren/internal/logger"
+	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/peterbourgon/ff/v4"
+	"github.com/peterbourgon/ff/v4/ffhelp"
+	"github.com/samber/lo"
+)
+
+func main() {
+	if err := mainE(); err != nil {
+		slog.Error("fatal", "error", err)
+		os.Exit(1)
+	}
+}
+
+func mainE() error {
+	_ = godotenv.Load()
+
+	fs := ff.NewFlagSet("leagueofren-retranslate")
+	var (
+		databaseURL     = fs.StringLong("database-url", "", "PostgreSQL connection URL or SQLite path")
+		llmProvider     = fs.StringEnumLong("llm-provider", "LLM provider", "anthropic", "google")
+		llmModel        = fs.StringLong("llm-model", "", "LLM model name")
+		anthropicAPIKey = fs.StringLong("anthropic-api-key", "", "Anthropic API key")
+		googleAPIKey    = fs.StringLong("google-api-key", "", "Google API key")
+		limit           = fs.IntLong("limit", 100, "Number of most-seen names to consider")
+		batchSize       = fs.IntLong("batch-size", 20, "Names per LLM call")
+		force           = fs.BoolLong("force", "Re-translate names even if already produced by the current model and prompt")
+		dryRun          = fs.BoolLong("dry-run", "Print the names that would be re-translated without calling the LLM")
+	)
+
+	if err := ff.Parse(fs, os.Args[1:], ff.WithEnvVars()); err != nil {
+		fmt.Printf("%s\n", ffhelp.Flags(fs))
+		return fmt.Errorf("parsing flags: %w", err)
+	}
+
+	if *databaseURL == "" {
+		return errors.New("database-url is required")
+	}
+	if *llmModel == "" {
+		return errors.New("llm-model is required")
+	}
+	if *limit <= 0 || *batchSize <= 0 {
+		return errors.New("limit and batch-size must be positive")
+	}
+
+	ctx := context.Background()
+	log := logger.New()
+
+	var client llm.Client
+	switch *llmProvider {
+	case "anthropic":
+		if *anthropicAPIKey == "" {
+			return errors.New("anthropic-api-key is required when using anthropic provider")
+		}
+		client = anthropic.NewClient(*anthropicAPIKey, anthropic.Model(*llmModel))
+	case "google":
+		if *googleAPIKey == "" {
+			return errors.New("google-api-key is required when using google provider")
+		}
+		var err error
+		client, err = google.NewClient(ctx, *googleAPIKey, google.Model(*llmModel))
+		if err != nil {
+			return fmt.Errorf("creating Google client: %w", err)
+		}
+	}
+
+	var repo db.Repository
+	if isSQLite(*databaseURL) {
+		sqliteRepo, err := sqlite.New(ctx, *databaseURL)
+		if err != nil {
+			return fmt.Errorf("opening SQLite database: %w", err)
+		}
+		repo = sqliteRepo
+	} else {
+		pgRepo, err := postgres.New(ctx, *databaseURL)
+		if err != nil {
+			return fmt.Errorf("creating PostgreSQL connection: %w", err)
+		}
+		repo = pgRepo
+	}
+	defer repo.Close()
+
+	translator := translation.NewTranslator(client, repo, *llmProvider, *llmModel, translation.WithLogger(log))
+
+	mostSeen, err := repo.ListMostSeenTranslations(ctx, int32(*limit))
+	if err != nil {
+		return fmt.Errorf("listing most-seen translations: %w", err)
+	}
+
+	targets := lo.Filter(mostSeen, func(t db.Translation, _ int) bool {
+		return *force || translator.IsStale(t)
+	})
+	log.InfoContext(ctx, "selected names for re-translation",
+		"considered", len(mostSeen),
+		"selected", len(targets),
+		"model", *llmModel,
+		"prompt_version", translation.PromptVersion,
+	)
+
+	if *dryRun {
+		for _, t := range targets {
+			fmt.Printf("%s\tlang=%s\tseen=%d\tmodel=%s\tprompt=%s\n", t.Username, t.TargetLanguage, t.SeenCount, t.Model, t.PromptVersion)
+		}
+		return nil
+	}
+
+	byLanguage := lo.GroupBy(targets, func(t db.Translation) string { return t.TargetLanguage })
+	var done, failed int
+	for _, language := range lo.Keys(byLanguage) {
+		names := lo.Map(byLanguage[language], func(t db.Translation, _ int) string { return t.Username })
+		for _, batch := range lo.Chunk(names, *batchSize) {
+			results, err := translator.Retranslate(ctx, batch, language)
+			if err != nil {
+				failed += len(batch)
+				log.ErrorContext(ctx, "re-translating batch", "names", batch, "target_language", language, "error", err)
+				continue
+			}
+			done += len(results)
+			for _, r := range results {
+				log.InfoContext(ctx, "re-translated", "name", r.Original, "translation", r.Translated, "target_language", language)
+			}
+		}
+	}
+
+	log.InfoContext(ctx, "re-translation complete", "translated", done, "failed", failed)
+	if failed > 0 {
+		return fmt.Errorf("%d names failed to re-translate", failed)
+	}
+	return nil
+}
+
+func isSQLite(url string) bool {
+	if strings.HasPrefix(url, "sqlite://") {
+		return true
+	}
+	if strings.HasSuffix(url, ".db") || strings.HasSuffix(url, ".sqlite") || strings.HasSuffix(url, ".sqlite3") {
+		return true
+	}
+	return false
+}
@@ -17,13 +17,16 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/jusunglee/leagueofren/internal/anthropic"
 	"github.com/jusunglee/leagueofren/internal/db/postgres"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
 	"github.com/jusunglee/leagueofren/internal/google"
+	"github.com/jusunglee/leagueofren/internal/jobs"
 	"github.com/jusunglee/leagueofren/internal/llm"
 	"github.com/jusunglee/leagueofren/internal/logger"
 	"github.com/jusunglee/leagueofren/internal/metrics"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/translation"
 	"github.com/jusunglee/leagueofren/internal/web"
+	"github.com/jusunglee/leagueofren/internal/web/stream"
 	"github.com/peterbourgon/ff/v4"
 	"github.com/peterbourgon/ff/v4/ffhelp"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -57,9 +60,17 @@ func mainE() error {
 		anthropicAPIKey = fs_.StringLong("anthropic-api-key", "", "Anthropic API key")
 		googleAPIKey    = fs_.StringLong("google-api-key", "", "Google API key")
 		allowedOrigins  = fs_.StringLong("allowed-origins", "", "Comma-separated list of allowed CORS origins")
+		siteURL         = fs_.StringLong("site-url", "https://leagueofren.com", "Public URL of the website, for links in the feeds")
 		rateLimitMax    = fs_.IntLong("rate-limit-max", 60, "Max requests per rate limit window per IP")
 		rateLimitWindow = fs_.IntLong("rate-limit-window", 60, "Rate limit window in seconds")
 		maxVotesPerIP   = fs_.IntLong("max-votes-per-ip", 20, "Max votes allowed per IP per day")
+		promotionMargin = fs_.IntLong("promotion-margin", 5, "Net votes an alternative must lead its translation by to be promoted")
+		promotionHold   = fs_.DurationLong("promotion-hold", 24*time.Hour, "How long an alternative must keep its lead before it replaces the translation")
+		adminAuth       = fs_.StringEnumLong("admin-auth", "How /api/v1/admin requests authenticate (none, basic, api-key); none disables the admin API", "none", "basic", "api-key")
+		adminPassword   = fs_.StringLong("admin-password", "", "Password for user admin when admin-auth is basic")
+		adminAPIKey     = fs_.StringLong("admin-api-key", "", "X-API-Key value when admin-auth is api-key")
+		banSecret       = fs_.StringLong("ban-secret", "", "Secret for the keyed IP hashes vote bans are stored against; required when the admin API is enabled")
+		stalePolicy     = fs_.StringEnumLong("stale-translation-policy", "How to handle cached translations from an older model or prompt (refresh, serve, bypass)", "refresh", "serve", "bypass")
 	)
 
 	if err := ff.Parse(fs_, os.Args[1:], ff.WithEnvVars()); err != nil {
@@ -76,6 +87,18 @@ func mainE() error {
 	if *llmModel == "" {
 		return errors.New("llm-model is required")
 	}
+	if *promotionMargin < 1 {
+		return errors.New("promotion-margin must be at least 1")
+	}
+	if *adminAuth == "basic" && *adminPassword == "" {
+		return errors.New("admin-password is required when admin-auth is basic")
+	}
+	if *adminAuth == "api-key" && *adminAPIKey == "" {
+		return errors.New("admin-api-key is required when admin-auth is api-key")
+	}
+	if *adminAuth != "none" && *banSecret == "" {
+		return errors.New("ban-secret is required when the admin API is enabled")
+	}
 
 	log := logger.New()
 
@@ -124,8 +147,33 @@ func mainE() error {
 		}
 	}()
 
+	keyed, err := web.BackfillUsernameKeys(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("backfilling username keys: %w", err)
+	}
+	if keyed > 0 {
+		log.InfoContext(ctx, "backfilled public translation username keys", "count", keyed)
+	}
+	romanized, err := web.BackfillSearchKeys(ctx, repo)
+	if err != nil {
+		return fmt.Errorf("backfilling search keys: %w", err)
+	}
+	if romanized > 0 {
+		log.InfoContext(ctx, "backfilled public translation search keys", "count", romanized)
+	}
+	scored, err := repo.BackfillHotScores(ctx)
+	if err != nil {
+		return fmt.Errorf("backfilling hot scores: %w", err)
+	}
+	if scored > 0 {
+		log.InfoContext(ctx, "backfilled public translation hot scores", "count", scored)
+	}
+
 	riotClient := riot.NewDirectClient(*riotAPIKey)
-	translator := translation.NewTranslator(llmClient, repo, *llmProvider, *llmModel)
+	translator := translation.NewTranslator(llmClient, repo, *llmProvider, *llmModel,
+		translation.WithStalePolicy(translation.StalePolicy(*stalePolicy)),
+		translation.WithLogger(log),
+	)
 
 	// River job queue setup
 	riverDriver := riverpgxv5.New(repo.Pool())
@@ -142,12 +190,30 @@ func mainE() error {
 
 	workers := river.NewWorkers()
 	river.AddWorker(workers, web.NewTranslateWorker(repo, riotClient, translator, log))
+	river.AddWorker(workers, web.NewPromoteWorker(repo, log, int32(*promotionMargin), *promotionHold))
+	river.AddWorker(workers, web.NewPruneStreamEventsWorker(repo, log))
 
 	riverClient, err := river.NewClient(riverDriver, &river.Config{
 		Logger: log,
 		Queues: map[string]river.QueueConfig{
 			river.QueueDefault: {MaxWorkers: 2},
 		},
+		PeriodicJobs: []*river.PeriodicJob{
+			river.NewPeriodicJob(
+				river.PeriodicInterval(10*time.Minute),
+				func() (river.JobArgs, *river.InsertOpts) {
+					return jobs.PromoteAlternativesArgs{}, nil
+				},
+				&river.PeriodicJobOpts{RunOnStart: true},
+			),
+			river.NewPeriodicJob(
+				river.PeriodicInterval(time.Hour),
+				func() (river.JobArgs, *river.InsertOpts) {
+					return jobs.PruneStreamEventsArgs{}, nil
+				},
+				&river.PeriodicJobOpts{RunOnStart: true},
+			),
+		},
 		Workers: workers,
 	})
 	if err != nil {
@@ -167,11 +233,24 @@ func mainE() error {
 		}
 	}
 
+	dict, err := dictionary.Bundled()
+	if err != nil {
+		return fmt.Errorf("loading bundled dictionary: %w", err)
+	}
+
+	hub := stream.NewHub(repo.Pool(), repo, log)
+	go hub.Run(ctx)
+
 	router := web.NewRouter(repo, log, riotClient, riverClient, origins, web.RateLimitConfig{
 		Max:           *rateLimitMax,
 		WindowSeconds: *rateLimitWindow,
 		MaxVotesPerIP: *maxVotesPerIP,
-	})
+	}, web.AdminConfig{
+		Auth:      *adminAuth,
+		Password:  *adminPassword,
+		APIKey:    *adminAPIKey,
+		BanSecret: *banSecret,
+	}, dict, hub, *siteURL)
 	apiHandler := router.Handler()
 
 	// Serve API routes first, fall back to embedded static files for the SPA
@@ -184,8 +263,8 @@ func mainE() error {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// API routes go to the router
-		if strings.HasPrefix(r.URL.Path, "/api/") {
+		// API and feed routes go to the router
+		if strings.HasPrefix(r.URL.Path, "/api/") || strings.HasPrefix(r.URL.Path, "/feeds/") {
 			apiHandler.ServeHTTP(w, r)
 			return
 		}
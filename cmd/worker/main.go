@@ -189,7 +189,8 @@ func runRefresh(ctx context.Context, repo db.Repository, riotClient *riot.Direct
 		}
 		metrics.RiotAPICallsTotal.WithLabelValues("ranked", "success").Inc()
 
-		rank := extractSoloQueueRank(entries)
+		solo, ranked := soloQueueEntry(entries)
+		rank := solo.Tier
 
 		time.Sleep(100 * time.Millisecond)
 
@@ -222,6 +223,17 @@ func runRefresh(ctx context.Context, repo db.Repository, riotClient *riot.Direct
 			log.ErrorContext(ctx, "updating player stats", "username", player.Username, "error", err)
 			continue
 		}
+		if ranked {
+			_, err = repo.RecordPlayerRank(ctx, db.RecordPlayerRankParams{
+				PlayerUsername: player.Username,
+				Tier:           solo.Tier,
+				Division:       solo.Rank,
+				LeaguePoints:   int32(solo.LeaguePoints),
+			})
+			if err != nil {
+				log.ErrorContext(ctx, "recording player rank", "username", player.Username, "error", err)
+			}
+		}
 
 		log.InfoContext(ctx, "updated player", "username", player.Username, "rank", rank, "champions", champNames)
 		time.Sleep(100 * time.Millisecond)
@@ -230,13 +242,15 @@ func runRefresh(ctx context.Context, repo db.Repository, riotClient *riot.Direct
 	log.InfoContext(ctx, "player refresh complete")
 }
 
-func extractSoloQueueRank(entries []riot.LeagueEntry) string {
+// soloQueueEntry returns the player's ranked solo queue entry, if they have
+// one.
+func soloQueueEntry(entries []riot.LeagueEntry) (riot.LeagueEntry, bool) {
 	for _, e := range entries {
 		if e.QueueType == "RANKED_SOLO_5x5" {
-			return e.Tier
+			return e, true
 		}
 	}
-	return ""
+	return riot.LeagueEntry{}, false
 }
 
 type dataDragonResponse struct {
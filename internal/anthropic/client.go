@@ -35,7 +35,9 @@ func NewClient(apiKey string, model Model) *Client {
 	}
 }
 
-func (c *Client) Complete(ctx context.Context, system, prompt string) (string, error) {
+// Complete ignores opts: Claude follows the system prompt's output format
+// without constrained decoding.
+func (c *Client) Complete(ctx context.Context, system, prompt string, _ ...llm.Option) (llm.Response, error) {
 	message, err := c.client.Messages.New(ctx, anthropic.MessageNewParams{
 		Model:     c.model,
 		MaxTokens: 1024,
@@ -47,11 +49,16 @@ func (c *Client) Complete(ctx context.Context, system, prompt string) (string, e
 		},
 	})
 	if err != nil {
-		return "", fmt.Errorf("anthropic API call failed: %w", err)
+		return llm.Response{}, fmt.Errorf("anthropic API call failed: %w", err)
+	}
+
+	usage := llm.Usage{
+		InputTokens:  message.Usage.InputTokens,
+		OutputTokens: message.Usage.OutputTokens,
 	}
 
 	if len(message.Content) == 0 {
-		return "", fmt.Errorf("empty response from anthropic")
+		return llm.Response{Usage: usage}, fmt.Errorf("empty response from anthropic")
 	}
 
 	var text string
@@ -63,8 +70,8 @@ func (c *Client) Complete(ctx context.Context, system, prompt string) (string, e
 	}
 
 	if text == "" {
-		return "", fmt.Errorf("no text content in response")
+		return llm.Response{Usage: usage}, fmt.Errorf("no text content in response")
 	}
 
-	return llm.StripMarkdownCodeBlocks(text), nil
+	return llm.Response{Text: llm.StripMarkdownCodeBlocks(text), Usage: usage}, nil
 }
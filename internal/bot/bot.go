@@ -9,13 +9,14 @@ import (
 	"strings"
 	"sync"
 	"time"
-	"unicode"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
 	"github.com/jusunglee/leagueofren/internal/metrics"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
 	"github.com/samber/lo"
 	"golang.org/x/sync/errgroup"
 )
@@ -31,6 +32,12 @@ type Config struct {
 	GuildID                      string
 	JobBufferSize                int
 	WebsiteURL                   string
+	// ServerMonthlyBudgetUSD caps each server's LLM spend per calendar month;
+	// 0 means unlimited. ServerBudgetsUSD overrides it for individual servers.
+	ServerMonthlyBudgetUSD float64
+	ServerBudgetsUSD       map[string]float64
+	// Dictionary answers the Breakdown button on translation messages.
+	Dictionary *dictionary.Dictionary
 }
 
 type Bot struct {
@@ -43,6 +50,9 @@ type Bot struct {
 	config        Config
 	rateLimiter   *RateLimiter
 	websiteClient *WebsiteClient
+	budgetNotices budgetNotices
+
+	pendingSubmissions pendingSubmissions
 }
 
 func New(
@@ -125,6 +135,11 @@ func (b *Bot) Run(ctx context.Context, cancel context.CancelCauseFunc) error {
 	wg.Add(1)
 	go b.runCleaner(ctx, &wg)
 
+	if b.websiteClient.Enabled() {
+		wg.Add(1)
+		go b.runSubmissionPoller(ctx, &wg)
+	}
+
 	b.log.InfoContext(ctx, "bot is running, press Ctrl+C to stop")
 
 	<-ctx.Done()
@@ -286,6 +301,25 @@ var commands = []*discordgo.ApplicationCommand{
 		Name:        "list",
 		Description: "List all subscriptions in this channel",
 	},
+	{
+		Name:        "config",
+		Description: "Show or change this server's translation settings",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "language",
+				Description: "Language to translate names into",
+				Required:    false,
+				Choices:     buildLanguageChoices(),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "scripts",
+				Description: "Comma-separated scripts to translate (default korean,chinese,japanese; e.g. korean,thai), or all",
+				Required:    false,
+			},
+		},
+	},
 }
 
 type handlerResult struct {
@@ -316,8 +350,8 @@ func (b *Bot) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate
 	var result handlerResult
 	cmd := i.ApplicationCommandData().Name
 
-	// Check permissions for subscribe/unsubscribe commands
-	if cmd == "subscribe" || cmd == "unsubscribe" {
+	// Check permissions for commands that change the server's setup
+	if cmd == "subscribe" || cmd == "unsubscribe" || cmd == "config" {
 		if i.Member == nil {
 			result = handlerResult{
 				Response: "❌ This command can only be used in a server",
@@ -356,6 +390,8 @@ func (b *Bot) handleCommand(s *discordgo.Session, i *discordgo.InteractionCreate
 		result = b.handleUnsubscribe(i)
 	case "list":
 		result = b.handleListForChannel(i)
+	case "config":
+		result = b.handleConfig(i)
 	}
 
 	cmdResult := "success"
@@ -402,6 +438,12 @@ func (b *Bot) handleComponent(s *discordgo.Session, i *discordgo.InteractionCrea
 			},
 		})
 
+	case breakdownButtonID:
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: b.handleBreakdown(ctx, i),
+		})
+
 	case "feedback_fix":
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseModal,
@@ -673,10 +715,27 @@ type sendMessageJob struct {
 	channelID      string
 	gameID         int64
 	region         string
+	language       string // target language of translations
 }
 
-func (b *Bot) produceForServer(ctx context.Context, subs []db.Subscription) ([]sendMessageJob, error) {
-	var jobs []sendMessageJob
+// pendingGame is a newly seen game whose foreign names still need translating.
+// Games are collected across every server first so that one produce cycle
+// shares LLM calls instead of paying for a round-trip per game.
+type pendingGame struct {
+	sub     db.Subscription
+	gameID  int64
+	names   []string
+	riotIDs map[string]string // game name -> full Riot ID (name#tag)
+	// language is the server's target language, set once per server by
+	// produceTranslationMessages.
+	language string
+}
+
+// produceForServer collects the games subs are in, keeping players whose names
+// are written in one of scripts (any registered script if empty).
+func (b *Bot) produceForServer(ctx context.Context, subs []db.Subscription, scripts []string) ([]pendingGame, error) {
+	var mu sync.Mutex
+	var games []pendingGame
 	var eg errgroup.Group
 	for _, sub := range subs {
 		eg.Go(func() error {
@@ -716,7 +775,7 @@ func (b *Bot) produceForServer(ctx context.Context, subs []db.Subscription) ([]s
 			var names []string
 			riotIDs := make(map[string]string) // game name -> full Riot ID
 			for _, p := range game.Participants {
-				if !containsForeignCharacters(p.GameName) {
+				if !transliteration.ContainsScript(p.GameName, scripts) {
 					continue
 				}
 
@@ -739,31 +798,117 @@ func (b *Bot) produceForServer(ctx context.Context, subs []db.Subscription) ([]s
 				return nil
 			}
 
-			translations, err := b.translator.TranslateUsernames(ctx, names)
-			if err != nil {
-				return nil
-			}
-			metrics.BotNamesTranslated.Add(float64(len(translations)))
-
-			jobs = append(jobs, sendMessageJob{
-				username:       sub.LolUsername,
-				translations:   translations,
-				riotIDs:        riotIDs,
-				channelID:      sub.DiscordChannelID,
-				subscriptionID: sub.ID,
-				gameID:         game.GameID,
-				region:         sub.Region,
+			mu.Lock()
+			games = append(games, pendingGame{
+				sub:     sub,
+				gameID:  game.GameID,
+				names:   names,
+				riotIDs: riotIDs,
 			})
+			mu.Unlock()
 			return nil
 		})
 	}
 
 	if err := eg.Wait(); err != nil {
-		// Return jobs for best effort
-		return jobs, fmt.Errorf("producing for all channels in server: %w", err)
+		// Return games for best effort
+		return games, fmt.Errorf("producing for all channels in server: %w", err)
+	}
+
+	return games, nil
+}
+
+// translatePendingGames translates the names of every pending game with one
+// TranslateUsernames call per target language, which dedupes and batches them,
+// then fans the results back out into one job per game. Games on servers that
+// have spent their monthly LLM budget only get cached translations.
+func (b *Bot) translatePendingGames(ctx context.Context, games []pendingGame) []sendMessageJob {
+	if len(games) == 0 {
+		return nil
+	}
+
+	overBudget := b.overBudgetServers(ctx, games)
+	byLanguage := lo.GroupBy(games, func(g pendingGame) string { return g.language })
+
+	// language -> name -> translation
+	translated := make(map[string]map[string]translation.Translation, len(byLanguage))
+	for language, languageGames := range byLanguage {
+		capped, live := lo.FilterReject(languageGames, func(g pendingGame, _ int) bool {
+			return overBudget[g.sub.ServerID]
+		})
+
+		// Attribute each name's LLM usage to the servers whose games need it.
+		requesters := make(map[string][]string)
+		for _, g := range live {
+			for _, name := range g.names {
+				if !lo.Contains(requesters[name], g.sub.ServerID) {
+					requesters[name] = append(requesters[name], g.sub.ServerID)
+				}
+			}
+		}
+		names := lo.Uniq(lo.FlatMap(live, func(g pendingGame, _ int) []string {
+			return g.names
+		}))
+		var translations []translation.Translation
+		if len(names) > 0 {
+			var err error
+			translations, err = b.translator.TranslateUsernames(translation.WithRequesters(ctx, requesters), names, language)
+			if err != nil {
+				// Best effort: games whose names all made it are still sent.
+				b.log.WarnContext(ctx, "failed to translate some names", "names", len(names), "translated", len(translations), "target_language", language, "error", err)
+			}
+		}
+
+		cappedNames, _ := lo.Difference(lo.Uniq(lo.FlatMap(capped, func(g pendingGame, _ int) []string {
+			return g.names
+		})), names)
+		if len(cappedNames) > 0 {
+			cached, err := b.translator.CachedTranslations(ctx, cappedNames, language)
+			if err != nil {
+				b.log.WarnContext(ctx, "failed to look up cached translations for over-budget servers", "names", len(cappedNames), "target_language", language, "error", err)
+			}
+			translations = append(translations, cached...)
+		}
+
+		translated[language] = lo.KeyBy(translations, func(t translation.Translation) string {
+			return t.Original
+		})
+	}
+
+	for _, g := range lo.UniqBy(games, func(g pendingGame) string { return g.sub.ServerID }) {
+		if overBudget[g.sub.ServerID] {
+			b.notifyBudgetExhausted(ctx, g.sub)
+		}
 	}
 
-	return jobs, nil
+	var jobs []sendMessageJob
+	for _, g := range games {
+		byName := translated[g.language]
+		gameTranslations := make([]translation.Translation, 0, len(g.names))
+		for _, name := range g.names {
+			if t, ok := byName[name]; ok {
+				gameTranslations = append(gameTranslations, t)
+			}
+		}
+		// Don't best effort within a game because missing some translations seems sloppy and is bad UX.
+		if len(gameTranslations) != len(g.names) {
+			b.log.WarnContext(ctx, "skipping game with untranslated names", "subscription_id", g.sub.ID, "game_id", g.gameID)
+			continue
+		}
+		metrics.BotNamesTranslated.Add(float64(len(gameTranslations)))
+
+		jobs = append(jobs, sendMessageJob{
+			username:       g.sub.LolUsername,
+			translations:   gameTranslations,
+			riotIDs:        g.riotIDs,
+			channelID:      g.sub.DiscordChannelID,
+			subscriptionID: g.sub.ID,
+			gameID:         g.gameID,
+			region:         g.sub.Region,
+			language:       g.language,
+		})
+	}
+	return jobs
 }
 
 func (b *Bot) consumeTranslationMessages(ctx context.Context, job sendMessageJob) error {
@@ -815,9 +960,18 @@ func (b *Bot) consumeTranslationMessages(ctx context.Context, job sendMessageJob
 
 	// Best-effort: submit usernames to the companion website for server-side translation
 	if b.websiteClient.Enabled() {
-		if err := b.websiteClient.SubmitTranslations(ctx, job.translations, job.riotIDs, job.region); err != nil {
+		submissions, err := b.websiteClient.SubmitTranslations(ctx, job.translations, job.riotIDs, job.region)
+		if err != nil {
 			b.log.WarnContext(ctx, "failed to submit translations to website", "error", err)
 		}
+		if len(submissions) > 0 {
+			queued := lo.CountBy(submissions, func(s WebsiteSubmission) bool { return s.ID != 0 })
+			b.log.InfoContext(ctx, "submitted usernames to website",
+				"queued", queued,
+				"already_translated", len(submissions)-queued,
+			)
+			b.pendingSubmissions.add(submissions, time.Now())
+		}
 	}
 
 	return nil
@@ -838,13 +992,17 @@ func (b *Bot) produceTranslationMessages(ctx context.Context) ([]sendMessageJob,
 	var eg errgroup.Group
 	eg.SetLimit(20)
 	var mu sync.Mutex
-	var jobs []sendMessageJob
+	var games []pendingGame
 
 	for server, subs := range servers {
 		eg.Go(func() error {
-			serverJobs, err := b.produceForServer(ctx, subs)
+			cfg := b.serverConfig(ctx, server)
+			serverGames, err := b.produceForServer(ctx, subs, cfg.scripts)
+			for i := range serverGames {
+				serverGames[i].language = cfg.language
+			}
 			mu.Lock()
-			jobs = append(jobs, serverJobs...)
+			games = append(games, serverGames...)
 			mu.Unlock()
 			// Best effort
 			if err != nil {
@@ -857,20 +1015,7 @@ func (b *Bot) produceTranslationMessages(ctx context.Context) ([]sendMessageJob,
 		err = fmt.Errorf("producing translation messages: %w", err)
 	}
 
-	return jobs, err
-}
-
-// TODO: Only supports korean and chinese so far, make the language a user-passed flag.
-func containsForeignCharacters(s string) bool {
-	for _, r := range s {
-		if unicode.Is(unicode.Han, r) {
-			return true
-		}
-		if unicode.Is(unicode.Hangul, r) {
-			return true
-		}
-	}
-	return false
+	return b.translatePendingGames(ctx, games), err
 }
 
 func (b *Bot) cleanupOldData(ctx context.Context) error {
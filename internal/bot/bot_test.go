@@ -2,14 +2,20 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -171,13 +177,13 @@ func (m *MockRepository) CreateTranslation(ctx context.Context, arg db.CreateTra
 	return ret.Get(0).(db.Translation), ret.Error(1)
 }
 
-func (m *MockRepository) GetTranslation(ctx context.Context, username string) (db.Translation, error) {
-	ret := m.Called(ctx, username)
+func (m *MockRepository) GetTranslation(ctx context.Context, arg db.GetTranslationParams) (db.Translation, error) {
+	ret := m.Called(ctx, arg)
 	return ret.Get(0).(db.Translation), ret.Error(1)
 }
 
-func (m *MockRepository) GetTranslations(ctx context.Context, usernames []string) ([]db.Translation, error) {
-	ret := m.Called(ctx, usernames)
+func (m *MockRepository) GetTranslations(ctx context.Context, arg db.GetTranslationsParams) ([]db.Translation, error) {
+	ret := m.Called(ctx, arg)
 	return ret.Get(0).([]db.Translation), ret.Error(1)
 }
 
@@ -191,6 +197,16 @@ func (m *MockRepository) CreateTranslationToEval(ctx context.Context, arg db.Cre
 	return ret.Error(0)
 }
 
+func (m *MockRepository) IncrementTranslationSeenCount(ctx context.Context, arg db.IncrementTranslationSeenCountParams) error {
+	ret := m.Called(ctx, arg)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) ListMostSeenTranslations(ctx context.Context, limit int32) ([]db.Translation, error) {
+	ret := m.Called(ctx, limit)
+	return ret.Get(0).([]db.Translation), ret.Error(1)
+}
+
 func (m *MockRepository) CreateFeedback(ctx context.Context, arg db.CreateFeedbackParams) (db.Feedback, error) {
 	ret := m.Called(ctx, arg)
 	return ret.Get(0).(db.Feedback), ret.Error(1)
@@ -236,6 +252,26 @@ func (m *MockRepository) DeleteExpiredGameCache(ctx context.Context) error {
 	return ret.Error(0)
 }
 
+func (m *MockRepository) CreateLLMUsage(ctx context.Context, arg db.CreateLLMUsageParams) error {
+	ret := m.Called(ctx, arg)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) SumLLMUsageCostByServerSince(ctx context.Context, serverID string, since time.Time) (int64, error) {
+	ret := m.Called(ctx, serverID, since)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) GetServerConfig(ctx context.Context, serverID string) (db.ServerConfig, error) {
+	ret := m.Called(ctx, serverID)
+	return ret.Get(0).(db.ServerConfig), ret.Error(1)
+}
+
+func (m *MockRepository) UpsertServerConfig(ctx context.Context, arg db.UpsertServerConfigParams) (db.ServerConfig, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(db.ServerConfig), ret.Error(1)
+}
+
 func (m *MockRepository) UpsertPlayer(ctx context.Context, arg db.UpsertPlayerParams) (db.Player, error) {
 	ret := m.Called(ctx, arg)
 	return ret.Get(0).(db.Player), ret.Error(1)
@@ -256,6 +292,21 @@ func (m *MockRepository) UpdatePlayerStats(ctx context.Context, arg db.UpdatePla
 	return ret.Error(0)
 }
 
+func (m *MockRepository) RecordPlayerRank(ctx context.Context, arg db.RecordPlayerRankParams) (int64, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) ListPlayerRankHistory(ctx context.Context, arg db.ListPlayerRankHistoryParams) ([]db.PlayerRank, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.PlayerRank), ret.Error(1)
+}
+
+func (m *MockRepository) GetPeakPlayerRank(ctx context.Context, playerUsername string) (db.PlayerRank, error) {
+	ret := m.Called(ctx, playerUsername)
+	return ret.Get(0).(db.PlayerRank), ret.Error(1)
+}
+
 func (m *MockRepository) UpsertPublicTranslation(ctx context.Context, arg db.UpsertPublicTranslationParams) (db.PublicTranslation, error) {
 	ret := m.Called(ctx, arg)
 	return ret.Get(0).(db.PublicTranslation), ret.Error(1)
@@ -271,21 +322,101 @@ func (m *MockRepository) GetPublicTranslationByUsername(ctx context.Context, use
 	return ret.Get(0).(db.PublicTranslation), ret.Error(1)
 }
 
+func (m *MockRepository) GetPublicTranslationByUsernameKey(ctx context.Context, usernameKey string) (db.PublicTranslation, error) {
+	ret := m.Called(ctx, usernameKey)
+	return ret.Get(0).(db.PublicTranslation), ret.Error(1)
+}
+
 func (m *MockRepository) ListPublicTranslationsNew(ctx context.Context, arg db.ListPublicTranslationsNewParams) ([]db.PublicTranslation, error) {
 	ret := m.Called(ctx, arg)
 	return ret.Get(0).([]db.PublicTranslation), ret.Error(1)
 }
 
+func (m *MockRepository) ListPublicTranslationsBest(ctx context.Context, arg db.ListPublicTranslationsBestParams) ([]db.ListPublicTranslationsBestRow, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.ListPublicTranslationsBestRow), ret.Error(1)
+}
+
+func (m *MockRepository) ListPublicTranslationsControversial(ctx context.Context, arg db.ListPublicTranslationsControversialParams) ([]db.ListPublicTranslationsControversialRow, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.ListPublicTranslationsControversialRow), ret.Error(1)
+}
+
+func (m *MockRepository) ListPublicTranslationsHot(ctx context.Context, arg db.ListPublicTranslationsHotParams) ([]db.ListPublicTranslationsHotRow, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.ListPublicTranslationsHotRow), ret.Error(1)
+}
+
 func (m *MockRepository) ListPublicTranslationsTop(ctx context.Context, arg db.ListPublicTranslationsTopParams) ([]db.PublicTranslation, error) {
 	ret := m.Called(ctx, arg)
 	return ret.Get(0).([]db.PublicTranslation), ret.Error(1)
 }
 
+func (m *MockRepository) ListTopVotedPublicTranslations(ctx context.Context, arg db.ListTopVotedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.PublicTranslation), ret.Error(1)
+}
+
 func (m *MockRepository) CountPublicTranslations(ctx context.Context, arg db.CountPublicTranslationsParams) (int64, error) {
 	ret := m.Called(ctx, arg)
 	return ret.Get(0).(int64), ret.Error(1)
 }
 
+func (m *MockRepository) ListUnkeyedPublicTranslations(ctx context.Context, arg db.ListUnkeyedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.PublicTranslation), ret.Error(1)
+}
+
+func (m *MockRepository) SetPublicTranslationUsernameKey(ctx context.Context, arg db.SetPublicTranslationUsernameKeyParams) error {
+	ret := m.Called(ctx, arg)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) MergePublicTranslation(ctx context.Context, arg db.MergePublicTranslationParams) error {
+	ret := m.Called(ctx, arg)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) ListUnromanizedPublicTranslations(ctx context.Context, arg db.ListUnromanizedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.PublicTranslation), ret.Error(1)
+}
+
+func (m *MockRepository) SetPublicTranslationRomanized(ctx context.Context, arg db.SetPublicTranslationRomanizedParams) error {
+	ret := m.Called(ctx, arg)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) BackfillHotScores(ctx context.Context) (int64, error) {
+	ret := m.Called(ctx)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) SearchPublicTranslations(ctx context.Context, arg db.SearchPublicTranslationsParams) ([]db.SearchPublicTranslationsRow, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.SearchPublicTranslationsRow), ret.Error(1)
+}
+
+func (m *MockRepository) CountSearchPublicTranslations(ctx context.Context, arg db.CountSearchPublicTranslationsParams) (int64, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) UpdatePublicTranslation(ctx context.Context, arg db.UpdatePublicTranslationParams) (int64, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) SetPublicTranslationHidden(ctx context.Context, arg db.SetPublicTranslationHiddenParams) (int64, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) DeletePublicTranslation(ctx context.Context, id int64) (int64, error) {
+	ret := m.Called(ctx, id)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
 func (m *MockRepository) IncrementUpvotes(ctx context.Context, id int64) error {
 	ret := m.Called(ctx, id)
 	return ret.Error(0)
@@ -326,6 +457,76 @@ func (m *MockRepository) CountVotesByIP(ctx context.Context, ipHash string) (int
 	return ret.Get(0).(int64), ret.Error(1)
 }
 
+func (m *MockRepository) IsVoteBanned(ctx context.Context, arg db.IsVoteBannedParams) (bool, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(bool), ret.Error(1)
+}
+
+func (m *MockRepository) CreateTranslationAlternative(ctx context.Context, arg db.CreateTranslationAlternativeParams) (db.TranslationAlternative, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(db.TranslationAlternative), ret.Error(1)
+}
+
+func (m *MockRepository) GetTranslationAlternative(ctx context.Context, id int64) (db.TranslationAlternative, error) {
+	ret := m.Called(ctx, id)
+	return ret.Get(0).(db.TranslationAlternative), ret.Error(1)
+}
+
+func (m *MockRepository) ListTranslationAlternatives(ctx context.Context, translationID int64) ([]db.TranslationAlternative, error) {
+	ret := m.Called(ctx, translationID)
+	return ret.Get(0).([]db.TranslationAlternative), ret.Error(1)
+}
+
+func (m *MockRepository) AdjustTranslationAlternativeVotes(ctx context.Context, arg db.AdjustTranslationAlternativeVotesParams) (db.TranslationAlternative, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(db.TranslationAlternative), ret.Error(1)
+}
+
+func (m *MockRepository) UpsertAlternativeVote(ctx context.Context, arg db.UpsertAlternativeVoteParams) (db.AlternativeVote, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(db.AlternativeVote), ret.Error(1)
+}
+
+func (m *MockRepository) GetAlternativeVote(ctx context.Context, arg db.GetAlternativeVoteParams) (db.AlternativeVote, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(db.AlternativeVote), ret.Error(1)
+}
+
+func (m *MockRepository) DeleteAlternativeVote(ctx context.Context, arg db.DeleteAlternativeVoteParams) (int64, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) UpdateAlternativeLeads(ctx context.Context, margin int32) (int64, error) {
+	ret := m.Called(ctx, margin)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) ListPromotableAlternatives(ctx context.Context, leadingSince time.Time) ([]db.TranslationAlternative, error) {
+	ret := m.Called(ctx, leadingSince)
+	return ret.Get(0).([]db.TranslationAlternative), ret.Error(1)
+}
+
+func (m *MockRepository) PromoteTranslationAlternative(ctx context.Context, id int64) (int64, error) {
+	ret := m.Called(ctx, id)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) DeleteTranslationVotes(ctx context.Context, translationID int64) error {
+	ret := m.Called(ctx, translationID)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) CopyAlternativeVotes(ctx context.Context, alternativeID int64) error {
+	ret := m.Called(ctx, alternativeID)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) ListTranslationRevisions(ctx context.Context, translationID int64) ([]db.TranslationRevision, error) {
+	ret := m.Called(ctx, translationID)
+	return ret.Get(0).([]db.TranslationRevision), ret.Error(1)
+}
+
 func (m *MockRepository) CreatePublicFeedback(ctx context.Context, arg db.CreatePublicFeedbackParams) (db.PublicFeedback, error) {
 	ret := m.Called(ctx, arg)
 	return ret.Get(0).(db.PublicFeedback), ret.Error(1)
@@ -336,7 +537,62 @@ func (m *MockRepository) ListPublicFeedback(ctx context.Context, arg db.ListPubl
 	return ret.Get(0).([]db.ListPublicFeedbackRow), ret.Error(1)
 }
 
-func (m *MockRepository) CountPublicFeedback(ctx context.Context) (int64, error) {
+func (m *MockRepository) CountPublicFeedback(ctx context.Context, resolved bool) (int64, error) {
+	ret := m.Called(ctx, resolved)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) ResolvePublicFeedback(ctx context.Context, id int64) (int64, error) {
+	ret := m.Called(ctx, id)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) CreateStreamEvent(ctx context.Context, arg db.CreateStreamEventParams) (db.StreamEvent, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(db.StreamEvent), ret.Error(1)
+}
+
+func (m *MockRepository) NotifyStreamEvent(ctx context.Context, payload string) error {
+	ret := m.Called(ctx, payload)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) ListStreamEventsAfter(ctx context.Context, arg db.ListStreamEventsAfterParams) ([]db.StreamEvent, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.StreamEvent), ret.Error(1)
+}
+
+func (m *MockRepository) DeleteOldStreamEvents(ctx context.Context, before time.Time) (int64, error) {
+	ret := m.Called(ctx, before)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) CreateVoteBan(ctx context.Context, arg db.CreateVoteBanParams) (db.VoteBan, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).(db.VoteBan), ret.Error(1)
+}
+
+func (m *MockRepository) ListVoteBans(ctx context.Context) ([]db.VoteBan, error) {
+	ret := m.Called(ctx)
+	return ret.Get(0).([]db.VoteBan), ret.Error(1)
+}
+
+func (m *MockRepository) DeleteVoteBan(ctx context.Context, id int64) (int64, error) {
+	ret := m.Called(ctx, id)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (m *MockRepository) CreateAdminAuditLog(ctx context.Context, arg db.CreateAdminAuditLogParams) error {
+	ret := m.Called(ctx, arg)
+	return ret.Error(0)
+}
+
+func (m *MockRepository) ListAdminAuditLog(ctx context.Context, arg db.ListAdminAuditLogParams) ([]db.AdminAuditLog, error) {
+	ret := m.Called(ctx, arg)
+	return ret.Get(0).([]db.AdminAuditLog), ret.Error(1)
+}
+
+func (m *MockRepository) CountAdminAuditLog(ctx context.Context) (int64, error) {
 	ret := m.Called(ctx)
 	return ret.Get(0).(int64), ret.Error(1)
 }
@@ -372,8 +628,13 @@ type MockTranslator struct {
 	mock.Mock
 }
 
-func (m *MockTranslator) TranslateUsernames(ctx context.Context, usernames []string) ([]translation.Translation, error) {
-	ret := m.Called(ctx, usernames)
+func (m *MockTranslator) TranslateUsernames(ctx context.Context, usernames []string, target string) ([]translation.Translation, error) {
+	ret := m.Called(ctx, usernames, target)
+	return ret.Get(0).([]translation.Translation), ret.Error(1)
+}
+
+func (m *MockTranslator) CachedTranslations(ctx context.Context, usernames []string, target string) ([]translation.Translation, error) {
+	ret := m.Called(ctx, usernames, target)
 	return ret.Get(0).([]translation.Translation), ret.Error(1)
 }
 
@@ -644,21 +905,45 @@ func TestProduceForServer(t *testing.T) {
 			return params.GameID.Int64 == 999 && params.SubscriptionID == 1
 		})).Return(db.Eval{}, db.ErrNoRows)
 
-		mockTranslator.On("TranslateUsernames", ctx, []string{"玩家2"}).
-			Return([]translation.Translation{
-				{Original: "玩家2", Translated: "Player 2"},
-			}, nil)
-
-		jobs, err := bot.produceForServer(ctx, subs)
+		games, err := bot.produceForServer(ctx, subs, nil)
 		require.NoError(t, err)
-		assert.Len(t, jobs, 1)
-		assert.Equal(t, "channel-123", jobs[0].channelID)
-		assert.Equal(t, int64(1), jobs[0].subscriptionID)
-		assert.Equal(t, int64(999), jobs[0].gameID)
+		require.Len(t, games, 1)
+		assert.Equal(t, "channel-123", games[0].sub.DiscordChannelID)
+		assert.Equal(t, int64(1), games[0].sub.ID)
+		assert.Equal(t, int64(999), games[0].gameID)
+		assert.Equal(t, []string{"玩家2"}, games[0].names)
+		assert.Equal(t, "玩家2#NA1", games[0].riotIDs["玩家2"])
 
 		mockRiot.AssertExpectations(t)
 		mockRepo.AssertExpectations(t)
-		mockTranslator.AssertExpectations(t)
+		// Translation happens once per cycle in translatePendingGames
+		mockTranslator.AssertNotCalled(t, "TranslateUsernames", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("only names in the server's scripts are kept", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRiot := new(MockRiotClient)
+		bot := newTestBot(new(MockLogger), new(MockDiscordSession), new(MockMessageServer), mockRepo, mockRiot, new(MockTranslator))
+
+		subs := []db.Subscription{{ID: 1, DiscordChannelID: "channel-123", ServerID: "server-456", LolUsername: "Player#NA1", Region: "NA"}}
+
+		mockRiot.On("GetAccountByRiotID", ctx, "Player", "NA1", "NA").
+			Return(riot.Account{PUUID: "puuid-123", GameName: "Player", TagLine: "NA1"}, nil)
+		mockRiot.On("GetActiveGame", ctx, "puuid-123", "NA").
+			Return(riot.ActiveGame{
+				GameID: 999,
+				Participants: []riot.Participant{
+					{GameName: "玩家2#NA1"},
+					{GameName: "สมชาย#TH2"},
+					{GameName: "Охотник#RU1"},
+				},
+			}, nil)
+		mockRepo.On("GetEvalByGameAndSubscription", ctx, mock.Anything).Return(db.Eval{}, db.ErrNoRows)
+
+		games, err := bot.produceForServer(ctx, subs, []string{"thai", "cyrillic"})
+		require.NoError(t, err)
+		require.Len(t, games, 1)
+		assert.Equal(t, []string{"สมชาย", "Охотник"}, games[0].names)
 	})
 
 	t.Run("player not in game", func(t *testing.T) {
@@ -689,7 +974,7 @@ func TestProduceForServer(t *testing.T) {
 		mockRiot.On("GetActiveGame", ctx, "puuid-123", "NA").
 			Return(riot.ActiveGame{}, riot.ErrNotInGame)
 
-		jobs, err := bot.produceForServer(ctx, subs)
+		jobs, err := bot.produceForServer(ctx, subs, nil)
 		require.NoError(t, err)
 		assert.Len(t, jobs, 0)
 
@@ -716,7 +1001,7 @@ func TestProduceForServer(t *testing.T) {
 			},
 		}
 
-		jobs, err := bot.produceForServer(ctx, subs)
+		jobs, err := bot.produceForServer(ctx, subs, nil)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid format")
 		assert.Len(t, jobs, 0)
@@ -756,7 +1041,7 @@ func TestProduceForServer(t *testing.T) {
 		mockRepo.On("GetEvalByGameAndSubscription", ctx, mock.Anything).
 			Return(db.Eval{ID: 5}, nil)
 
-		jobs, err := bot.produceForServer(ctx, subs)
+		jobs, err := bot.produceForServer(ctx, subs, nil)
 		require.NoError(t, err)
 		assert.Len(t, jobs, 0)
 
@@ -765,6 +1050,153 @@ func TestProduceForServer(t *testing.T) {
 	})
 }
 
+// Test translatePendingGames
+func TestTranslatePendingGames(t *testing.T) {
+	ctx := context.Background()
+
+	gameFor := func(subID int64, channelID string, gameID int64, names ...string) pendingGame {
+		riotIDs := make(map[string]string, len(names))
+		for _, n := range names {
+			riotIDs[n] = n + "#KR1"
+		}
+		return pendingGame{
+			sub:     db.Subscription{ID: subID, DiscordChannelID: channelID, LolUsername: "Player#NA1", Region: "KR"},
+			gameID:  gameID,
+			names:   names,
+			riotIDs: riotIDs,
+		}
+	}
+
+	t.Run("names across games are translated in one call", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockTranslator := new(MockTranslator)
+		bot := newTestBot(mockLogger, new(MockDiscordSession), new(MockMessageServer), new(MockRepository), new(MockRiotClient), mockTranslator)
+
+		games := []pendingGame{
+			gameFor(1, "channel-1", 100, "玩家", "페이커"),
+			gameFor(2, "channel-2", 200, "페이커", "托儿索"),
+		}
+
+		mockTranslator.On("TranslateUsernames", mock.Anything, []string{"玩家", "페이커", "托儿索"}, "").
+			Return([]translation.Translation{
+				{Original: "玩家", Translated: "Player"},
+				{Original: "페이커", Translated: "Faker"},
+				{Original: "托儿索", Translated: "Yasuo"},
+			}, nil).Once()
+
+		jobs := bot.translatePendingGames(ctx, games)
+		require.Len(t, jobs, 2)
+
+		byChannel := lo.KeyBy(jobs, func(j sendMessageJob) string { return j.channelID })
+		assert.Equal(t, int64(100), byChannel["channel-1"].gameID)
+		assert.Equal(t, []translation.Translation{
+			{Original: "玩家", Translated: "Player"},
+			{Original: "페이커", Translated: "Faker"},
+		}, byChannel["channel-1"].translations)
+		assert.Equal(t, []translation.Translation{
+			{Original: "페이커", Translated: "Faker"},
+			{Original: "托儿索", Translated: "Yasuo"},
+		}, byChannel["channel-2"].translations)
+		assert.Equal(t, "托儿索#KR1", byChannel["channel-2"].riotIDs["托儿索"])
+
+		mockTranslator.AssertExpectations(t)
+	})
+
+	t.Run("game with a failed name is skipped", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockTranslator := new(MockTranslator)
+		bot := newTestBot(mockLogger, new(MockDiscordSession), new(MockMessageServer), new(MockRepository), new(MockRiotClient), mockTranslator)
+
+		games := []pendingGame{
+			gameFor(1, "channel-1", 100, "玩家"),
+			gameFor(2, "channel-2", 200, "托儿索"),
+		}
+
+		mockLogger.On("WarnContext", mock.Anything, mock.Anything, mock.Anything).Return()
+		mockTranslator.On("TranslateUsernames", mock.Anything, []string{"玩家", "托儿索"}, "").
+			Return([]translation.Translation{
+				{Original: "玩家", Translated: "Player"},
+			}, errors.New("batch failed"))
+
+		jobs := bot.translatePendingGames(ctx, games)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "channel-1", jobs[0].channelID)
+		mockLogger.AssertCalled(t, "WarnContext", mock.Anything, "skipping game with untranslated names", mock.Anything)
+	})
+
+	t.Run("over-budget servers only get cached translations", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockSession := new(MockDiscordSession)
+		mockRepo := new(MockRepository)
+		mockTranslator := new(MockTranslator)
+		bot := newTestBot(mockLogger, mockSession, new(MockMessageServer), mockRepo, new(MockRiotClient), mockTranslator)
+		bot.config.ServerMonthlyBudgetUSD = 5
+		bot.config.ServerBudgetsUSD = map[string]float64{"free": 0}
+
+		spent := gameFor(1, "channel-1", 100, "玩家", "托儿索")
+		spent.sub.ServerID = "spent"
+		spentAgain := gameFor(3, "channel-3", 300, "玩家")
+		spentAgain.sub.ServerID = "spent"
+		paying := gameFor(2, "channel-2", 200, "페이커")
+		paying.sub.ServerID = "paying"
+		free := gameFor(4, "channel-4", 400, "페이커")
+		free.sub.ServerID = "free"
+
+		mockRepo.On("SumLLMUsageCostByServerSince", mock.Anything, "spent", mock.Anything).Return(int64(5_000_000), nil)
+		mockRepo.On("SumLLMUsageCostByServerSince", mock.Anything, "paying", mock.Anything).Return(int64(4_999_999), nil)
+		mockLogger.On("WarnContext", mock.Anything, mock.Anything, mock.Anything).Return()
+		mockTranslator.On("TranslateUsernames", mock.Anything, []string{"페이커"}, "").
+			Return([]translation.Translation{{Original: "페이커", Translated: "Faker"}}, nil).Once()
+		mockTranslator.On("CachedTranslations", mock.Anything, []string{"玩家", "托儿索"}, "").
+			Return([]translation.Translation{{Original: "玩家", Translated: "Player"}}, nil)
+		mockSession.On("ChannelMessageSendComplex", "channel-1", mock.Anything, mock.Anything).
+			Return(&discordgo.Message{}, nil).Once()
+
+		jobs := bot.translatePendingGames(ctx, []pendingGame{spent, paying, spentAgain, free})
+		channels := lo.Map(jobs, func(j sendMessageJob, _ int) string { return j.channelID })
+		assert.ElementsMatch(t, []string{"channel-2", "channel-3", "channel-4"}, channels,
+			"channel-1 is skipped because 托儿索 isn't cached")
+
+		// The notice is only posted once a month.
+		bot.translatePendingGames(ctx, []pendingGame{spent})
+		mockSession.AssertNumberOfCalls(t, "ChannelMessageSendComplex", 1)
+		mockRepo.AssertNotCalled(t, "SumLLMUsageCostByServerSince", mock.Anything, "free", mock.Anything)
+	})
+
+	t.Run("games are translated into their server's language", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockTranslator := new(MockTranslator)
+		bot := newTestBot(mockLogger, new(MockDiscordSession), new(MockMessageServer), new(MockRepository), new(MockRiotClient), mockTranslator)
+
+		english := gameFor(1, "channel-1", 100, "玩家")
+		english.language = translation.LanguageEnglish
+		spanish := gameFor(2, "channel-2", 200, "玩家")
+		spanish.language = translation.LanguageSpanish
+
+		mockTranslator.On("TranslateUsernames", mock.Anything, []string{"玩家"}, translation.LanguageEnglish).
+			Return([]translation.Translation{{Original: "玩家", Translated: "Player"}}, nil).Once()
+		mockTranslator.On("TranslateUsernames", mock.Anything, []string{"玩家"}, translation.LanguageSpanish).
+			Return([]translation.Translation{{Original: "玩家", Translated: "Jugador"}}, nil).Once()
+
+		jobs := bot.translatePendingGames(ctx, []pendingGame{english, spanish})
+		require.Len(t, jobs, 2)
+
+		byChannel := lo.KeyBy(jobs, func(j sendMessageJob) string { return j.channelID })
+		assert.Equal(t, "Player", byChannel["channel-1"].translations[0].Translated)
+		assert.Equal(t, "Jugador", byChannel["channel-2"].translations[0].Translated)
+		assert.Equal(t, translation.LanguageSpanish, byChannel["channel-2"].language)
+		mockTranslator.AssertExpectations(t)
+	})
+
+	t.Run("no pending games skips the translator", func(t *testing.T) {
+		mockTranslator := new(MockTranslator)
+		bot := newTestBot(new(MockLogger), new(MockDiscordSession), new(MockMessageServer), new(MockRepository), new(MockRiotClient), mockTranslator)
+
+		assert.Empty(t, bot.translatePendingGames(ctx, nil))
+		mockTranslator.AssertNotCalled(t, "TranslateUsernames", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 // Test handleSubscribe
 func TestHandleSubscribe(t *testing.T) {
 	t.Run("successful subscription", func(t *testing.T) {
@@ -1023,3 +1455,244 @@ func TestHandleListForChannel(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+// Test handleConfig
+func TestHandleConfig(t *testing.T) {
+	configInteraction := func(options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+		return &discordgo.InteractionCreate{
+			Interaction: &discordgo.Interaction{
+				Type:      discordgo.InteractionApplicationCommand,
+				Data:      discordgo.ApplicationCommandInteractionData{Options: options},
+				GuildID:   "guild-123",
+				ChannelID: "channel-456",
+			},
+		}
+	}
+
+	t.Run("sets the target language", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockRepo := new(MockRepository)
+		bot := newTestBot(mockLogger, new(MockDiscordSession), new(MockMessageServer), mockRepo, new(MockRiotClient), new(MockTranslator))
+
+		mockRepo.On("GetServerConfig", mock.Anything, "guild-123").Return(db.ServerConfig{}, db.ErrNoRows)
+		mockRepo.On("UpsertServerConfig", mock.Anything, db.UpsertServerConfigParams{ServerID: "guild-123", TargetLanguage: "es"}).
+			Return(db.ServerConfig{ServerID: "guild-123", TargetLanguage: "es"}, nil)
+		mockLogger.On("InfoContext", mock.Anything, mock.Anything, mock.Anything).Return()
+
+		result := bot.handleConfig(configInteraction(
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "language", Type: discordgo.ApplicationCommandOptionString, Value: "es"},
+		))
+		assert.NoError(t, result.Err)
+		assert.Contains(t, result.Response, "Spanish")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an unsupported language", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		bot := newTestBot(new(MockLogger), new(MockDiscordSession), new(MockMessageServer), mockRepo, new(MockRiotClient), new(MockTranslator))
+
+		mockRepo.On("GetServerConfig", mock.Anything, "guild-123").Return(db.ServerConfig{}, db.ErrNoRows)
+
+		result := bot.handleConfig(configInteraction(
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "language", Type: discordgo.ApplicationCommandOptionString, Value: "xx"},
+		))
+		_, isUserErr := errors.AsType[*userError](result.Err)
+		assert.True(t, isUserErr)
+		mockRepo.AssertNotCalled(t, "UpsertServerConfig", mock.Anything, mock.Anything)
+	})
+
+	t.Run("shows the default for an unconfigured server", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		bot := newTestBot(new(MockLogger), new(MockDiscordSession), new(MockMessageServer), mockRepo, new(MockRiotClient), new(MockTranslator))
+
+		mockRepo.On("GetServerConfig", mock.Anything, "guild-123").Return(db.ServerConfig{}, db.ErrNoRows)
+
+		result := bot.handleConfig(configInteraction())
+		assert.NoError(t, result.Err)
+		assert.Contains(t, result.Response, "English")
+		assert.Contains(t, result.Response, "korean, japanese, chinese", "opt-in scripts aren't translated by default")
+	})
+
+	t.Run("sets scripts and keeps the language", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockRepo := new(MockRepository)
+		bot := newTestBot(mockLogger, new(MockDiscordSession), new(MockMessageServer), mockRepo, new(MockRiotClient), new(MockTranslator))
+
+		mockRepo.On("GetServerConfig", mock.Anything, "guild-123").
+			Return(db.ServerConfig{ServerID: "guild-123", TargetLanguage: "pt"}, nil)
+		mockRepo.On("UpsertServerConfig", mock.Anything, db.UpsertServerConfigParams{ServerID: "guild-123", TargetLanguage: "pt", Scripts: "thai,cyrillic"}).
+			Return(db.ServerConfig{ServerID: "guild-123", TargetLanguage: "pt", Scripts: "thai,cyrillic"}, nil)
+		mockLogger.On("InfoContext", mock.Anything, mock.Anything, mock.Anything).Return()
+
+		result := bot.handleConfig(configInteraction(
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "scripts", Type: discordgo.ApplicationCommandOptionString, Value: "Thai, cyrillic"},
+		))
+		assert.NoError(t, result.Err)
+		assert.Contains(t, result.Response, "thai, cyrillic")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("opts into every script", func(t *testing.T) {
+		mockLogger := new(MockLogger)
+		mockRepo := new(MockRepository)
+		bot := newTestBot(mockLogger, new(MockDiscordSession), new(MockMessageServer), mockRepo, new(MockRiotClient), new(MockTranslator))
+
+		mockRepo.On("GetServerConfig", mock.Anything, "guild-123").Return(db.ServerConfig{}, db.ErrNoRows).Once()
+		mockRepo.On("UpsertServerConfig", mock.Anything, db.UpsertServerConfigParams{ServerID: "guild-123", TargetLanguage: "en", Scripts: "all"}).
+			Return(db.ServerConfig{ServerID: "guild-123", TargetLanguage: "en", Scripts: "all"}, nil)
+		mockLogger.On("InfoContext", mock.Anything, mock.Anything, mock.Anything).Return()
+
+		result := bot.handleConfig(configInteraction(
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "scripts", Type: discordgo.ApplicationCommandOptionString, Value: "All"},
+		))
+		assert.NoError(t, result.Err)
+		assert.Contains(t, result.Response, "all scripts")
+
+		mockRepo.On("GetServerConfig", mock.Anything, "guild-123").Return(db.ServerConfig{ServerID: "guild-123", TargetLanguage: "en", Scripts: "all"}, nil)
+		assert.Nil(t, bot.serverConfig(context.Background(), "guild-123").scripts)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an unknown script", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		bot := newTestBot(new(MockLogger), new(MockDiscordSession), new(MockMessageServer), mockRepo, new(MockRiotClient), new(MockTranslator))
+
+		mockRepo.On("GetServerConfig", mock.Anything, "guild-123").Return(db.ServerConfig{}, db.ErrNoRows)
+
+		result := bot.handleConfig(configInteraction(
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "scripts", Type: discordgo.ApplicationCommandOptionString, Value: "klingon"},
+		))
+		_, isUserErr := errors.AsType[*userError](result.Err)
+		assert.True(t, isUserErr)
+		mockRepo.AssertNotCalled(t, "UpsertServerConfig", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFormatTranslationEmbedLocalized(t *testing.T) {
+	translations := []translation.Translation{{Original: "玩家", Translated: "Jugador", Quality: translation.QualityDictionary}}
+
+	embed := formatTranslationEmbed("Player#NA1", translations, labelsFor(translation.LanguageSpanish))
+	assert.Equal(t, "¡Player#NA1 está en partida!", embed.Title)
+	assert.Equal(t, "Traducción", embed.Fields[1].Name)
+	require.NotNil(t, embed.Footer)
+
+	fallback := formatTranslationEmbed("Player#NA1", translations, labelsFor("xx"))
+	assert.Equal(t, "Player#NA1 is in a game!", fallback.Title)
+}
+
+func TestEmbedNamesReadsInlineAndOverflowNames(t *testing.T) {
+	originals := []string{"大魔王", "Faker", "一", "二", "三", "四", "五", "六", "龍王", "페이커"}
+	translations := lo.Map(originals, func(name string, _ int) translation.Translation {
+		return translation.Translation{Original: name, Translated: "x"}
+	})
+
+	embed := formatTranslationEmbed("Player#NA1", translations, labelsFor(translation.LanguagePortuguese))
+	assert.Equal(t, originals, embedNames(embed))
+}
+
+func TestFormatBreakdownEmbed(t *testing.T) {
+	// A small dictionary rather than the bundled one, so the readings don't
+	// change with its releases.
+	dict, err := dictionary.New(strings.NewReader(`大 大 [da4] /big/great/
+王 王 [wang2] /king/
+`), nil)
+	require.NoError(t, err)
+	require.NoError(t, dict.LoadReadings(
+		strings.NewReader("大\t대\n王\t왕\n"),
+		strings.NewReader("大\tダイ タイ\tおお\tlarge; big\n王\tオウ\t\tking\n"),
+	))
+
+	embed := formatBreakdownEmbed([]string{"大魔王", "龍王"}, dict, labelsFor(translation.LanguageEnglish))
+	require.Len(t, embed.Fields, 2)
+	assert.Equal(t, "大魔王", embed.Fields[0].Name)
+	lines := strings.Split(embed.Fields[0].Value, "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "**大** dà · 대 dae · ダイ, タイ / おお — big, great", lines[0])
+	assert.Equal(t, "**王** wáng · 왕 wang · オウ — king", lines[2])
+	assert.Equal(t, "龍王", embed.Fields[1].Name)
+}
+
+func TestWebsiteClientSubmissions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v1/translations":
+			var body websiteSubmission
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			switch body.Username {
+			case "페이커#KR1":
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"status":"completed","translation_id":7}`))
+			case "대마왕#KR1":
+				w.WriteHeader(http.StatusAccepted)
+				w.Write([]byte(`{"id":42,"status":"queued"}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"username not found on Riot servers"}`))
+			}
+		case r.Method == "GET" && r.URL.Path == "/api/v1/submissions/42":
+			w.Write([]byte(`{"id":42,"status":"completed","translation_id":8}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewWebsiteClient(srv.URL)
+	submissions, err := client.SubmitTranslations(context.Background(), []translation.Translation{
+		{Original: "페이커", Translated: "Faker"},
+		{Original: "대마왕", Translated: "Great Demon King"},
+		{Original: "없음", Translated: "Nobody"},
+	}, map[string]string{"페이커": "페이커#KR1", "대마왕": "대마왕#KR1", "없음": "없음#KR1"}, "KR")
+	require.NoError(t, err)
+	assert.Equal(t, []WebsiteSubmission{
+		{Username: "페이커#KR1", Status: "completed", TranslationID: 7},
+		{Username: "대마왕#KR1", ID: 42, Status: "queued"},
+	}, submissions)
+
+	submission, err := client.Submission(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, WebsiteSubmission{ID: 42, Status: "completed", TranslationID: 8}, submission)
+
+	_, err = client.Submission(context.Background(), 43)
+	assert.Error(t, err)
+}
+
+func TestPollSubmissions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/submissions/1":
+			w.Write([]byte(`{"id":1,"status":"completed","translation_id":8}`))
+		case "/api/v1/submissions/2":
+			w.Write([]byte(`{"id":2,"status":"failed"}`))
+		case "/api/v1/submissions/3", "/api/v1/submissions/4":
+			w.Write([]byte(`{"status":"running"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	mockLogger := new(MockLogger)
+	mockLogger.On("InfoContext", mock.Anything, "website translated submission", mock.Anything).Return().Once()
+	mockLogger.On("WarnContext", mock.Anything, "website failed to translate submission", mock.Anything).Return().Once()
+	mockLogger.On("WarnContext", mock.Anything, "gave up waiting for website submission", mock.Anything).Return().Once()
+
+	bot := newTestBot(mockLogger, new(MockDiscordSession), new(MockMessageServer), new(MockRepository), new(MockRiotClient), new(MockTranslator))
+	bot.websiteClient = NewWebsiteClient(srv.URL)
+
+	now := time.Now()
+	bot.pendingSubmissions.add([]WebsiteSubmission{
+		{Username: "페이커#KR1", Status: "completed", TranslationID: 7},
+		{Username: "대마왕#KR1", ID: 1, Status: "queued"},
+		{Username: "없음#KR1", ID: 2, Status: "queued"},
+		{Username: "大魔王#TW2", ID: 3, Status: "queued"},
+		{Username: "王者#TW2", ID: 5, Status: "queued"},
+	}, now)
+	bot.pendingSubmissions.add([]WebsiteSubmission{{Username: "魔王#TW2", ID: 4, Status: "queued"}}, now.Add(-submissionMaxAge))
+
+	bot.pollSubmissions(context.Background(), now)
+
+	pending := lo.Map(bot.pendingSubmissions.take(), func(p pendingSubmission, _ int) int64 { return p.ID })
+	assert.Equal(t, []int64{3, 5}, pending, "running and unreachable submissions are still followed")
+	mockLogger.AssertExpectations(t)
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/samber/lo"
+)
+
+// breakdownButtonID is the custom ID of the button that expands a translation
+// message into a per-character breakdown of its Han names.
+const breakdownButtonID = "breakdown"
+
+// Discord caps an embed at 25 fields of 1024 characters each.
+const (
+	maxBreakdownNames  = 25
+	maxBreakdownLength = 1024
+)
+
+func hasHan(s string) bool {
+	return strings.ContainsFunc(s, func(r rune) bool { return unicode.Is(unicode.Han, r) })
+}
+
+// embedNames returns the original names listed in a translation embed, both
+// the inline Original fields and the overflow list formatTranslationEmbed
+// writes as "**name** → translation" lines.
+func embedNames(embed *discordgo.MessageEmbed) []string {
+	originals := lo.Map(lo.Values(labelsByLanguage), func(l embedLabels, _ int) string { return l.original })
+	var names []string
+	for _, f := range embed.Fields {
+		if lo.Contains(originals, f.Name) {
+			names = append(names, f.Value)
+			continue
+		}
+		for _, line := range strings.Split(f.Value, "\n") {
+			if name, _, ok := strings.Cut(strings.TrimPrefix(line, "**"), "** → "); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return lo.Uniq(names)
+}
+
+// formatBreakdownEmbed lists each name's Han characters with their readings
+// and meanings, one field per name:
+//
+//	**大** dà · 대 dae · ダイ, タイ / おお — big, great
+func formatBreakdownEmbed(names []string, dict *dictionary.Dictionary, labels embedLabels) *discordgo.MessageEmbed {
+	fields := make([]*discordgo.MessageEmbedField, 0, maxBreakdownNames)
+	for _, name := range names {
+		glyphs := dict.Glyphs(name)
+		if len(glyphs) == 0 {
+			continue
+		}
+		lines := lo.Map(glyphs, func(g dictionary.Glyph, _ int) string { return formatGlyph(g) })
+		value := strings.Join(lines, "\n")
+		if len(value) > maxBreakdownLength {
+			value = value[:strings.LastIndex(value[:maxBreakdownLength-1], "\n")+1] + "…"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: name, Value: value})
+		if len(fields) == maxBreakdownNames {
+			break
+		}
+	}
+	return &discordgo.MessageEmbed{
+		Title:       labels.breakdownTitle,
+		Color:       0x5865F2,
+		Description: labels.breakdownHelp,
+		Fields:      fields,
+	}
+}
+
+func formatGlyph(g dictionary.Glyph) string {
+	var readings []string
+	if g.Pinyin != "" {
+		readings = append(readings, g.Pinyin)
+	}
+	if g.Eum != "" {
+		readings = append(readings, g.Eum+" "+transliteration.Transliterate(g.Eum))
+	}
+	switch {
+	case len(g.On) > 0 && len(g.Kun) > 0:
+		readings = append(readings, strings.Join(g.On, ", ")+" / "+strings.Join(g.Kun, ", "))
+	case len(g.On)+len(g.Kun) > 0:
+		readings = append(readings, strings.Join(append(g.On, g.Kun...), ", "))
+	}
+
+	line := "**" + g.Char + "**"
+	if len(readings) > 0 {
+		line += " " + strings.Join(readings, " · ")
+	}
+	if len(g.Meanings) > 0 {
+		line += " — " + strings.Join(g.Meanings, ", ")
+	}
+	return line
+}
+
+// handleBreakdown answers the Breakdown button with the character breakdown of
+// the message's Han names, visible only to the user who asked.
+func (b *Bot) handleBreakdown(ctx context.Context, i *discordgo.InteractionCreate) *discordgo.InteractionResponseData {
+	labels := labelsFor(b.serverConfig(ctx, i.GuildID).language)
+	var names []string
+	for _, embed := range i.Message.Embeds {
+		names = append(names, lo.Filter(embedNames(embed), func(name string, _ int) bool { return hasHan(name) })...)
+	}
+	if len(names) == 0 || b.config.Dictionary == nil {
+		return &discordgo.InteractionResponseData{Content: labels.noBreakdown, Flags: discordgo.MessageFlagsEphemeral}
+	}
+	return &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{formatBreakdownEmbed(names, b.config.Dictionary, labels)},
+		Flags:  discordgo.MessageFlagsEphemeral,
+	}
+}
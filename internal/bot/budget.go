@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/samber/lo"
+)
+
+// budgetNotices remembers which servers have been told they're out of LLM
+// budget, keyed by server ID with the month they were told in, so each server
+// hears about it once a month rather than once per game. It lives in memory, so
+// a restart can repeat a notice.
+type budgetNotices struct {
+	mu   sync.Mutex
+	sent map[string]string
+}
+
+// claim reports whether serverID still needs a notice for month and, if so,
+// marks it as sent.
+func (n *budgetNotices) claim(serverID, month string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.sent == nil {
+		n.sent = make(map[string]string)
+	}
+	if n.sent[serverID] == month {
+		return false
+	}
+	n.sent[serverID] = month
+	return true
+}
+
+// serverBudgetMicroUSD returns serverID's monthly LLM budget in millionths of a
+// dollar, or 0 when it is unlimited.
+func (b *Bot) serverBudgetMicroUSD(serverID string) int64 {
+	budget := b.config.ServerMonthlyBudgetUSD
+	if override, ok := b.config.ServerBudgetsUSD[serverID]; ok {
+		budget = override
+	}
+	return int64(budget * 1e6)
+}
+
+// overBudgetServers returns the servers among games that have spent their LLM
+// budget for the current calendar month (UTC). A server whose spend can't be
+// looked up is treated as under budget so a database hiccup doesn't stop
+// translations.
+func (b *Bot) overBudgetServers(ctx context.Context, games []pendingGame) map[string]bool {
+	over := make(map[string]bool)
+	since := monthStart(time.Now())
+	servers := lo.Uniq(lo.Map(games, func(g pendingGame, _ int) string { return g.sub.ServerID }))
+	for _, server := range servers {
+		budget := b.serverBudgetMicroUSD(server)
+		if budget <= 0 {
+			continue
+		}
+		spent, err := b.repo.SumLLMUsageCostByServerSince(ctx, server, since)
+		if err != nil {
+			b.log.WarnContext(ctx, "failed to look up LLM spend, not enforcing budget", "server_id", server, "error", err)
+			continue
+		}
+		if spent >= budget {
+			over[server] = true
+		}
+	}
+	return over
+}
+
+// notifyBudgetExhausted tells sub's channel that its server is out of budget,
+// once per server per month.
+func (b *Bot) notifyBudgetExhausted(ctx context.Context, sub db.Subscription) {
+	now := time.Now().UTC()
+	if !b.budgetNotices.claim(sub.ServerID, now.Format("2006-01")) {
+		return
+	}
+	content := fmt.Sprintf("⚠️ This server has used its translation budget for %s. "+
+		"Until it resets on the 1st, I'll only post games where every name has been translated before.", now.Format("January"))
+	if _, err := b.session.ChannelMessageSendComplex(sub.DiscordChannelID, &discordgo.MessageSend{Content: content}); err != nil {
+		b.log.WarnContext(ctx, "failed to send budget notice", "server_id", sub.ServerID, "channel_id", sub.DiscordChannelID, "error", err)
+	}
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/samber/lo"
+)
+
+// embedLabels is the fixed text of a translation message in one target
+// language. Names in the embed are the LLM's output; only the chrome around
+// them is localized here.
+type embedLabels struct {
+	title            string // formatted with the subscribed username
+	description      string
+	original         string
+	translation      string
+	dictionaryFooter string
+	goodButton       string
+	fixButton        string
+	breakdownButton  string
+	breakdownTitle   string
+	breakdownHelp    string
+	noBreakdown      string
+}
+
+var labelsByLanguage = map[string]embedLabels{
+	translation.LanguageEnglish: {
+		title:            "%s is in a game!",
+		description:      "Translations for players in this match:",
+		original:         "Original",
+		translation:      "Translation",
+		dictionaryFooter: "📖 Some names are dictionary translations: word-by-word glosses, not an AI translation.",
+		goodButton:       "Good ✓",
+		fixButton:        "Suggest Fix",
+		breakdownButton:  "Breakdown",
+		breakdownTitle:   "Character breakdown",
+		breakdownHelp:    "Readings in Mandarin · Korean · Japanese (on / kun), from the offline dictionary.",
+		noBreakdown:      "No Chinese characters to break down in this message.",
+	},
+	translation.LanguageSpanish: {
+		title:            "¡%s está en partida!",
+		description:      "Traducciones de los jugadores de esta partida:",
+		original:         "Original",
+		translation:      "Traducción",
+		dictionaryFooter: "📖 Algunos nombres son traducciones de diccionario: glosas palabra por palabra, no una traducción de IA.",
+		goodButton:       "Bien ✓",
+		fixButton:        "Sugerir corrección",
+		breakdownButton:  "Desglose",
+		breakdownTitle:   "Desglose por carácter",
+		breakdownHelp:    "Lecturas en mandarín · coreano · japonés (on / kun), del diccionario sin conexión.",
+		noBreakdown:      "No hay caracteres chinos que desglosar en este mensaje.",
+	},
+	translation.LanguagePortuguese: {
+		title:            "%s está em partida!",
+		description:      "Traduções dos jogadores desta partida:",
+		original:         "Original",
+		translation:      "Tradução",
+		dictionaryFooter: "📖 Alguns nomes são traduções de dicionário: glosas palavra por palavra, não uma tradução por IA.",
+		goodButton:       "Bom ✓",
+		fixButton:        "Sugerir correção",
+		breakdownButton:  "Detalhar",
+		breakdownTitle:   "Caracteres em detalhe",
+		breakdownHelp:    "Leituras em mandarim · coreano · japonês (on / kun), do dicionário offline.",
+		noBreakdown:      "Não há caracteres chineses para detalhar nesta mensagem.",
+	},
+}
+
+// labelsFor returns the embed labels for language, falling back to the default
+// language's.
+func labelsFor(language string) embedLabels {
+	if l, ok := labelsByLanguage[language]; ok {
+		return l
+	}
+	return labelsByLanguage[translation.DefaultLanguage]
+}
+
+func buildLanguageChoices() []*discordgo.ApplicationCommandOptionChoice {
+	codes := lo.Keys(translation.LanguageNames)
+	sort.Strings(codes)
+	return lo.Map(codes, func(code string, _ int) *discordgo.ApplicationCommandOptionChoice {
+		return &discordgo.ApplicationCommandOptionChoice{
+			Name:  translation.LanguageNames[code],
+			Value: code,
+		}
+	})
+}
+
+// allScripts is stored as a server's scripts when it chose every script,
+// including those that are opt-in. An empty setting means the defaults.
+const allScripts = "all"
+
+// serverSettings is a server's config with defaults filled in.
+type serverSettings struct {
+	language string
+	// scripts are the transliteration scripts whose names get translated; nil
+	// means all of them.
+	scripts []string
+	// scriptsSetting is scripts as stored: empty for the defaults, allScripts
+	// or a list of names.
+	scriptsSetting string
+}
+
+// serverConfig returns the settings configured for serverID. Servers that never
+// ran /config, or whose config can't be read, get the defaults.
+func (b *Bot) serverConfig(ctx context.Context, serverID string) serverSettings {
+	settings := serverSettings{language: translation.DefaultLanguage, scripts: transliteration.DefaultScripts()}
+	cfg, err := b.repo.GetServerConfig(ctx, serverID)
+	if err != nil {
+		if !db.IsNoRows(err) {
+			b.log.WarnContext(ctx, "failed to load server config, using defaults", "server_id", serverID, "error", err)
+		}
+		return settings
+	}
+	settings.language = cfg.TargetLanguage
+	switch cfg.Scripts {
+	case "":
+		return settings
+	case allScripts:
+		settings.scripts = nil
+	default:
+		scripts, err := transliteration.ParseScripts(cfg.Scripts)
+		if err != nil {
+			// A script can disappear from the registry after a server picked it.
+			b.log.WarnContext(ctx, "ignoring invalid scripts in server config", "server_id", serverID, "scripts", cfg.Scripts, "error", err)
+			return settings
+		}
+		settings.scripts = scripts
+	}
+	settings.scriptsSetting = cfg.Scripts
+	return settings
+}
+
+// describeScripts lists script names for a /config reply.
+func describeScripts(scripts []string) string {
+	if len(scripts) == 0 {
+		return "all scripts"
+	}
+	return strings.Join(scripts, ", ")
+}
+
+func (b *Bot) handleConfig(i *discordgo.InteractionCreate) handlerResult {
+	options := i.ApplicationCommandData().Options
+	language := getOption(options, "language")
+	scriptsOption := getOption(options, "scripts")
+	serverID := i.GuildID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+
+	current := b.serverConfig(ctx, serverID)
+	if language == "" && scriptsOption == "" {
+		return handlerResult{Response: fmt.Sprintf(
+			"Translations in this server are in **%s**, for names in %s. Use `/config language:<language>` or `/config scripts:<korean,chinese,...|all>` to change them.",
+			translation.LanguageNames[current.language], describeScripts(current.scripts),
+		)}
+	}
+
+	if language == "" {
+		language = current.language
+	}
+	if !translation.IsSupportedLanguage(language) {
+		return handlerResult{
+			Response: fmt.Sprintf("❌ Unsupported language **%s**", language),
+			Err:      newUserError(fmt.Errorf("unsupported language %q", language)),
+		}
+	}
+
+	scripts, scriptsSetting := current.scripts, current.scriptsSetting
+	if scriptsOption != "" {
+		scripts, scriptsSetting = nil, allScripts
+		if !strings.EqualFold(strings.TrimSpace(scriptsOption), allScripts) {
+			parsed, err := transliteration.ParseScripts(scriptsOption)
+			if err != nil || len(parsed) == 0 {
+				names := lo.Map(transliteration.Scripts(), func(s transliteration.Script, _ int) string { return s.Name })
+				return handlerResult{
+					Response: fmt.Sprintf("❌ Unknown scripts **%s**. Choose from: %s, or `all`", scriptsOption, strings.Join(names, ", ")),
+					Err:      newUserError(fmt.Errorf("parsing scripts %q: %w", scriptsOption, err)),
+				}
+			}
+			scripts, scriptsSetting = parsed, strings.Join(parsed, ",")
+		}
+	}
+
+	_, err := b.repo.UpsertServerConfig(ctx, db.UpsertServerConfigParams{
+		ServerID:       serverID,
+		TargetLanguage: language,
+		Scripts:        scriptsSetting,
+	})
+	if err != nil {
+		return handlerResult{
+			Response: "❌ Failed to save server settings. Please try again later.",
+			Err:      fmt.Errorf("upserting config for server %s: %w", serverID, err),
+		}
+	}
+
+	b.log.InfoContext(ctx, "server config updated", "server_id", serverID, "target_language", language, "scripts", scripts)
+	return handlerResult{Response: fmt.Sprintf("✅ Translations in this server will now be in **%s**, for names in %s.", translation.LanguageNames[language], describeScripts(scripts))}
+}
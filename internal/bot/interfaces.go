@@ -9,6 +9,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/samber/lo"
 )
 
 // Logger defines the logging interface used by Bot
@@ -43,7 +44,8 @@ type RiotClient interface {
 
 // Translator defines the translation interface used by Bot
 type Translator interface {
-	TranslateUsernames(ctx context.Context, usernames []string) ([]translation.Translation, error)
+	TranslateUsernames(ctx context.Context, usernames []string, target string) ([]translation.Translation, error)
+	CachedTranslations(ctx context.Context, usernames []string, target string) ([]translation.Translation, error)
 }
 
 // MessageServer defines the interface for sending messages to a messaging platform
@@ -106,25 +108,30 @@ type discordMessageServer struct {
 }
 
 func (d *discordMessageServer) SendMessage(ctx context.Context, job sendMessageJob) (*discordgo.Message, error) {
-	embed := formatTranslationEmbed(job.username, job.translations)
-	return d.session.ChannelMessageSendComplex(job.channelID, &discordgo.MessageSend{
-		Embeds: []*discordgo.MessageEmbed{embed},
-		Components: []discordgo.MessageComponent{
-			discordgo.ActionsRow{
-				Components: []discordgo.MessageComponent{
-					discordgo.Button{
-						Label:    "Good ✓",
-						CustomID: "feedback_good",
-						Style:    discordgo.SuccessButton,
-					},
-					discordgo.Button{
-						Label:    "Suggest Fix",
-						CustomID: "feedback_fix",
-						Style:    discordgo.SecondaryButton,
-					},
-				},
-			},
+	labels := labelsFor(job.language)
+	embed := formatTranslationEmbed(job.username, job.translations, labels)
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    labels.goodButton,
+			CustomID: "feedback_good",
+			Style:    discordgo.SuccessButton,
+		},
+		discordgo.Button{
+			Label:    labels.fixButton,
+			CustomID: "feedback_fix",
+			Style:    discordgo.SecondaryButton,
 		},
+	}
+	if lo.SomeBy(job.translations, func(t translation.Translation) bool { return hasHan(t.Original) }) {
+		buttons = append(buttons, discordgo.Button{
+			Label:    labels.breakdownButton,
+			CustomID: breakdownButtonID,
+			Style:    discordgo.SecondaryButton,
+		})
+	}
+	return d.session.ChannelMessageSendComplex(job.channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}},
 	})
 }
 
@@ -141,7 +148,7 @@ func NewMessageServer(session DiscordSession) MessageServer {
 	return &discordMessageServer{session: session}
 }
 
-func formatTranslationEmbed(username string, translations []translation.Translation) *discordgo.MessageEmbed {
+func formatTranslationEmbed(username string, translations []translation.Translation, labels embedLabels) *discordgo.MessageEmbed {
 	const maxInlineEntries = 8
 	fields := make([]*discordgo.MessageEmbedField, 0, 25)
 
@@ -149,8 +156,8 @@ func formatTranslationEmbed(username string, translations []translation.Translat
 	for i := 0; i < inlineCount; i++ {
 		t := translations[i]
 		fields = append(fields,
-			&discordgo.MessageEmbedField{Name: "Original", Value: t.Original, Inline: true},
-			&discordgo.MessageEmbedField{Name: "Translation", Value: t.Translated, Inline: true},
+			&discordgo.MessageEmbedField{Name: labels.original, Value: t.Original, Inline: true},
+			&discordgo.MessageEmbedField{Name: labels.translation, Value: t.Translated, Inline: true},
 		)
 		if i < inlineCount-1 {
 			fields = append(fields, &discordgo.MessageEmbedField{Name: "\u200b", Value: "\u200b", Inline: false})
@@ -168,10 +175,16 @@ func formatTranslationEmbed(username string, translations []translation.Translat
 		})
 	}
 
-	return &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("%s is in a game!", username),
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf(labels.title, username),
 		Color:       0x5865F2,
-		Description: "Translations for players in this match:",
+		Description: labels.description,
 		Fields:      fields,
 	}
+	if lo.SomeBy(translations, func(t translation.Translation) bool { return t.Quality == translation.QualityDictionary }) {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: labels.dictionaryFooter,
+		}
+	}
+	return embed
 }
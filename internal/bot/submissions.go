@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// submissionPollInterval is how often queued website submissions are
+	// checked on.
+	submissionPollInterval = time.Minute
+	// submissionMaxAge is how long a submission is followed before the bot
+	// stops waiting for it. The website retries a translation for well under
+	// that, so one still queued by then is stuck.
+	submissionMaxAge = time.Hour
+)
+
+// pendingSubmission is a website submission still being translated, with when
+// it was submitted.
+type pendingSubmission struct {
+	WebsiteSubmission
+	submittedAt time.Time
+}
+
+// pendingSubmissions holds the website submissions that were queued rather
+// than already translated, until the website reports how they went. It lives
+// in memory, so a restart stops following the ones in flight.
+type pendingSubmissions struct {
+	mu    sync.Mutex
+	items []pendingSubmission
+}
+
+// add follows the queued ones among submissions.
+func (p *pendingSubmissions) add(submissions []WebsiteSubmission, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, s := range submissions {
+		if s.ID != 0 {
+			p.items = append(p.items, pendingSubmission{WebsiteSubmission: s, submittedAt: now})
+		}
+	}
+}
+
+// take returns the submissions being followed and stops following them.
+func (p *pendingSubmissions) take() []pendingSubmission {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	items := p.items
+	p.items = nil
+	return items
+}
+
+// keep follows taken submissions again.
+func (p *pendingSubmissions) keep(items []pendingSubmission) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.items = append(p.items, items...)
+}
+
+func (b *Bot) runSubmissionPoller(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for ctx.Err() == nil {
+		sleepWithContext(ctx, submissionPollInterval)
+		pollCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		b.pollSubmissions(pollCtx, time.Now())
+		cancel()
+	}
+}
+
+// pollSubmissions checks on each pending website submission, logging the ones
+// the website failed to translate. Submissions still queued or running, or
+// that couldn't be checked, are followed until submissionMaxAge.
+func (b *Bot) pollSubmissions(ctx context.Context, now time.Time) {
+	var still []pendingSubmission
+	for _, p := range b.pendingSubmissions.take() {
+		s, err := b.websiteClient.Submission(ctx, p.ID)
+		switch {
+		case err == nil && s.Status == "completed":
+			b.log.InfoContext(ctx, "website translated submission",
+				"username", p.Username,
+				"submission_id", p.ID,
+				"translation_id", s.TranslationID,
+			)
+			continue
+		case err == nil && s.Status == "failed":
+			b.log.WarnContext(ctx, "website failed to translate submission",
+				"username", p.Username,
+				"submission_id", p.ID,
+			)
+			continue
+		case now.Sub(p.submittedAt) >= submissionMaxAge:
+			b.log.WarnContext(ctx, "gave up waiting for website submission",
+				"username", p.Username,
+				"submission_id", p.ID,
+				"status", s.Status,
+				"error", err,
+			)
+			continue
+		}
+		still = append(still, p)
+	}
+	b.pendingSubmissions.keep(still)
+}
@@ -36,11 +36,25 @@ type websiteSubmission struct {
 	Region   string `json:"region"`
 }
 
-func (w *WebsiteClient) SubmitTranslations(ctx context.Context, translations []translation.Translation, riotIDs map[string]string, region string) error {
+// WebsiteSubmission is the website's receipt for a submitted username. A
+// name the website hasn't translated yet is queued and has the ID to poll
+// with Submission; one it already has comes back completed, with no ID.
+type WebsiteSubmission struct {
+	Username      string `json:"-"`
+	ID            int64  `json:"id"`
+	Status        string `json:"status"`
+	TranslationID int64  `json:"translation_id"`
+}
+
+// SubmitTranslations submits each translated username and returns the
+// receipts of those the website accepted. Names it rejects, such as ones Riot
+// doesn't know, are skipped.
+func (w *WebsiteClient) SubmitTranslations(ctx context.Context, translations []translation.Translation, riotIDs map[string]string, region string) ([]WebsiteSubmission, error) {
 	if !w.Enabled() {
-		return nil
+		return nil, nil
 	}
 
+	var submissions []WebsiteSubmission
 	for _, t := range translations {
 		// Use full Riot ID (name#tag) if available, fall back to game name
 		username := t.Original
@@ -54,21 +68,56 @@ func (w *WebsiteClient) SubmitTranslations(ctx context.Context, translations []t
 
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("marshaling submission: %w", err)
+			return submissions, fmt.Errorf("marshaling submission: %w", err)
 		}
 
 		req, err := http.NewRequestWithContext(ctx, "POST", w.url+"/api/v1/translations", bytes.NewReader(jsonBody))
 		if err != nil {
-			return fmt.Errorf("creating request: %w", err)
+			return submissions, fmt.Errorf("creating request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
 
 		resp, err := w.http.Do(req)
 		if err != nil {
-			return fmt.Errorf("submitting username %s: %w", t.Original, err)
+			return submissions, fmt.Errorf("submitting username %s: %w", t.Original, err)
+		}
+		var submission WebsiteSubmission
+		accepted := resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted
+		if accepted {
+			err = json.NewDecoder(resp.Body).Decode(&submission)
 		}
 		resp.Body.Close()
+		if err != nil {
+			return submissions, fmt.Errorf("decoding submission of %s: %w", t.Original, err)
+		}
+		if accepted {
+			submission.Username = username
+			submissions = append(submissions, submission)
+		}
+	}
+
+	return submissions, nil
+}
+
+// Submission fetches the current status of a queued submission by its ID.
+func (w *WebsiteClient) Submission(ctx context.Context, id int64) (WebsiteSubmission, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v1/submissions/%d", w.url, id), nil)
+	if err != nil {
+		return WebsiteSubmission{}, fmt.Errorf("creating request: %w", err)
 	}
 
-	return nil
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return WebsiteSubmission{}, fmt.Errorf("fetching submission %d: %w", id, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return WebsiteSubmission{}, fmt.Errorf("fetching submission %d: status %d", id, resp.StatusCode)
+	}
+
+	var submission WebsiteSubmission
+	if err := json.NewDecoder(resp.Body).Decode(&submission); err != nil {
+		return WebsiteSubmission{}, fmt.Errorf("decoding submission %d: %w", id, err)
+	}
+	return submission, nil
 }
@@ -0,0 +1,87 @@
+// Package cedict bundles CC-CEDICT, the community-maintained Chinese-English
+// dictionary. The offline translator glosses names with its words, and pinyin
+// conversion reads characters that change with the word they're in (长 in 长城
+// and 队长) from its readings.
+//
+// CC-CEDICT is licensed under a Creative Commons Attribution-ShareAlike 4.0
+// International License (https://creativecommons.org/licenses/by-sa/4.0/).
+// Source: https://www.mdbg.net/chinese/dictionary?page=cc-cedict
+package cedict
+
+//go:generate go run gen.go
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"io"
+	"strings"
+)
+
+// bundled is cedict.u8.gz, written by gen.go.
+//
+//go:embed cedict.u8.gz
+var bundled []byte
+
+// Entry is one line of CC-CEDICT.
+type Entry struct {
+	Traditional string
+	Simplified  string
+	// Pinyin is the reading as CC-CEDICT writes it: syllables with tone
+	// numbers, ü as u: and the neutral tone as 5 ("chang2 cheng2").
+	Pinyin string
+	// Glosses are the English equivalents, most common sense first.
+	Glosses []string
+}
+
+// Open returns the bundled dictionary in CC-CEDICT's text format.
+func Open() (io.ReadCloser, error) {
+	return gzip.NewReader(bytes.NewReader(bundled))
+}
+
+// Each calls fn for every entry of the bundled dictionary, in file order.
+func Each(fn func(Entry)) error {
+	r, err := Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return Parse(r, fn)
+}
+
+// Parse calls fn for every entry in r, which is in CC-CEDICT's format:
+//
+//	Traditional Simplified [pin1 yin1] /gloss 1/gloss 2/
+//
+// Comments and malformed lines are skipped. The glosses may be left out.
+func Parse(r io.Reader, fn func(Entry)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		traditional, rest, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		simplified, rest, ok := strings.Cut(rest, " ")
+		if !ok || !strings.HasPrefix(rest, "[") {
+			continue
+		}
+		pinyin, rest, ok := strings.Cut(rest[1:], "]")
+		if !ok {
+			continue
+		}
+
+		e := Entry{Traditional: traditional, Simplified: simplified, Pinyin: strings.TrimSpace(pinyin)}
+		if glosses := strings.Trim(strings.TrimSpace(rest), "/"); glosses != "" {
+			e.Glosses = strings.Split(glosses, "/")
+		}
+		fn(e)
+	}
+	return sc.Err()
+}
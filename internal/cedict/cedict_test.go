@@ -0,0 +1,45 @@
+package cedict
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	input := `# CC-CEDICT
+長城 长城 [Chang2 cheng2] /the Great Wall/
+綠 绿 [lu:4] /green/
+malformed line
+兒 儿 [er5]
+`
+	var entries []Entry
+	require.NoError(t, Parse(strings.NewReader(input), func(e Entry) {
+		entries = append(entries, e)
+	}))
+
+	assert.Equal(t, []Entry{
+		{Traditional: "長城", Simplified: "长城", Pinyin: "Chang2 cheng2", Glosses: []string{"the Great Wall"}},
+		{Traditional: "綠", Simplified: "绿", Pinyin: "lu:4", Glosses: []string{"green"}},
+		{Traditional: "兒", Simplified: "儿", Pinyin: "er5"},
+	}, entries)
+}
+
+func TestEach(t *testing.T) {
+	entries := 0
+	require.NoError(t, Each(func(Entry) { entries++ }))
+	assert.Greater(t, entries, 100)
+}
+
+func TestBundledIsRelease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the bundled dictionary is the development subset until make dictionaries")
+	}
+	// gen.go refuses releases with fewer entries than this; the subset in
+	// the repo has a few hundred.
+	entries := 0
+	require.NoError(t, Each(func(Entry) { entries++ }))
+	assert.GreaterOrEqual(t, entries, 100_000, "cedict.u8.gz is the development subset; run make dictionaries")
+}
@@ -0,0 +1,65 @@
+//go:build ignore
+
+// gen downloads the current CC-CEDICT release into cedict.u8.gz. Run it with
+// go generate (or make dictionaries) to update the bundled dictionary.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/jusunglee/leagueofren/internal/cedict"
+)
+
+const (
+	releaseURL = "https://www.mdbg.net/chinese/export/cedict/cedict_1_0_ts_utf-8_mdbg.txt.gz"
+
+	// minEntries guards against replacing the dictionary with a truncated
+	// download; releases have had over 120,000 entries for years.
+	minEntries = 100_000
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	resp, err := http.Get(releaseURL)
+	if err != nil {
+		return fmt.Errorf("downloading CC-CEDICT: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading CC-CEDICT: %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("downloading CC-CEDICT: %w", err)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("decompressing CC-CEDICT: %w", err)
+	}
+	entries := 0
+	if err := cedict.Parse(zr, func(cedict.Entry) { entries++ }); err != nil {
+		return fmt.Errorf("parsing CC-CEDICT: %w", err)
+	}
+	if entries < minEntries {
+		return fmt.Errorf("CC-CEDICT download has only %d entries", entries)
+	}
+
+	// The release is already gzipped, with its licence in the header.
+	if err := os.WriteFile("cedict.u8.gz", body, 0o644); err != nil {
+		return err
+	}
+	log.Printf("wrote cedict.u8.gz: %d entries", entries)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -17,6 +18,7 @@ import (
 type Repository struct {
 	pool    *pgxpool.Pool
 	queries *sqlc.Queries
+	tx      pgx.Tx
 }
 
 // New creates a new PostgreSQL repository
@@ -63,6 +65,12 @@ func (r *Repository) Pool() *pgxpool.Pool {
 	return r.pool
 }
 
+// Tx returns the transaction a repository passed to a WithTx callback runs
+// in, so River jobs can be inserted in it, or nil outside of one.
+func (r *Repository) Tx() pgx.Tx {
+	return r.tx
+}
+
 func (r *Repository) WithTx(ctx context.Context, fn func(repo db.Repository) error) error {
 	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -81,6 +89,7 @@ func (r *Repository) WithTx(ctx context.Context, fn func(repo db.Repository) err
 	txRepo := &Repository{
 		pool:    r.pool,
 		queries: r.queries.WithTx(tx),
+		tx:      tx,
 	}
 
 	err = fn(txRepo)
@@ -234,10 +243,13 @@ func (r *Repository) FindSubscriptionsWithExpiredNewestOnlineEval(ctx context.Co
 
 func (r *Repository) CreateTranslation(ctx context.Context, arg db.CreateTranslationParams) (db.Translation, error) {
 	result, err := r.queries.CreateTranslation(ctx, sqlc.CreateTranslationParams{
-		Username:    arg.Username,
-		Translation: arg.Translation,
-		Provider:    arg.Provider,
-		Model:       arg.Model,
+		Username:        arg.Username,
+		Translation:     arg.Translation,
+		Provider:        arg.Provider,
+		Model:           arg.Model,
+		PromptVersion:   arg.PromptVersion,
+		ExamplesVersion: arg.ExamplesVersion,
+		TargetLanguage:  arg.TargetLanguage,
 	})
 	if err != nil {
 		return db.Translation{}, err
@@ -245,8 +257,11 @@ func (r *Repository) CreateTranslation(ctx context.Context, arg db.CreateTransla
 	return convertTranslation(result), nil
 }
 
-func (r *Repository) GetTranslation(ctx context.Context, username string) (db.Translation, error) {
-	result, err := r.queries.GetTranslation(ctx, username)
+func (r *Repository) GetTranslation(ctx context.Context, arg db.GetTranslationParams) (db.Translation, error) {
+	result, err := r.queries.GetTranslation(ctx, sqlc.GetTranslationParams{
+		Username:       arg.Username,
+		TargetLanguage: arg.TargetLanguage,
+	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return db.Translation{}, db.ErrNoRows
@@ -256,8 +271,11 @@ func (r *Repository) GetTranslation(ctx context.Context, username string) (db.Tr
 	return convertTranslation(result), nil
 }
 
-func (r *Repository) GetTranslations(ctx context.Context, usernames []string) ([]db.Translation, error) {
-	results, err := r.queries.GetTranslations(ctx, usernames)
+func (r *Repository) GetTranslations(ctx context.Context, arg db.GetTranslationsParams) ([]db.Translation, error) {
+	results, err := r.queries.GetTranslations(ctx, sqlc.GetTranslationsParams{
+		TargetLanguage: arg.TargetLanguage,
+		Column2:        arg.Usernames,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -279,6 +297,21 @@ func (r *Repository) CreateTranslationToEval(ctx context.Context, arg db.CreateT
 	})
 }
 
+func (r *Repository) IncrementTranslationSeenCount(ctx context.Context, arg db.IncrementTranslationSeenCountParams) error {
+	return r.queries.IncrementTranslationSeenCount(ctx, sqlc.IncrementTranslationSeenCountParams{
+		TargetLanguage: arg.TargetLanguage,
+		Column2:        arg.Usernames,
+	})
+}
+
+func (r *Repository) ListMostSeenTranslations(ctx context.Context, limit int32) ([]db.Translation, error) {
+	results, err := r.queries.ListMostSeenTranslations(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return convertTranslations(results), nil
+}
+
 // Feedback methods
 
 func (r *Repository) CreateFeedback(ctx context.Context, arg db.CreateFeedbackParams) (db.Feedback, error) {
@@ -374,6 +407,51 @@ func (r *Repository) DeleteExpiredGameCache(ctx context.Context) error {
 	return r.queries.DeleteExpiredGameCache(ctx)
 }
 
+// Server config methods
+
+func (r *Repository) GetServerConfig(ctx context.Context, serverID string) (db.ServerConfig, error) {
+	result, err := r.queries.GetServerConfig(ctx, serverID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.ServerConfig{}, db.ErrNoRows
+		}
+		return db.ServerConfig{}, err
+	}
+	return convertServerConfig(result), nil
+}
+
+func (r *Repository) UpsertServerConfig(ctx context.Context, arg db.UpsertServerConfigParams) (db.ServerConfig, error) {
+	result, err := r.queries.UpsertServerConfig(ctx, sqlc.UpsertServerConfigParams{
+		ServerID:       arg.ServerID,
+		TargetLanguage: arg.TargetLanguage,
+		Scripts:        arg.Scripts,
+	})
+	if err != nil {
+		return db.ServerConfig{}, err
+	}
+	return convertServerConfig(result), nil
+}
+
+// LLM usage methods
+
+func (r *Repository) CreateLLMUsage(ctx context.Context, arg db.CreateLLMUsageParams) error {
+	return r.queries.CreateLLMUsage(ctx, sqlc.CreateLLMUsageParams{
+		ServerID:     arg.ServerID,
+		Provider:     arg.Provider,
+		Model:        arg.Model,
+		InputTokens:  arg.InputTokens,
+		OutputTokens: arg.OutputTokens,
+		CostMicrousd: arg.CostMicroUSD,
+	})
+}
+
+func (r *Repository) SumLLMUsageCostByServerSince(ctx context.Context, serverID string, since time.Time) (int64, error) {
+	return r.queries.SumLLMUsageCostByServerSince(ctx, sqlc.SumLLMUsageCostByServerSinceParams{
+		ServerID:  serverID,
+		CreatedAt: pgtype.Timestamptz{Valid: true, Time: since},
+	})
+}
+
 // Player methods
 
 func (r *Repository) UpsertPlayer(ctx context.Context, arg db.UpsertPlayerParams) (db.Player, error) {
@@ -421,6 +499,38 @@ func (r *Repository) UpdatePlayerStats(ctx context.Context, arg db.UpdatePlayerS
 	})
 }
 
+func (r *Repository) RecordPlayerRank(ctx context.Context, arg db.RecordPlayerRankParams) (int64, error) {
+	return r.queries.RecordPlayerRank(ctx, sqlc.RecordPlayerRankParams{
+		PlayerUsername: arg.PlayerUsername,
+		Tier:           arg.Tier,
+		Division:       arg.Division,
+		LeaguePoints:   arg.LeaguePoints,
+	})
+}
+
+func (r *Repository) ListPlayerRankHistory(ctx context.Context, arg db.ListPlayerRankHistoryParams) ([]db.PlayerRank, error) {
+	results, err := r.queries.ListPlayerRankHistory(ctx, sqlc.ListPlayerRankHistoryParams{
+		PlayerUsername: arg.PlayerUsername,
+		Limit:          arg.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.PlayerRank, len(results))
+	for i, h := range results {
+		out[i] = convertPlayerRank(h)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetPeakPlayerRank(ctx context.Context, playerUsername string) (db.PlayerRank, error) {
+	h, err := r.queries.GetPeakPlayerRank(ctx, playerUsername)
+	if err != nil {
+		return db.PlayerRank{}, err
+	}
+	return convertPlayerRank(h), nil
+}
+
 // Public Translation methods
 
 func (r *Repository) UpsertPublicTranslation(ctx context.Context, arg db.UpsertPublicTranslationParams) (db.PublicTranslation, error) {
@@ -432,6 +542,8 @@ func (r *Repository) UpsertPublicTranslation(ctx context.Context, arg db.UpsertP
 		PlayerUsername: arg.PlayerUsername,
 		SourceBotID:    toPgText(arg.SourceBotID),
 		RiotVerified:   arg.RiotVerified,
+		UsernameKey:    pgtype.Text{String: arg.UsernameKey, Valid: arg.UsernameKey != ""},
+		Romanized:      arg.Romanized,
 	})
 	if err != nil {
 		return db.PublicTranslation{}, err
@@ -447,6 +559,7 @@ func (r *Repository) UpsertPublicTranslation(ctx context.Context, arg db.UpsertP
 		Upvotes:      result.Upvotes,
 		Downvotes:    result.Downvotes,
 		CreatedAt:    result.CreatedAt.Time,
+		Hidden:       result.Hidden,
 	}, nil
 }
 
@@ -461,7 +574,7 @@ func (r *Repository) GetPublicTranslation(ctx context.Context, id int64) (db.Pub
 	return convertPublicTranslationRow(result.ID, result.Username, result.Translation,
 		result.Explanation, result.Language, result.Region, result.SourceBotID,
 		result.RiotVerified, result.Rank, result.TopChampions,
-		result.Upvotes, result.Downvotes, result.CreatedAt, result.FirstSeen), nil
+		result.Upvotes, result.Downvotes, result.CreatedAt, result.FirstSeen, result.Hidden), nil
 }
 
 func (r *Repository) GetPublicTranslationByUsername(ctx context.Context, username string) (db.PublicTranslation, error) {
@@ -475,7 +588,21 @@ func (r *Repository) GetPublicTranslationByUsername(ctx context.Context, usernam
 	return convertPublicTranslationRow(result.ID, result.Username, result.Translation,
 		result.Explanation, result.Language, result.Region, result.SourceBotID,
 		result.RiotVerified, result.Rank, result.TopChampions,
-		result.Upvotes, result.Downvotes, result.CreatedAt, result.FirstSeen), nil
+		result.Upvotes, result.Downvotes, result.CreatedAt, result.FirstSeen, result.Hidden), nil
+}
+
+func (r *Repository) GetPublicTranslationByUsernameKey(ctx context.Context, usernameKey string) (db.PublicTranslation, error) {
+	result, err := r.queries.GetPublicTranslationByUsernameKey(ctx, pgtype.Text{String: usernameKey, Valid: true})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.PublicTranslation{}, db.ErrNoRows
+		}
+		return db.PublicTranslation{}, err
+	}
+	return convertPublicTranslationRow(result.ID, result.Username, result.Translation,
+		result.Explanation, result.Language, result.Region, result.SourceBotID,
+		result.RiotVerified, result.Rank, result.TopChampions,
+		result.Upvotes, result.Downvotes, result.CreatedAt, result.FirstSeen, result.Hidden), nil
 }
 
 func (r *Repository) ListPublicTranslationsNew(ctx context.Context, arg db.ListPublicTranslationsNewParams) ([]db.PublicTranslation, error) {
@@ -484,6 +611,8 @@ func (r *Repository) ListPublicTranslationsNew(ctx context.Context, arg db.ListP
 		Column2: arg.Language,
 		Limit:   arg.Limit,
 		Offset:  arg.Offset,
+		Column5: arg.AfterID,
+		Column6: pgtype.Timestamptz{Valid: true, Time: arg.AfterCreatedAt},
 	})
 	if err != nil {
 		return nil, err
@@ -493,7 +622,84 @@ func (r *Repository) ListPublicTranslationsNew(ctx context.Context, arg db.ListP
 		out[i] = convertPublicTranslationRow(r.ID, r.Username, r.Translation,
 			r.Explanation, r.Language, r.Region, r.SourceBotID,
 			r.RiotVerified, r.Rank, r.TopChampions,
-			r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen)
+			r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen, r.Hidden)
+	}
+	return out, nil
+}
+
+func (r *Repository) ListPublicTranslationsBest(ctx context.Context, arg db.ListPublicTranslationsBestParams) ([]db.ListPublicTranslationsBestRow, error) {
+	results, err := r.queries.ListPublicTranslationsBest(ctx, sqlc.ListPublicTranslationsBestParams{
+		Column1:   arg.Region,
+		Column2:   arg.Language,
+		Limit:     arg.Limit,
+		Offset:    arg.Offset,
+		CreatedAt: pgtype.Timestamptz{Valid: true, Time: arg.CreatedAt},
+		Column6:   arg.AfterID,
+		Column7:   arg.AfterScore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.ListPublicTranslationsBestRow, len(results))
+	for i, r := range results {
+		out[i] = db.ListPublicTranslationsBestRow{
+			PublicTranslation: convertPublicTranslationRow(r.ID, r.Username, r.Translation,
+				r.Explanation, r.Language, r.Region, r.SourceBotID,
+				r.RiotVerified, r.Rank, r.TopChampions,
+				r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen, r.Hidden),
+			BestScore: r.BestScore,
+		}
+	}
+	return out, nil
+}
+
+func (r *Repository) ListPublicTranslationsControversial(ctx context.Context, arg db.ListPublicTranslationsControversialParams) ([]db.ListPublicTranslationsControversialRow, error) {
+	results, err := r.queries.ListPublicTranslationsControversial(ctx, sqlc.ListPublicTranslationsControversialParams{
+		Column1:   arg.Region,
+		Column2:   arg.Language,
+		Limit:     arg.Limit,
+		Offset:    arg.Offset,
+		CreatedAt: pgtype.Timestamptz{Valid: true, Time: arg.CreatedAt},
+		Column6:   arg.AfterID,
+		Column7:   arg.AfterScore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.ListPublicTranslationsControversialRow, len(results))
+	for i, r := range results {
+		out[i] = db.ListPublicTranslationsControversialRow{
+			PublicTranslation: convertPublicTranslationRow(r.ID, r.Username, r.Translation,
+				r.Explanation, r.Language, r.Region, r.SourceBotID,
+				r.RiotVerified, r.Rank, r.TopChampions,
+				r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen, r.Hidden),
+			Controversy: r.Controversy,
+		}
+	}
+	return out, nil
+}
+
+func (r *Repository) ListPublicTranslationsHot(ctx context.Context, arg db.ListPublicTranslationsHotParams) ([]db.ListPublicTranslationsHotRow, error) {
+	results, err := r.queries.ListPublicTranslationsHot(ctx, sqlc.ListPublicTranslationsHotParams{
+		Column1: arg.Region,
+		Column2: arg.Language,
+		Limit:   arg.Limit,
+		Offset:  arg.Offset,
+		Column5: arg.AfterID,
+		Column6: arg.AfterScore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.ListPublicTranslationsHotRow, len(results))
+	for i, r := range results {
+		out[i] = db.ListPublicTranslationsHotRow{
+			PublicTranslation: convertPublicTranslationRow(r.ID, r.Username, r.Translation,
+				r.Explanation, r.Language, r.Region, r.SourceBotID,
+				r.RiotVerified, r.Rank, r.TopChampions,
+				r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen, r.Hidden),
+			HotScore: r.HotScore,
+		}
 	}
 	return out, nil
 }
@@ -505,6 +711,27 @@ func (r *Repository) ListPublicTranslationsTop(ctx context.Context, arg db.ListP
 		Limit:     arg.Limit,
 		Offset:    arg.Offset,
 		CreatedAt: pgtype.Timestamptz{Valid: true, Time: arg.CreatedAt},
+		Column6:   arg.AfterID,
+		Column7:   arg.AfterScore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.PublicTranslation, len(results))
+	for i, r := range results {
+		out[i] = convertPublicTranslationRow(r.ID, r.Username, r.Translation,
+			r.Explanation, r.Language, r.Region, r.SourceBotID,
+			r.RiotVerified, r.Rank, r.TopChampions,
+			r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen, r.Hidden)
+	}
+	return out, nil
+}
+
+func (r *Repository) ListTopVotedPublicTranslations(ctx context.Context, arg db.ListTopVotedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	results, err := r.queries.ListTopVotedPublicTranslations(ctx, sqlc.ListTopVotedPublicTranslationsParams{
+		Language: arg.Language,
+		Upvotes:  arg.MinUpvotes,
+		Limit:    arg.Limit,
 	})
 	if err != nil {
 		return nil, err
@@ -514,18 +741,137 @@ func (r *Repository) ListPublicTranslationsTop(ctx context.Context, arg db.ListP
 		out[i] = convertPublicTranslationRow(r.ID, r.Username, r.Translation,
 			r.Explanation, r.Language, r.Region, r.SourceBotID,
 			r.RiotVerified, r.Rank, r.TopChampions,
-			r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen)
+			r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen, r.Hidden)
 	}
 	return out, nil
 }
 
 func (r *Repository) CountPublicTranslations(ctx context.Context, arg db.CountPublicTranslationsParams) (int64, error) {
 	return r.queries.CountPublicTranslations(ctx, sqlc.CountPublicTranslationsParams{
-		Column1: arg.Region,
-		Column2: arg.Language,
+		Column1:   arg.Region,
+		Column2:   arg.Language,
+		CreatedAt: pgtype.Timestamptz{Valid: true, Time: arg.CreatedAt},
+	})
+}
+
+func (r *Repository) ListUnkeyedPublicTranslations(ctx context.Context, arg db.ListUnkeyedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	results, err := r.queries.ListUnkeyedPublicTranslations(ctx, sqlc.ListUnkeyedPublicTranslationsParams{
+		ID:    arg.AfterID,
+		Limit: arg.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.PublicTranslation, len(results))
+	for i, row := range results {
+		out[i] = db.PublicTranslation{ID: row.ID, Username: row.Username}
+	}
+	return out, nil
+}
+
+func (r *Repository) SetPublicTranslationUsernameKey(ctx context.Context, arg db.SetPublicTranslationUsernameKeyParams) error {
+	return r.queries.SetPublicTranslationUsernameKey(ctx, sqlc.SetPublicTranslationUsernameKeyParams{
+		ID:          arg.ID,
+		UsernameKey: pgtype.Text{String: arg.UsernameKey, Valid: true},
+	})
+}
+
+func (r *Repository) MergePublicTranslation(ctx context.Context, arg db.MergePublicTranslationParams) error {
+	return r.queries.MergePublicTranslation(ctx, sqlc.MergePublicTranslationParams{
+		FromID: arg.FromID,
+		IntoID: arg.IntoID,
+	})
+}
+
+func (r *Repository) ListUnromanizedPublicTranslations(ctx context.Context, arg db.ListUnromanizedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	results, err := r.queries.ListUnromanizedPublicTranslations(ctx, sqlc.ListUnromanizedPublicTranslationsParams{
+		ID:    arg.AfterID,
+		Limit: arg.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.PublicTranslation, len(results))
+	for i, row := range results {
+		out[i] = db.PublicTranslation{ID: row.ID, Username: row.Username}
+	}
+	return out, nil
+}
+
+func (r *Repository) SetPublicTranslationRomanized(ctx context.Context, arg db.SetPublicTranslationRomanizedParams) error {
+	return r.queries.SetPublicTranslationRomanized(ctx, sqlc.SetPublicTranslationRomanizedParams{
+		ID:        arg.ID,
+		Romanized: arg.Romanized,
+	})
+}
+
+func (r *Repository) BackfillHotScores(ctx context.Context) (int64, error) {
+	return r.queries.BackfillHotScores(ctx)
+}
+
+func (r *Repository) SearchPublicTranslations(ctx context.Context, arg db.SearchPublicTranslationsParams) ([]db.SearchPublicTranslationsRow, error) {
+	results, err := r.queries.SearchPublicTranslations(ctx, sqlc.SearchPublicTranslationsParams{
+		Column1: arg.Query,
+		Column2: arg.Key,
+		Column3: likePattern(arg.Query),
+		Column4: likePattern(arg.Key),
+		Column5: arg.Region,
+		Column6: arg.Language,
+		Limit:   arg.Limit,
+		Offset:  arg.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.SearchPublicTranslationsRow, len(results))
+	for i, r := range results {
+		out[i] = db.SearchPublicTranslationsRow{
+			PublicTranslation: convertPublicTranslationRow(r.ID, r.Username, r.Translation,
+				r.Explanation, r.Language, r.Region, r.SourceBotID,
+				r.RiotVerified, r.Rank, r.TopChampions,
+				r.Upvotes, r.Downvotes, r.CreatedAt, r.FirstSeen, r.Hidden),
+			SearchRank: r.SearchRank,
+		}
+	}
+	return out, nil
+}
+
+func (r *Repository) CountSearchPublicTranslations(ctx context.Context, arg db.CountSearchPublicTranslationsParams) (int64, error) {
+	return r.queries.CountSearchPublicTranslations(ctx, sqlc.CountSearchPublicTranslationsParams{
+		Column1: arg.Query,
+		Column2: arg.Key,
+		Column3: likePattern(arg.Query),
+		Column4: likePattern(arg.Key),
+		Column5: arg.Region,
+		Column6: arg.Language,
 	})
 }
 
+// likePattern matches s anywhere in a LIKE or ILIKE, with its wildcards
+// escaped.
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
+func (r *Repository) UpdatePublicTranslation(ctx context.Context, arg db.UpdatePublicTranslationParams) (int64, error) {
+	return r.queries.UpdatePublicTranslation(ctx, sqlc.UpdatePublicTranslationParams{
+		ID:          arg.ID,
+		Translation: toPgText(arg.Translation),
+		Explanation: toPgText(arg.Explanation),
+	})
+}
+
+func (r *Repository) SetPublicTranslationHidden(ctx context.Context, arg db.SetPublicTranslationHiddenParams) (int64, error) {
+	return r.queries.SetPublicTranslationHidden(ctx, sqlc.SetPublicTranslationHiddenParams{
+		ID:     arg.ID,
+		Hidden: arg.Hidden,
+	})
+}
+
+func (r *Repository) DeletePublicTranslation(ctx context.Context, id int64) (int64, error) {
+	return r.queries.DeletePublicTranslation(ctx, id)
+}
+
 func (r *Repository) IncrementUpvotes(ctx context.Context, id int64) error {
 	return r.queries.IncrementUpvotes(ctx, id)
 }
@@ -582,6 +928,148 @@ func (r *Repository) CountVotesByIP(ctx context.Context, ipHash string) (int64,
 	return r.queries.CountVotesByIP(ctx, ipHash)
 }
 
+func (r *Repository) IsVoteBanned(ctx context.Context, arg db.IsVoteBannedParams) (bool, error) {
+	return r.queries.IsVoteBanned(ctx, sqlc.IsVoteBannedParams{
+		IpKey:     pgtype.Text{String: arg.IpKey, Valid: arg.IpKey != ""},
+		VisitorID: pgtype.Text{String: arg.VisitorID, Valid: arg.VisitorID != ""},
+	})
+}
+
+// Translation alternative methods
+
+func (r *Repository) CreateTranslationAlternative(ctx context.Context, arg db.CreateTranslationAlternativeParams) (db.TranslationAlternative, error) {
+	result, err := r.queries.CreateTranslationAlternative(ctx, sqlc.CreateTranslationAlternativeParams{
+		TranslationID: arg.TranslationID,
+		Translation:   arg.Translation,
+		Explanation:   toPgText(arg.Explanation),
+		IpHash:        arg.IpHash,
+		VisitorID:     arg.VisitorID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.TranslationAlternative{}, db.ErrNoRows
+		}
+		return db.TranslationAlternative{}, err
+	}
+	return convertTranslationAlternative(result), nil
+}
+
+func (r *Repository) GetTranslationAlternative(ctx context.Context, id int64) (db.TranslationAlternative, error) {
+	result, err := r.queries.GetTranslationAlternative(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.TranslationAlternative{}, db.ErrNoRows
+		}
+		return db.TranslationAlternative{}, err
+	}
+	return convertTranslationAlternative(result), nil
+}
+
+func (r *Repository) ListTranslationAlternatives(ctx context.Context, translationID int64) ([]db.TranslationAlternative, error) {
+	results, err := r.queries.ListTranslationAlternatives(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.TranslationAlternative, len(results))
+	for i, a := range results {
+		out[i] = convertTranslationAlternative(a)
+	}
+	return out, nil
+}
+
+func (r *Repository) AdjustTranslationAlternativeVotes(ctx context.Context, arg db.AdjustTranslationAlternativeVotesParams) (db.TranslationAlternative, error) {
+	result, err := r.queries.AdjustTranslationAlternativeVotes(ctx, sqlc.AdjustTranslationAlternativeVotesParams{
+		ID:      arg.ID,
+		Column2: arg.Upvotes,
+		Column3: arg.Downvotes,
+	})
+	if err != nil {
+		return db.TranslationAlternative{}, err
+	}
+	return convertTranslationAlternative(result), nil
+}
+
+func (r *Repository) UpsertAlternativeVote(ctx context.Context, arg db.UpsertAlternativeVoteParams) (db.AlternativeVote, error) {
+	result, err := r.queries.UpsertAlternativeVote(ctx, sqlc.UpsertAlternativeVoteParams{
+		AlternativeID: arg.AlternativeID,
+		IpHash:        arg.IpHash,
+		VisitorID:     arg.VisitorID,
+		Vote:          arg.Vote,
+	})
+	if err != nil {
+		return db.AlternativeVote{}, err
+	}
+	return convertAlternativeVote(result), nil
+}
+
+func (r *Repository) GetAlternativeVote(ctx context.Context, arg db.GetAlternativeVoteParams) (db.AlternativeVote, error) {
+	result, err := r.queries.GetAlternativeVote(ctx, sqlc.GetAlternativeVoteParams{
+		AlternativeID: arg.AlternativeID,
+		VisitorID:     arg.VisitorID,
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return db.AlternativeVote{}, db.ErrNoRows
+		}
+		return db.AlternativeVote{}, err
+	}
+	return convertAlternativeVote(result), nil
+}
+
+func (r *Repository) DeleteAlternativeVote(ctx context.Context, arg db.DeleteAlternativeVoteParams) (int64, error) {
+	return r.queries.DeleteAlternativeVote(ctx, sqlc.DeleteAlternativeVoteParams{
+		AlternativeID: arg.AlternativeID,
+		VisitorID:     arg.VisitorID,
+	})
+}
+
+func (r *Repository) UpdateAlternativeLeads(ctx context.Context, margin int32) (int64, error) {
+	return r.queries.UpdateAlternativeLeads(ctx, margin)
+}
+
+func (r *Repository) ListPromotableAlternatives(ctx context.Context, leadingSince time.Time) ([]db.TranslationAlternative, error) {
+	results, err := r.queries.ListPromotableAlternatives(ctx, pgtype.Timestamptz{Valid: true, Time: leadingSince})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.TranslationAlternative, len(results))
+	for i, a := range results {
+		out[i] = convertTranslationAlternative(a)
+	}
+	return out, nil
+}
+
+func (r *Repository) PromoteTranslationAlternative(ctx context.Context, id int64) (int64, error) {
+	return r.queries.PromoteTranslationAlternative(ctx, id)
+}
+
+func (r *Repository) DeleteTranslationVotes(ctx context.Context, translationID int64) error {
+	return r.queries.DeleteTranslationVotes(ctx, translationID)
+}
+
+func (r *Repository) CopyAlternativeVotes(ctx context.Context, alternativeID int64) error {
+	return r.queries.CopyAlternativeVotes(ctx, alternativeID)
+}
+
+func (r *Repository) ListTranslationRevisions(ctx context.Context, translationID int64) ([]db.TranslationRevision, error) {
+	results, err := r.queries.ListTranslationRevisions(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.TranslationRevision, len(results))
+	for i, rev := range results {
+		out[i] = db.TranslationRevision{
+			ID:            rev.ID,
+			TranslationID: rev.TranslationID,
+			Translation:   rev.Translation,
+			Explanation:   fromPgText(rev.Explanation),
+			AlternativeID: fromPgInt8(rev.AlternativeID),
+			CreatedAt:     rev.CreatedAt.Time,
+		}
+	}
+	return out, nil
+}
+
 // Public Feedback methods
 
 func (r *Repository) CreatePublicFeedback(ctx context.Context, arg db.CreatePublicFeedbackParams) (db.PublicFeedback, error) {
@@ -599,13 +1087,15 @@ func (r *Repository) CreatePublicFeedback(ctx context.Context, arg db.CreatePubl
 		IpHash:        result.IpHash,
 		FeedbackText:  result.FeedbackText,
 		CreatedAt:     result.CreatedAt.Time,
+		ResolvedAt:    fromPgTimestamptz(result.ResolvedAt),
 	}, nil
 }
 
 func (r *Repository) ListPublicFeedback(ctx context.Context, arg db.ListPublicFeedbackParams) ([]db.ListPublicFeedbackRow, error) {
 	results, err := r.queries.ListPublicFeedback(ctx, sqlc.ListPublicFeedbackParams{
-		Limit:  arg.Limit,
-		Offset: arg.Offset,
+		Limit:   arg.Limit,
+		Offset:  arg.Offset,
+		Column3: arg.Resolved,
 	})
 	if err != nil {
 		return nil, err
@@ -618,6 +1108,7 @@ func (r *Repository) ListPublicFeedback(ctx context.Context, arg db.ListPublicFe
 			IpHash:        r.IpHash,
 			FeedbackText:  r.FeedbackText,
 			CreatedAt:     r.CreatedAt.Time,
+			ResolvedAt:    fromPgTimestamptz(r.ResolvedAt),
 			Username:      r.Username,
 			Translation:   r.Translation,
 		}
@@ -625,8 +1116,113 @@ func (r *Repository) ListPublicFeedback(ctx context.Context, arg db.ListPublicFe
 	return rows, nil
 }
 
-func (r *Repository) CountPublicFeedback(ctx context.Context) (int64, error) {
-	return r.queries.CountPublicFeedback(ctx)
+func (r *Repository) CountPublicFeedback(ctx context.Context, resolved bool) (int64, error) {
+	return r.queries.CountPublicFeedback(ctx, resolved)
+}
+
+func (r *Repository) ResolvePublicFeedback(ctx context.Context, id int64) (int64, error) {
+	return r.queries.ResolvePublicFeedback(ctx, id)
+}
+
+// Stream event methods
+
+func (r *Repository) CreateStreamEvent(ctx context.Context, arg db.CreateStreamEventParams) (db.StreamEvent, error) {
+	result, err := r.queries.CreateStreamEvent(ctx, sqlc.CreateStreamEventParams{
+		Type:    arg.Type,
+		Payload: arg.Payload,
+	})
+	if err != nil {
+		return db.StreamEvent{}, err
+	}
+	return convertStreamEvent(result), nil
+}
+
+func (r *Repository) NotifyStreamEvent(ctx context.Context, payload string) error {
+	return r.queries.NotifyStreamEvent(ctx, payload)
+}
+
+func (r *Repository) ListStreamEventsAfter(ctx context.Context, arg db.ListStreamEventsAfterParams) ([]db.StreamEvent, error) {
+	results, err := r.queries.ListStreamEventsAfter(ctx, sqlc.ListStreamEventsAfterParams{
+		ID:    arg.AfterID,
+		Limit: arg.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.StreamEvent, len(results))
+	for i, e := range results {
+		out[i] = convertStreamEvent(e)
+	}
+	return out, nil
+}
+
+func (r *Repository) DeleteOldStreamEvents(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteOldStreamEvents(ctx, pgtype.Timestamptz{Valid: true, Time: before})
+}
+
+// Moderation methods
+
+func (r *Repository) CreateVoteBan(ctx context.Context, arg db.CreateVoteBanParams) (db.VoteBan, error) {
+	result, err := r.queries.CreateVoteBan(ctx, sqlc.CreateVoteBanParams{
+		IpKey:     toPgText(arg.IpKey),
+		VisitorID: toPgText(arg.VisitorID),
+		Reason:    arg.Reason,
+	})
+	if err != nil {
+		return db.VoteBan{}, err
+	}
+	return convertVoteBan(result), nil
+}
+
+func (r *Repository) ListVoteBans(ctx context.Context) ([]db.VoteBan, error) {
+	results, err := r.queries.ListVoteBans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bans := make([]db.VoteBan, len(results))
+	for i, b := range results {
+		bans[i] = convertVoteBan(b)
+	}
+	return bans, nil
+}
+
+func (r *Repository) DeleteVoteBan(ctx context.Context, id int64) (int64, error) {
+	return r.queries.DeleteVoteBan(ctx, id)
+}
+
+func (r *Repository) CreateAdminAuditLog(ctx context.Context, arg db.CreateAdminAuditLogParams) error {
+	return r.queries.CreateAdminAuditLog(ctx, sqlc.CreateAdminAuditLogParams{
+		Actor:    arg.Actor,
+		Action:   arg.Action,
+		TargetID: toPgInt8(arg.TargetID),
+		Details:  arg.Details,
+	})
+}
+
+func (r *Repository) ListAdminAuditLog(ctx context.Context, arg db.ListAdminAuditLogParams) ([]db.AdminAuditLog, error) {
+	results, err := r.queries.ListAdminAuditLog(ctx, sqlc.ListAdminAuditLogParams{
+		Limit:  arg.Limit,
+		Offset: arg.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]db.AdminAuditLog, len(results))
+	for i, e := range results {
+		entries[i] = db.AdminAuditLog{
+			ID:        e.ID,
+			Actor:     e.Actor,
+			Action:    e.Action,
+			TargetID:  fromPgInt8(e.TargetID),
+			Details:   e.Details,
+			CreatedAt: e.CreatedAt.Time,
+		}
+	}
+	return entries, nil
+}
+
+func (r *Repository) CountAdminAuditLog(ctx context.Context) (int64, error) {
+	return r.queries.CountAdminAuditLog(ctx)
 }
 
 // Type conversion helpers
@@ -664,12 +1260,17 @@ func convertEval(e sqlc.Eval) db.Eval {
 
 func convertTranslation(t sqlc.Translation) db.Translation {
 	return db.Translation{
-		ID:          t.ID,
-		Username:    t.Username,
-		Translation: t.Translation,
-		Provider:    t.Provider,
-		Model:       t.Model,
-		CreatedAt:   t.CreatedAt.Time,
+		ID:              t.ID,
+		Username:        t.Username,
+		Translation:     t.Translation,
+		Provider:        t.Provider,
+		Model:           t.Model,
+		CreatedAt:       t.CreatedAt.Time,
+		PromptVersion:   t.PromptVersion,
+		SeenCount:       t.SeenCount,
+		ExamplesVersion: t.ExamplesVersion,
+		TargetLanguage:  t.TargetLanguage,
+		UpdatedAt:       t.UpdatedAt.Time,
 	}
 }
 
@@ -681,6 +1282,15 @@ func convertTranslations(translations []sqlc.Translation) []db.Translation {
 	return result
 }
 
+func convertServerConfig(c sqlc.ServerConfig) db.ServerConfig {
+	return db.ServerConfig{
+		ServerID:       c.ServerID,
+		TargetLanguage: c.TargetLanguage,
+		Scripts:        c.Scripts,
+		UpdatedAt:      c.UpdatedAt.Time,
+	}
+}
+
 func convertPlayer(p sqlc.Player) db.Player {
 	return db.Player{
 		Username:     p.Username,
@@ -693,13 +1303,24 @@ func convertPlayer(p sqlc.Player) db.Player {
 	}
 }
 
+func convertPlayerRank(h sqlc.PlayerRankHistory) db.PlayerRank {
+	return db.PlayerRank{
+		ID:             h.ID,
+		PlayerUsername: h.PlayerUsername,
+		Tier:           h.Tier,
+		Division:       h.Division,
+		LeaguePoints:   h.LeaguePoints,
+		RecordedAt:     h.RecordedAt.Time,
+	}
+}
+
 func convertPublicTranslationRow(
 	id int64, username, translation string,
 	explanation pgtype.Text, language, region string,
 	sourceBotID pgtype.Text, riotVerified bool,
 	rank, topChampions pgtype.Text,
 	upvotes, downvotes int32, createdAt pgtype.Timestamptz,
-	firstSeen pgtype.Timestamptz,
+	firstSeen pgtype.Timestamptz, hidden bool,
 ) db.PublicTranslation {
 	return db.PublicTranslation{
 		ID:           id,
@@ -716,6 +1337,17 @@ func convertPublicTranslationRow(
 		Downvotes:    downvotes,
 		CreatedAt:    createdAt.Time,
 		FirstSeen:    firstSeen.Time,
+		Hidden:       hidden,
+	}
+}
+
+func convertVoteBan(b sqlc.VoteBan) db.VoteBan {
+	return db.VoteBan{
+		ID:        b.ID,
+		IpKey:     fromPgText(b.IpKey),
+		VisitorID: fromPgText(b.VisitorID),
+		Reason:    b.Reason,
+		CreatedAt: b.CreatedAt.Time,
 	}
 }
 
@@ -730,6 +1362,42 @@ func convertVote(v sqlc.Vote) db.Vote {
 	}
 }
 
+func convertTranslationAlternative(a sqlc.TranslationAlternative) db.TranslationAlternative {
+	return db.TranslationAlternative{
+		ID:            a.ID,
+		TranslationID: a.TranslationID,
+		Translation:   a.Translation,
+		Explanation:   fromPgText(a.Explanation),
+		IpHash:        a.IpHash,
+		VisitorID:     a.VisitorID,
+		Upvotes:       a.Upvotes,
+		Downvotes:     a.Downvotes,
+		LeadingSince:  fromPgTimestamptz(a.LeadingSince),
+		PromotedAt:    fromPgTimestamptz(a.PromotedAt),
+		CreatedAt:     a.CreatedAt.Time,
+	}
+}
+
+func convertAlternativeVote(v sqlc.AlternativeVote) db.AlternativeVote {
+	return db.AlternativeVote{
+		ID:            v.ID,
+		AlternativeID: v.AlternativeID,
+		IpHash:        v.IpHash,
+		VisitorID:     v.VisitorID,
+		Vote:          v.Vote,
+		CreatedAt:     v.CreatedAt.Time,
+	}
+}
+
+func convertStreamEvent(e sqlc.StreamEvent) db.StreamEvent {
+	return db.StreamEvent{
+		ID:        e.ID,
+		Type:      e.Type,
+		Payload:   e.Payload,
+		CreatedAt: e.CreatedAt.Time,
+	}
+}
+
 func toPgInt8(n sql.NullInt64) pgtype.Int8 {
 	return pgtype.Int8{Int64: n.Int64, Valid: n.Valid}
 }
@@ -738,6 +1406,10 @@ func fromPgInt8(n pgtype.Int8) sql.NullInt64 {
 	return sql.NullInt64{Int64: n.Int64, Valid: n.Valid}
 }
 
+func fromPgTimestamptz(t pgtype.Timestamptz) sql.NullTime {
+	return sql.NullTime{Time: t.Time, Valid: t.Valid}
+}
+
 func toPgText(s sql.NullString) pgtype.Text {
 	return pgtype.Text{String: s.String, Valid: s.Valid}
 }
@@ -24,7 +24,7 @@ func newTestRepo(t *testing.T) *Repository {
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		repo.pool.Exec(context.Background(),
-			"TRUNCATE subscriptions, evals, translations, translation_to_evals, feedback, riot_account_cache, riot_game_cache CASCADE")
+			"TRUNCATE subscriptions, evals, translations, translation_to_evals, feedback, riot_account_cache, riot_game_cache, players, player_rank_history, public_translations, translation_alternatives, alternative_votes, translation_revisions, stream_events, llm_usage, server_configs, vote_bans, admin_audit_log CASCADE")
 		repo.Close()
 	})
 	return repo
@@ -139,32 +139,58 @@ func TestTranslationCRUD(t *testing.T) {
 	ctx := context.Background()
 
 	tr, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username:    "玩家",
-		Translation: "Player",
-		Provider:    "anthropic",
-		Model:       "claude-3",
+		Username:       "玩家",
+		TargetLanguage: "en",
+		Translation:    "Player",
+		Provider:       "anthropic",
+		Model:          "claude-3",
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "Player", tr.Translation)
 
-	got, err := repo.GetTranslation(ctx, "玩家")
+	got, err := repo.GetTranslation(ctx, db.GetTranslationParams{Username: "玩家", TargetLanguage: "en"})
 	require.NoError(t, err)
 	assert.Equal(t, tr.ID, got.ID)
 
-	batch, err := repo.GetTranslations(ctx, []string{"玩家", "nonexistent"})
+	batch, err := repo.GetTranslations(ctx, db.GetTranslationsParams{Usernames: []string{"玩家", "nonexistent"}, TargetLanguage: "en"})
 	require.NoError(t, err)
 	assert.Len(t, batch, 1)
 
 	// Upsert
 	updated, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username:    "玩家",
-		Translation: "Gamer",
-		Provider:    "google",
-		Model:       "gemini",
+		Username:       "玩家",
+		TargetLanguage: "en",
+		Translation:    "Gamer",
+		Provider:       "google",
+		Model:          "gemini",
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "Gamer", updated.Translation)
 	assert.Equal(t, "google", updated.Provider)
+	assert.Equal(t, tr.CreatedAt, updated.CreatedAt, "retention goes by the first write")
+	assert.True(t, updated.UpdatedAt.After(tr.UpdatedAt))
+
+	deleted, err := repo.DeleteOldTranslations(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted, "refreshing doesn't extend retention")
+}
+
+func TestServerConfig(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.GetServerConfig(ctx, "server-1")
+	assert.True(t, db.IsNoRows(err))
+
+	_, err = repo.UpsertServerConfig(ctx, db.UpsertServerConfigParams{ServerID: "server-1", TargetLanguage: "es"})
+	require.NoError(t, err)
+	_, err = repo.UpsertServerConfig(ctx, db.UpsertServerConfigParams{ServerID: "server-1", TargetLanguage: "pt", Scripts: "thai,cyrillic"})
+	require.NoError(t, err)
+
+	cfg, err := repo.GetServerConfig(ctx, "server-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pt", cfg.TargetLanguage)
+	assert.Equal(t, "thai,cyrillic", cfg.Scripts)
 }
 
 func TestFeedback(t *testing.T) {
@@ -185,12 +211,12 @@ func TestDeleteOldTranslations(t *testing.T) {
 	ctx := context.Background()
 
 	_, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username: "old_user", Translation: "Old", Provider: "test", Model: "test",
+		Username: "old_user", TargetLanguage: "en", Translation: "Old", Provider: "test", Model: "test",
 	})
 	require.NoError(t, err)
 
 	_, err = repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username: "new_user", Translation: "New", Provider: "test", Model: "test",
+		Username: "new_user", TargetLanguage: "en", Translation: "New", Provider: "test", Model: "test",
 	})
 	require.NoError(t, err)
 
@@ -198,7 +224,7 @@ func TestDeleteOldTranslations(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), deleted)
 
-	remaining, err := repo.GetTranslations(ctx, []string{"old_user", "new_user"})
+	remaining, err := repo.GetTranslations(ctx, db.GetTranslationsParams{Usernames: []string{"old_user", "new_user"}, TargetLanguage: "en"})
 	require.NoError(t, err)
 	assert.Empty(t, remaining)
 }
@@ -217,6 +243,31 @@ func TestDeleteOldFeedback(t *testing.T) {
 	assert.Equal(t, int64(1), deleted)
 }
 
+func TestLLMUsage(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, u := range []db.CreateLLMUsageParams{
+		{ServerID: "server-1", Provider: "anthropic", Model: "m", InputTokens: 100, OutputTokens: 10, CostMicroUSD: 150},
+		{ServerID: "server-1", Provider: "anthropic", Model: "m", InputTokens: 50, OutputTokens: 5, CostMicroUSD: 75},
+		{ServerID: "server-2", Provider: "anthropic", Model: "m", InputTokens: 100, OutputTokens: 10, CostMicroUSD: 150},
+	} {
+		require.NoError(t, repo.CreateLLMUsage(ctx, u))
+	}
+
+	spent, err := repo.SumLLMUsageCostByServerSince(ctx, "server-1", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(225), spent)
+
+	spent, err = repo.SumLLMUsageCostByServerSince(ctx, "server-1", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, spent)
+
+	spent, err = repo.SumLLMUsageCostByServerSince(ctx, "unknown", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, spent)
+}
+
 func TestWithTxCommit(t *testing.T) {
 	repo := newTestRepo(t)
 	ctx := context.Background()
@@ -390,7 +441,7 @@ func TestTranslationToEval(t *testing.T) {
 	require.NoError(t, err)
 
 	tr, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username: "玩家", Translation: "Player", Provider: "test", Model: "test",
+		Username: "玩家", TargetLanguage: "en", Translation: "Player", Provider: "test", Model: "test",
 	})
 	require.NoError(t, err)
 
@@ -417,3 +468,681 @@ func TestUpdateSubscriptionLastEvaluatedAt(t *testing.T) {
 	err = repo.UpdateSubscriptionLastEvaluatedAt(ctx, sub.ID)
 	require.NoError(t, err)
 }
+
+func TestTranslationPromptVersionAndSeenCount(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"一", "二", "三"} {
+		tr, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
+			Username: name, TargetLanguage: "en", Translation: "x", Provider: "test", Model: "m1", PromptVersion: "v1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "v1", tr.PromptVersion)
+		assert.Equal(t, int64(1), tr.SeenCount)
+	}
+
+	require.NoError(t, repo.IncrementTranslationSeenCount(ctx, db.IncrementTranslationSeenCountParams{Usernames: []string{"二", "三"}, TargetLanguage: "en"}))
+	require.NoError(t, repo.IncrementTranslationSeenCount(ctx, db.IncrementTranslationSeenCountParams{Usernames: []string{"三"}, TargetLanguage: "en"}))
+
+	top, err := repo.ListMostSeenTranslations(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, "三", top[0].Username)
+	assert.Equal(t, int64(3), top[0].SeenCount)
+	assert.Equal(t, "二", top[1].Username)
+
+	updated, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
+		Username: "三", TargetLanguage: "en", Translation: "Three", Provider: "test", Model: "m2", PromptVersion: "v2", ExamplesVersion: "3,7",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", updated.PromptVersion)
+	assert.Equal(t, "3,7", updated.ExamplesVersion)
+	assert.Equal(t, int64(3), updated.SeenCount)
+}
+
+func TestListTopVotedPublicTranslations(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	seed := []struct {
+		username, language string
+		up, down           int
+	}{
+		{"페이커#KR1", "korean", 50, 2},
+		{"토르소#KR1", "korean", 10, 0},
+		{"꿈을꾸다#KR1", "korean", 30, 20}, // too controversial
+		{"하늘바라기#KR1", "korean", 2, 0},  // not enough votes
+		{"大魔王#NA1", "chinese", 100, 0},
+	}
+	for _, s := range seed {
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: s.username, Region: "KR"})
+		require.NoError(t, err)
+		pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: s.username, Translation: "x", Language: s.language, PlayerUsername: s.username, UsernameKey: s.username,
+		})
+		require.NoError(t, err)
+		for range s.up {
+			require.NoError(t, repo.IncrementUpvotes(ctx, pt.ID))
+		}
+		for range s.down {
+			require.NoError(t, repo.IncrementDownvotes(ctx, pt.ID))
+		}
+	}
+
+	top, err := repo.ListTopVotedPublicTranslations(ctx, db.ListTopVotedPublicTranslationsParams{
+		Language: "korean", MinUpvotes: 5, Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, "페이커#KR1", top[0].Username)
+	assert.Equal(t, "토르소#KR1", top[1].Username)
+}
+
+func TestUpsertPublicTranslationDedupesByKey(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, username := range []string{"Faker#KR1", "Ｆａｋｅｒ#KR1"} {
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: username, Region: "KR"})
+		require.NoError(t, err)
+	}
+
+	first, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+		Username: "Faker#KR1", Translation: "Faker", Language: "korean", PlayerUsername: "Faker#KR1", UsernameKey: "Faker#KR1",
+	})
+	require.NoError(t, err)
+	second, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+		Username: "Ｆａｋｅｒ#KR1", Translation: "Faker!", Language: "korean", PlayerUsername: "Ｆａｋｅｒ#KR1", UsernameKey: "Faker#KR1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, "Faker#KR1", second.Username, "keeps the name it was first saved under")
+	assert.Equal(t, "Faker!", second.Translation)
+
+	byKey, err := repo.GetPublicTranslationByUsernameKey(ctx, "Faker#KR1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, byKey.ID)
+	_, err = repo.GetPublicTranslationByUsernameKey(ctx, "Chovy#KR1")
+	assert.True(t, db.IsNoRows(err))
+}
+
+func TestSetPublicTranslationUsernameKey(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, username := range []string{"페이커#KR1", "페이커\u200b#KR1"} {
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: username, Region: "KR"})
+		require.NoError(t, err)
+		pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: username, Translation: "Faker", Language: "korean", PlayerUsername: username,
+		})
+		require.NoError(t, err)
+		ids = append(ids, pt.ID)
+	}
+
+	unkeyed, err := repo.ListUnkeyedPublicTranslations(ctx, db.ListUnkeyedPublicTranslationsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, unkeyed, 2)
+
+	for _, id := range ids {
+		require.NoError(t, repo.SetPublicTranslationUsernameKey(ctx, db.SetPublicTranslationUsernameKeyParams{
+			ID: id, UsernameKey: "페이커#KR1",
+		}))
+	}
+
+	// The second row shares the first's key, so it's left unkeyed.
+	unkeyed, err = repo.ListUnkeyedPublicTranslations(ctx, db.ListUnkeyedPublicTranslationsParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, unkeyed, 1)
+	assert.Equal(t, ids[1], unkeyed[0].ID)
+
+	unkeyed, err = repo.ListUnkeyedPublicTranslations(ctx, db.ListUnkeyedPublicTranslationsParams{AfterID: ids[1], Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, unkeyed)
+}
+
+func TestMergePublicTranslation(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, username := range []string{"페이커#KR1", "페이커\u200b#KR1"} {
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: username, Region: "KR"})
+		require.NoError(t, err)
+		pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: username, Translation: "Faker", Language: "korean", PlayerUsername: username,
+		})
+		require.NoError(t, err)
+		ids = append(ids, pt.ID)
+	}
+	into, from := ids[0], ids[1]
+
+	votes := []db.UpsertVoteParams{
+		{TranslationID: into, IpHash: "ip-a", VisitorID: "visitor-a", Vote: 1},
+		{TranslationID: from, IpHash: "ip-a", VisitorID: "visitor-a", Vote: -1},
+		{TranslationID: from, IpHash: "ip-b", VisitorID: "visitor-b", Vote: 1},
+		{TranslationID: from, IpHash: "ip-c", VisitorID: "visitor-c", Vote: -1},
+	}
+	for _, v := range votes {
+		_, err := repo.UpsertVote(ctx, v)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.IncrementUpvotes(ctx, into))
+	_, err := repo.CreatePublicFeedback(ctx, db.CreatePublicFeedbackParams{TranslationID: from, IpHash: "ip-b", FeedbackText: "wrong"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.MergePublicTranslation(ctx, db.MergePublicTranslationParams{FromID: from, IntoID: into}))
+	_, err = repo.DeletePublicTranslation(ctx, from)
+	require.NoError(t, err)
+
+	got, err := repo.GetPublicTranslation(ctx, into)
+	require.NoError(t, err)
+	assert.Equal(t, "페이커#KR1", got.Username)
+	assert.Equal(t, int32(2), got.Upvotes, "visitor-a keeps the vote they cast on the survivor")
+	assert.Equal(t, int32(1), got.Downvotes)
+
+	vote, err := repo.GetVote(ctx, db.GetVoteParams{TranslationID: into, VisitorID: "visitor-a"})
+	require.NoError(t, err)
+	assert.Equal(t, int16(1), vote.Vote)
+
+	feedback, err := repo.ListPublicFeedback(ctx, db.ListPublicFeedbackParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, feedback, 1)
+	assert.Equal(t, into, feedback[0].TranslationID)
+}
+
+func TestHiddenPublicTranslations(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: "페이커#KR1", Region: "KR"})
+	require.NoError(t, err)
+	params := db.UpsertPublicTranslationParams{
+		Username: "페이커#KR1", Translation: "Faker", Language: "korean", PlayerUsername: "페이커#KR1", UsernameKey: "페이커#KR1",
+	}
+	pt, err := repo.UpsertPublicTranslation(ctx, params)
+	require.NoError(t, err)
+
+	n, err := repo.SetPublicTranslationHidden(ctx, db.SetPublicTranslationHiddenParams{ID: pt.ID, Hidden: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	listed, err := repo.ListPublicTranslationsNew(ctx, db.ListPublicTranslationsNewParams{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+	count, err := repo.CountPublicTranslations(ctx, db.CountPublicTranslationsParams{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// Resubmitting the name doesn't unhide it.
+	pt, err = repo.UpsertPublicTranslation(ctx, params)
+	require.NoError(t, err)
+	assert.True(t, pt.Hidden)
+
+	got, err := repo.GetPublicTranslation(ctx, pt.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Hidden)
+
+	n, err = repo.UpdatePublicTranslation(ctx, db.UpdatePublicTranslationParams{
+		ID: pt.ID, Translation: sql.NullString{String: "Unkillable Demon King", Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	got, err = repo.GetPublicTranslation(ctx, pt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Unkillable Demon King", got.Translation)
+	assert.False(t, got.Explanation.Valid)
+
+	n, err = repo.DeletePublicTranslation(ctx, pt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	_, err = repo.GetPublicTranslation(ctx, pt.ID)
+	assert.True(t, db.IsNoRows(err))
+}
+
+func TestResolvePublicFeedback(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: "페이커#KR1", Region: "KR"})
+	require.NoError(t, err)
+	pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+		Username: "페이커#KR1", Translation: "Faker", Language: "korean", PlayerUsername: "페이커#KR1", UsernameKey: "페이커#KR1",
+	})
+	require.NoError(t, err)
+	fb, err := repo.CreatePublicFeedback(ctx, db.CreatePublicFeedbackParams{TranslationID: pt.ID, IpHash: "abc", FeedbackText: "wrong"})
+	require.NoError(t, err)
+	assert.False(t, fb.ResolvedAt.Valid)
+
+	n, err := repo.ResolvePublicFeedback(ctx, fb.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	open, err := repo.CountPublicFeedback(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), open)
+
+	resolved, err := repo.ListPublicFeedback(ctx, db.ListPublicFeedbackParams{Resolved: true, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, resolved, 1)
+	assert.True(t, resolved[0].ResolvedAt.Valid)
+
+	n, err = repo.ResolvePublicFeedback(ctx, fb.ID+1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestVoteBans(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	ban, err := repo.CreateVoteBan(ctx, db.CreateVoteBanParams{
+		VisitorID: sql.NullString{String: "visitor-1", Valid: true},
+		Reason:    "vote brigading",
+	})
+	require.NoError(t, err)
+	assert.False(t, ban.IpKey.Valid)
+
+	banned, err := repo.IsVoteBanned(ctx, db.IsVoteBannedParams{IpKey: "abc", VisitorID: "visitor-1"})
+	require.NoError(t, err)
+	assert.True(t, banned)
+	banned, err = repo.IsVoteBanned(ctx, db.IsVoteBannedParams{IpKey: "abc", VisitorID: "visitor-2"})
+	require.NoError(t, err)
+	assert.False(t, banned)
+
+	ipBan, err := repo.CreateVoteBan(ctx, db.CreateVoteBanParams{
+		IpKey: sql.NullString{String: "key-1", Valid: true},
+	})
+	require.NoError(t, err)
+	banned, err = repo.IsVoteBanned(ctx, db.IsVoteBannedParams{IpKey: "key-1", VisitorID: "visitor-2"})
+	require.NoError(t, err)
+	assert.True(t, banned)
+
+	_, err = repo.CreateVoteBan(ctx, db.CreateVoteBanParams{})
+	assert.Error(t, err, "a ban needs an IP key or a visitor ID")
+
+	bans, err := repo.ListVoteBans(ctx)
+	require.NoError(t, err)
+	require.Len(t, bans, 2)
+
+	n, err := repo.DeleteVoteBan(ctx, ipBan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = repo.DeleteVoteBan(ctx, ban.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	banned, err = repo.IsVoteBanned(ctx, db.IsVoteBannedParams{IpKey: "abc", VisitorID: "visitor-1"})
+	require.NoError(t, err)
+	assert.False(t, banned)
+}
+
+func TestAdminAuditLog(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	require.NoError(t, repo.CreateAdminAuditLog(ctx, db.CreateAdminAuditLogParams{
+		Actor: "admin", Action: "translation.hide", TargetID: sql.NullInt64{Int64: 7, Valid: true},
+	}))
+	require.NoError(t, repo.CreateAdminAuditLog(ctx, db.CreateAdminAuditLogParams{
+		Actor: "api-key", Action: "ban.create", TargetID: sql.NullInt64{Int64: 1, Valid: true}, Details: []byte(`{"reason":"spam"}`),
+	}))
+
+	count, err := repo.CountAdminAuditLog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	entries, err := repo.ListAdminAuditLog(ctx, db.ListAdminAuditLogParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "ban.create", entries[0].Action)
+	assert.JSONEq(t, `{"reason":"spam"}`, string(entries[0].Details))
+	assert.Nil(t, entries[1].Details)
+}
+
+func TestSearchPublicTranslations(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, p := range []struct{ username, region, translation, romanized string }{
+		{"페이커#KR1", "KR", "Faker", "peikeo"},
+		{"大魔王#TW2", "TW", "Great Demon King", "damowang"},
+		{"Doublelift#NA1", "NA", "Doublelift", "doublelift"},
+	} {
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: p.username, Region: p.region})
+		require.NoError(t, err)
+		_, err = repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: p.username, Translation: p.translation, Language: "korean", PlayerUsername: p.username,
+			UsernameKey: p.username, Romanized: p.romanized,
+		})
+		require.NoError(t, err)
+	}
+
+	search := func(query, key string) []string {
+		t.Helper()
+		rows, err := repo.SearchPublicTranslations(ctx, db.SearchPublicTranslationsParams{Query: query, Key: key, Limit: 10})
+		require.NoError(t, err)
+		count, err := repo.CountSearchPublicTranslations(ctx, db.CountSearchPublicTranslationsParams{Query: query, Key: key})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(rows)), count)
+		var usernames []string
+		for _, r := range rows {
+			usernames = append(usernames, r.Username)
+		}
+		return usernames
+	}
+
+	assert.Equal(t, []string{"페이커#KR1"}, search("peikeo", "peikeo"), "romanized")
+	assert.Equal(t, []string{"Doublelift#NA1"}, search("lift", "lift"), "substring")
+	assert.Equal(t, []string{"大魔王#TW2"}, search("demon", "demon"), "translation text")
+	assert.Empty(t, search("100%", "100"), "LIKE wildcards are escaped")
+}
+
+func TestHotPublicTranslations(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, username := range []string{"페이커#KR1", "大魔王#TW2", "Doublelift#NA1"} {
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: username, Region: "NA"})
+		require.NoError(t, err)
+		pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: username, Translation: username, Language: "korean", PlayerUsername: username, UsernameKey: username,
+		})
+		require.NoError(t, err)
+		ids = append(ids, pt.ID)
+	}
+
+	// Ten net votes outweigh the seconds between inserts.
+	for range 10 {
+		require.NoError(t, repo.IncrementUpvotes(ctx, ids[0]))
+	}
+	require.NoError(t, repo.IncrementDownvotes(ctx, ids[0]))
+	require.NoError(t, repo.DecrementDownvotes(ctx, ids[0]))
+
+	hot := func(offset int32) []int64 {
+		t.Helper()
+		rows, err := repo.ListPublicTranslationsHot(ctx, db.ListPublicTranslationsHotParams{Limit: 2, Offset: offset})
+		require.NoError(t, err)
+		var got []int64
+		for _, r := range rows {
+			got = append(got, r.ID)
+		}
+		return got
+	}
+	assert.Equal(t, []int64{ids[0], ids[2]}, hot(0))
+	assert.Equal(t, []int64{ids[1]}, hot(2))
+
+	rows, err := repo.ListPublicTranslationsHot(ctx, db.ListPublicTranslationsHotParams{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	want := 1 + float64(rows[0].CreatedAt.UnixMicro())/1e6/45000
+	assert.InDelta(t, want, rows[0].HotScore, 1e-6)
+
+	n, err := repo.BackfillHotScores(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n, "scores are set on insert")
+}
+
+func TestBestAndControversialPublicTranslations(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	ids := map[string]int64{}
+	for _, p := range []struct {
+		username           string
+		upvotes, downvotes int
+	}{
+		{"Sweep#NA1", 3, 0},
+		{"Favorite#NA1", 95, 10},
+		{"Contested#NA1", 10, 9},
+		{"Unvoted#NA1", 0, 0},
+	} {
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: p.username, Region: "NA"})
+		require.NoError(t, err)
+		pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: p.username, Translation: p.username, Language: "korean", PlayerUsername: p.username, UsernameKey: p.username,
+		})
+		require.NoError(t, err)
+		for range p.upvotes {
+			require.NoError(t, repo.IncrementUpvotes(ctx, pt.ID))
+		}
+		for range p.downvotes {
+			require.NoError(t, repo.IncrementDownvotes(ctx, pt.ID))
+		}
+		ids[p.username] = pt.ID
+	}
+
+	best, err := repo.ListPublicTranslationsBest(ctx, db.ListPublicTranslationsBestParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, best, 2)
+	assert.Equal(t, ids["Favorite#NA1"], best[0].ID, "95-10 beats 3-0")
+	assert.Equal(t, ids["Sweep#NA1"], best[1].ID)
+	assert.InDelta(t, 0.833, best[0].BestScore, 0.01)
+
+	controversial, err := repo.ListPublicTranslationsControversial(ctx, db.ListPublicTranslationsControversialParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, controversial, 2)
+	assert.Equal(t, ids["Contested#NA1"], controversial[0].ID)
+	assert.Equal(t, ids["Favorite#NA1"], controversial[1].ID)
+
+	// Nothing is newer than an hour from now.
+	future := time.Now().Add(time.Hour)
+	best, err = repo.ListPublicTranslationsBest(ctx, db.ListPublicTranslationsBestParams{Limit: 10, CreatedAt: future})
+	require.NoError(t, err)
+	assert.Empty(t, best)
+	count, err := repo.CountPublicTranslations(ctx, db.CountPublicTranslationsParams{CreatedAt: future})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	count, err = repo.CountPublicTranslations(ctx, db.CountPublicTranslationsParams{Region: "NA"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+}
+
+func TestPublicTranslationsKeysetPagination(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	add := func(username string) int64 {
+		t.Helper()
+		_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: username, Region: "NA"})
+		require.NoError(t, err)
+		pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: username, Translation: username, Language: "korean", PlayerUsername: username, UsernameKey: username,
+		})
+		require.NoError(t, err)
+		return pt.ID
+	}
+	first, second, third := add("One#NA1"), add("Two#NA1"), add("Three#NA1")
+
+	page, err := repo.ListPublicTranslationsNew(ctx, db.ListPublicTranslationsNewParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, third, page[0].ID)
+	assert.Equal(t, second, page[1].ID)
+
+	// A translation added between pages doesn't shift the next one.
+	add("Four#NA1")
+	page, err = repo.ListPublicTranslationsNew(ctx, db.ListPublicTranslationsNewParams{
+		Limit: 2, AfterID: page[1].ID, AfterCreatedAt: page[1].CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, first, page[0].ID)
+
+	hot, err := repo.ListPublicTranslationsHot(ctx, db.ListPublicTranslationsHotParams{Limit: 3})
+	require.NoError(t, err)
+	require.Len(t, hot, 3)
+	rest, err := repo.ListPublicTranslationsHot(ctx, db.ListPublicTranslationsHotParams{
+		Limit: 3, AfterID: hot[2].ID, AfterScore: hot[2].HotScore,
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, first, rest[0].ID)
+}
+
+func TestPlayerRankHistory(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: "페이커#KR1", Region: "KR"})
+	require.NoError(t, err)
+
+	_, err = repo.GetPeakPlayerRank(ctx, "페이커#KR1")
+	assert.True(t, db.IsNoRows(err), "no history yet")
+
+	for _, rank := range []struct {
+		tier, division string
+		lp             int32
+		recorded       bool
+	}{
+		{"DIAMOND", "II", 40, true},
+		{"DIAMOND", "II", 40, false}, // unchanged
+		{"DIAMOND", "I", 75, true},
+		{"MASTER", "I", 12, true},
+		{"DIAMOND", "I", 90, true},
+	} {
+		n, err := repo.RecordPlayerRank(ctx, db.RecordPlayerRankParams{
+			PlayerUsername: "페이커#KR1", Tier: rank.tier, Division: rank.division, LeaguePoints: rank.lp,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, rank.recorded, n == 1, "%s %s %d", rank.tier, rank.division, rank.lp)
+	}
+
+	history, err := repo.ListPlayerRankHistory(ctx, db.ListPlayerRankHistoryParams{PlayerUsername: "페이커#KR1", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, int32(90), history[0].LeaguePoints, "newest first")
+	assert.Equal(t, "MASTER", history[1].Tier)
+
+	peak, err := repo.GetPeakPlayerRank(ctx, "페이커#KR1")
+	require.NoError(t, err)
+	assert.Equal(t, "MASTER", peak.Tier)
+	assert.Equal(t, int32(12), peak.LeaguePoints)
+}
+
+func TestTranslationAlternatives(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.UpsertPlayer(ctx, db.UpsertPlayerParams{Username: "大魔王#TW2", Region: "TW"})
+	require.NoError(t, err)
+	pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+		Username: "大魔王#TW2", Translation: "Big Demon King", Language: "chinese", PlayerUsername: "大魔王#TW2", UsernameKey: "大魔王#TW2",
+	})
+	require.NoError(t, err)
+	_, err = repo.UpsertVote(ctx, db.UpsertVoteParams{TranslationID: pt.ID, IpHash: "ip-a", VisitorID: "visitor-a", Vote: 1})
+	require.NoError(t, err)
+	require.NoError(t, repo.IncrementUpvotes(ctx, pt.ID))
+
+	alt, err := repo.CreateTranslationAlternative(ctx, db.CreateTranslationAlternativeParams{
+		TranslationID: pt.ID,
+		Translation:   "Great Demon King",
+		Explanation:   sql.NullString{String: "大 is great, not big, in titles", Valid: true},
+		IpHash:        "ip-b",
+		VisitorID:     "visitor-b",
+	})
+	require.NoError(t, err)
+	_, err = repo.CreateTranslationAlternative(ctx, db.CreateTranslationAlternativeParams{
+		TranslationID: pt.ID, Translation: "Demon Lord", IpHash: "ip-b", VisitorID: "visitor-b",
+	})
+	assert.True(t, db.IsNoRows(err), "one alternative per visitor")
+
+	for _, visitor := range []string{"visitor-b", "visitor-c", "visitor-d"} {
+		_, err := repo.UpsertAlternativeVote(ctx, db.UpsertAlternativeVoteParams{AlternativeID: alt.ID, IpHash: "ip-b", VisitorID: visitor, Vote: 1})
+		require.NoError(t, err)
+	}
+	alt, err = repo.AdjustTranslationAlternativeVotes(ctx, db.AdjustTranslationAlternativeVotesParams{ID: alt.ID, Upvotes: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), alt.Upvotes)
+
+	count, err := repo.CountVotesByIP(ctx, "ip-b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count, "alternative votes count toward the IP limit")
+
+	// 3 net votes against 1 is a lead at margin 2, not at margin 3.
+	n, err := repo.UpdateAlternativeLeads(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	n, err = repo.UpdateAlternativeLeads(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = repo.UpdateAlternativeLeads(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n, "a lead that continues is left alone")
+
+	promotable, err := repo.ListPromotableAlternatives(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, promotable)
+	promotable, err = repo.ListPromotableAlternatives(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, promotable, 1)
+	assert.Equal(t, alt.ID, promotable[0].ID)
+
+	err = repo.WithTx(ctx, func(txRepo db.Repository) error {
+		promoted, err := txRepo.PromoteTranslationAlternative(ctx, alt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), promoted)
+		require.NoError(t, txRepo.DeleteTranslationVotes(ctx, pt.ID))
+		return txRepo.CopyAlternativeVotes(ctx, alt.ID)
+	})
+	require.NoError(t, err)
+
+	got, err := repo.GetPublicTranslation(ctx, pt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Great Demon King", got.Translation)
+	assert.Equal(t, "大 is great, not big, in titles", got.Explanation.String)
+	assert.Equal(t, int32(3), got.Upvotes)
+	_, err = repo.GetVote(ctx, db.GetVoteParams{TranslationID: pt.ID, VisitorID: "visitor-a"})
+	assert.True(t, db.IsNoRows(err), "votes on the old version are dropped")
+	vote, err := repo.GetVote(ctx, db.GetVoteParams{TranslationID: pt.ID, VisitorID: "visitor-c"})
+	require.NoError(t, err)
+	assert.Equal(t, int16(1), vote.Vote)
+
+	count, err = repo.CountVotesByIP(ctx, "ip-b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count, "copied votes aren't counted twice")
+
+	revisions, err := repo.ListTranslationRevisions(ctx, pt.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "Big Demon King", revisions[0].Translation)
+	assert.Equal(t, alt.ID, revisions[0].AlternativeID.Int64)
+
+	alternatives, err := repo.ListTranslationAlternatives(ctx, pt.ID)
+	require.NoError(t, err)
+	assert.Empty(t, alternatives, "promoted alternatives aren't listed")
+
+	promoted, err := repo.PromoteTranslationAlternative(ctx, alt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), promoted, "an alternative is promoted once")
+}
+
+func TestStreamEvents(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, typ := range []string{"translation.created", "translation.voted", "translation.voted"} {
+		e, err := repo.CreateStreamEvent(ctx, db.CreateStreamEventParams{Type: typ, Payload: []byte(`{"id":1}`)})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":1}`, string(e.Payload))
+		ids = append(ids, e.ID)
+	}
+	require.NoError(t, repo.NotifyStreamEvent(ctx, `{"id":1}`))
+
+	after, err := repo.ListStreamEventsAfter(ctx, db.ListStreamEventsAfterParams{AfterID: ids[0], Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	assert.Equal(t, ids[1], after[0].ID, "oldest first")
+	assert.Equal(t, "translation.voted", after[0].Type)
+
+	deleted, err := repo.DeleteOldStreamEvents(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "events are recent")
+
+	deleted, err = repo.DeleteOldStreamEvents(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
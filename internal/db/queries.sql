@@ -56,18 +56,27 @@ SET last_evaluated_at = NOW()
 WHERE id = $1;
 
 -- name: CreateTranslation :one
-INSERT INTO translations (username, translation, provider, model)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (username) DO UPDATE SET translation = $2, provider = $3, model = $4
+INSERT INTO translations (username, translation, provider, model, prompt_version, examples_version, target_language)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (username, target_language) DO UPDATE SET translation = $2, provider = $3, model = $4, prompt_version = $5, examples_version = $6, updated_at = NOW()
 RETURNING *;
 
 -- name: GetTranslation :one
 SELECT * FROM translations
-WHERE username = $1;
+WHERE username = $1 AND target_language = $2;
 
 -- name: GetTranslations :many
 SELECT * FROM translations
-WHERE username = ANY($1::text[]);
+WHERE target_language = $1 AND username = ANY($2::text[]);
+
+-- name: IncrementTranslationSeenCount :exec
+UPDATE translations SET seen_count = seen_count + 1
+WHERE target_language = $1 AND username = ANY($2::text[]);
+
+-- name: ListMostSeenTranslations :many
+SELECT * FROM translations
+ORDER BY seen_count DESC, created_at DESC
+LIMIT $1;
 
 -- name: CreateEval :one
 INSERT INTO evals (subscription_id, eval_status, discord_message_id, game_id)
@@ -133,6 +142,27 @@ DELETE FROM translations WHERE created_at < $1;
 -- name: DeleteOldFeedback :execrows
 DELETE FROM feedback WHERE created_at < $1;
 
+-- Server config queries
+-- name: GetServerConfig :one
+SELECT * FROM server_configs
+WHERE server_id = $1;
+
+-- name: UpsertServerConfig :one
+INSERT INTO server_configs (server_id, target_language, scripts)
+VALUES ($1, $2, $3)
+ON CONFLICT (server_id) DO UPDATE SET target_language = $2, scripts = $3, updated_at = NOW()
+RETURNING *;
+
+-- LLM usage queries
+-- name: CreateLLMUsage :exec
+INSERT INTO llm_usage (server_id, provider, model, input_tokens, output_tokens, cost_microusd)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: SumLLMUsageCostByServerSince :one
+SELECT COALESCE(SUM(cost_microusd), 0)::bigint
+FROM llm_usage
+WHERE server_id = $1 AND created_at >= $2;
+
 -- ===========================================
 -- Companion Website Queries
 -- ===========================================
@@ -160,16 +190,49 @@ SELECT * FROM players ORDER BY username;
 UPDATE players SET rank = $2, top_champions = $3, last_updated = NOW()
 WHERE username = $1;
 
+-- Appends a rank unless it is the player's latest already
+-- name: RecordPlayerRank :execrows
+INSERT INTO player_rank_history (player_username, tier, division, league_points)
+SELECT $1, $2, $3, $4
+WHERE NOT EXISTS (
+    SELECT 1 FROM (
+        SELECT tier, division, league_points FROM player_rank_history
+        WHERE player_username = $1
+        ORDER BY recorded_at DESC, id DESC
+        LIMIT 1
+    ) latest
+    WHERE latest.tier = $2 AND latest.division = $3 AND latest.league_points = $4
+);
+
+-- name: ListPlayerRankHistory :many
+SELECT id, player_username, tier, division, league_points, recorded_at FROM player_rank_history
+WHERE player_username = $1
+ORDER BY recorded_at DESC, id DESC
+LIMIT $2;
+
+-- name: GetPeakPlayerRank :one
+SELECT id, player_username, tier, division, league_points, recorded_at FROM player_rank_history
+WHERE player_username = $1
+ORDER BY CASE tier
+             WHEN 'CHALLENGER' THEN 9 WHEN 'GRANDMASTER' THEN 8 WHEN 'MASTER' THEN 7
+             WHEN 'DIAMOND' THEN 6 WHEN 'EMERALD' THEN 5 WHEN 'PLATINUM' THEN 4
+             WHEN 'GOLD' THEN 3 WHEN 'SILVER' THEN 2 WHEN 'BRONZE' THEN 1 ELSE 0
+         END DESC,
+         CASE division WHEN 'I' THEN 4 WHEN 'II' THEN 3 WHEN 'III' THEN 2 ELSE 1 END DESC,
+         league_points DESC, recorded_at
+LIMIT 1;
+
 -- Public translation queries (JOIN against players for region/rank/top_champions)
 
 -- name: UpsertPublicTranslation :one
-INSERT INTO public_translations (username, translation, explanation, language, player_username, source_bot_id, riot_verified)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-ON CONFLICT (username) DO UPDATE SET
+-- A name resubmitted under a lookalike spelling keeps the username and player
+-- it was first saved under.
+INSERT INTO public_translations (username, translation, explanation, language, player_username, source_bot_id, riot_verified, username_key, romanized, hot_score)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, EXTRACT(EPOCH FROM NOW())::float8 / 45000)
+ON CONFLICT (username_key) DO UPDATE SET
     translation = EXCLUDED.translation,
     explanation = EXCLUDED.explanation,
     language = EXCLUDED.language,
-    player_username = EXCLUDED.player_username,
     source_bot_id = EXCLUDED.source_bot_id,
     riot_verified = EXCLUDED.riot_verified
 RETURNING *;
@@ -177,7 +240,7 @@ RETURNING *;
 -- name: GetPublicTranslation :one
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
 WHERE pt.id = $1;
@@ -185,52 +248,239 @@ WHERE pt.id = $1;
 -- name: GetPublicTranslationByUsername :one
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
 WHERE pt.username = $1;
 
+-- name: GetPublicTranslationByUsernameKey :one
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE pt.username_key = $1;
+
 -- name: ListPublicTranslationsNew :many
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
-WHERE ($1::text = '' OR p.region = $1)
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
   AND ($2::text = '' OR pt.language = $2)
-ORDER BY pt.created_at DESC
+  AND ($5::bigint = 0 OR (pt.created_at, pt.id) < ($6::timestamptz, $5::bigint))
+ORDER BY pt.created_at DESC, pt.id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: ListPublicTranslationsBest :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden, pt.best_score
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
+  AND ($2::text = '' OR pt.language = $2)
+  AND pt.created_at > $5
+  AND ($6::bigint = 0 OR (pt.best_score, pt.id) < ($7::float8, $6::bigint))
+ORDER BY pt.best_score DESC, pt.id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: ListPublicTranslationsControversial :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden, pt.controversy
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
+  AND ($2::text = '' OR pt.language = $2)
+  AND pt.created_at > $5
+  AND ($6::bigint = 0 OR (pt.controversy, pt.id) < ($7::float8, $6::bigint))
+ORDER BY pt.controversy DESC, pt.id DESC
+LIMIT $3 OFFSET $4;
+
+-- name: ListPublicTranslationsHot :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden, pt.hot_score
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
+  AND ($2::text = '' OR pt.language = $2)
+  AND ($5::bigint = 0 OR (pt.hot_score, pt.id) < ($6::float8, $5::bigint))
+ORDER BY pt.hot_score DESC, pt.id DESC
 LIMIT $3 OFFSET $4;
 
 -- name: ListPublicTranslationsTop :many
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
-WHERE ($1::text = '' OR p.region = $1)
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
   AND ($2::text = '' OR pt.language = $2)
   AND pt.created_at > $5
-ORDER BY (pt.upvotes - pt.downvotes) DESC, pt.created_at DESC
+  AND ($6::bigint = 0 OR (pt.upvotes - pt.downvotes, pt.id) < ($7::float8, $6::bigint))
+ORDER BY (pt.upvotes - pt.downvotes) DESC, pt.id DESC
 LIMIT $3 OFFSET $4;
 
+-- Few-shot examples for the translator: well-liked, rarely disputed translations
+-- name: ListTopVotedPublicTranslations :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND pt.language = $1
+  AND pt.upvotes >= $2
+  AND pt.downvotes * 5 <= pt.upvotes
+ORDER BY (pt.upvotes - pt.downvotes) DESC, pt.id
+LIMIT $3;
+
 -- name: CountPublicTranslations :one
 SELECT COUNT(*)
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
-WHERE ($1::text = '' OR p.region = $1)
-  AND ($2::text = '' OR pt.language = $2);
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
+  AND ($2::text = '' OR pt.language = $2)
+  AND pt.created_at > $3;
+
+-- Backfill of username_key for rows written before it existed
+-- name: ListUnkeyedPublicTranslations :many
+SELECT id, username FROM public_translations
+WHERE username_key IS NULL AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- Leaves the key unset when another row already has it: that row is the same
+-- name, and keeps receiving its submissions.
+-- name: SetPublicTranslationUsernameKey :exec
+UPDATE public_translations SET username_key = $2
+WHERE id = $1
+  AND NOT EXISTS (SELECT 1 FROM public_translations WHERE username_key = $2);
+
+-- name: MergePublicTranslation :exec
+-- Moves the votes, alternatives, revisions and feedback of a duplicate of a
+-- translation onto it, adding the moved votes to its counts. A visitor who
+-- voted on (or suggested an alternative for) both keeps their vote on the
+-- survivor. The duplicate is deleted afterwards, taking what wasn't moved.
+WITH moved_votes AS (
+    UPDATE votes SET translation_id = @into_id
+    WHERE translation_id = @from_id
+      AND visitor_id NOT IN (SELECT visitor_id FROM votes WHERE translation_id = @into_id)
+    RETURNING vote
+), moved_alternatives AS (
+    UPDATE translation_alternatives SET translation_id = @into_id
+    WHERE translation_id = @from_id
+      AND visitor_id NOT IN (SELECT visitor_id FROM translation_alternatives WHERE translation_id = @into_id)
+), moved_revisions AS (
+    UPDATE translation_revisions SET translation_id = @into_id WHERE translation_id = @from_id
+), moved_feedback AS (
+    UPDATE public_feedback SET translation_id = @into_id WHERE translation_id = @from_id
+)
+UPDATE public_translations SET
+    upvotes = upvotes + (SELECT COUNT(*) FROM moved_votes WHERE vote = 1),
+    downvotes = downvotes + (SELECT COUNT(*) FROM moved_votes WHERE vote = -1),
+    hot_score = LOG(GREATEST(ABS(upvotes + (SELECT COUNT(*) FROM moved_votes WHERE vote = 1)
+                                 - downvotes - (SELECT COUNT(*) FROM moved_votes WHERE vote = -1)), 1)::float8)
+                + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = @into_id;
+
+-- Ranked search over usernames (substring or trigram), their romanizations and
+-- the translation text. $1 is the query as typed and $2 its SearchKey; $3 and $4
+-- are LIKE patterns for them, with wildcards escaped.
+-- name: SearchPublicTranslations :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden,
+       (GREATEST(
+            similarity(pt.username, $1::text),
+            CASE WHEN $2::text = '' THEN 0 ELSE similarity(pt.romanized, $2::text) END,
+            ts_rank(to_tsvector('english', pt.translation || ' ' || COALESCE(pt.explanation, '')), plainto_tsquery('english', $1::text))
+        ) + CASE WHEN pt.username ILIKE $3::text OR ($2::text <> '' AND pt.romanized LIKE $4::text) THEN 1 ELSE 0 END)::float8 AS search_rank
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($5::text = '' OR p.region = $5)
+  AND ($6::text = '' OR pt.language = $6)
+  AND (pt.username ILIKE $3::text
+       OR pt.username % $1::text
+       OR ($2::text <> '' AND (pt.romanized LIKE $4::text OR pt.romanized % $2::text))
+       OR to_tsvector('english', pt.translation || ' ' || COALESCE(pt.explanation, '')) @@ plainto_tsquery('english', $1::text))
+ORDER BY search_rank DESC, (pt.upvotes - pt.downvotes) DESC, pt.id DESC
+LIMIT $7 OFFSET $8;
+
+-- name: CountSearchPublicTranslations :one
+SELECT COUNT(*)
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($5::text = '' OR p.region = $5)
+  AND ($6::text = '' OR pt.language = $6)
+  AND (pt.username ILIKE $3::text
+       OR pt.username % $1::text
+       OR ($2::text <> '' AND (pt.romanized LIKE $4::text OR pt.romanized % $2::text))
+       OR to_tsvector('english', pt.translation || ' ' || COALESCE(pt.explanation, '')) @@ plainto_tsquery('english', $1::text));
+
+-- Backfill of hot_score for rows written before it existed
+-- name: BackfillHotScores :execrows
+UPDATE public_translations
+SET hot_score = LOG(GREATEST(ABS(upvotes - downvotes), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE hot_score = 0;
+
+-- Backfill of romanized for rows written before it existed
+-- name: ListUnromanizedPublicTranslations :many
+SELECT id, username FROM public_translations
+WHERE romanized = '' AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: SetPublicTranslationRomanized :exec
+UPDATE public_translations SET romanized = $2 WHERE id = $1;
+
+-- Moderator edit; NULL leaves a column as it is
+-- name: UpdatePublicTranslation :execrows
+UPDATE public_translations SET
+    translation = COALESCE($2, translation),
+    explanation = COALESCE($3, explanation)
+WHERE id = $1;
+
+-- name: SetPublicTranslationHidden :execrows
+UPDATE public_translations SET hidden = $2 WHERE id = $1;
+
+-- name: DeletePublicTranslation :execrows
+DELETE FROM public_translations WHERE id = $1;
 
 -- name: IncrementUpvotes :exec
-UPDATE public_translations SET upvotes = upvotes + 1 WHERE id = $1;
+UPDATE public_translations
+SET upvotes = upvotes + 1,
+    hot_score = LOG(GREATEST(ABS(upvotes + 1 - downvotes), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1;
 
 -- name: DecrementUpvotes :exec
-UPDATE public_translations SET upvotes = upvotes - 1 WHERE id = $1;
+UPDATE public_translations
+SET upvotes = upvotes - 1,
+    hot_score = LOG(GREATEST(ABS(upvotes - 1 - downvotes), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1;
 
 -- name: IncrementDownvotes :exec
-UPDATE public_translations SET downvotes = downvotes + 1 WHERE id = $1;
+UPDATE public_translations
+SET downvotes = downvotes + 1,
+    hot_score = LOG(GREATEST(ABS(upvotes - (downvotes + 1)), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1;
 
 -- name: DecrementDownvotes :exec
-UPDATE public_translations SET downvotes = downvotes - 1 WHERE id = $1;
+UPDATE public_translations
+SET downvotes = downvotes - 1,
+    hot_score = LOG(GREATEST(ABS(upvotes - (downvotes - 1)), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1;
 
 -- name: UpsertVote :one
 INSERT INTO votes (translation_id, ip_hash, visitor_id, vote)
@@ -244,8 +494,14 @@ SELECT * FROM votes WHERE translation_id = $1 AND visitor_id = $2;
 -- name: DeleteVote :execrows
 DELETE FROM votes WHERE translation_id = $1 AND visitor_id = $2;
 
+-- Votes on translations and on their alternatives share one per-IP limit.
+-- A promoted alternative's votes were copied into votes, so they're only
+-- counted there.
 -- name: CountVotesByIP :one
-SELECT COUNT(*) FROM votes WHERE ip_hash = $1;
+SELECT (SELECT COUNT(*) FROM votes WHERE votes.ip_hash = $1)
+     + (SELECT COUNT(*) FROM alternative_votes av
+        JOIN translation_alternatives ta ON ta.id = av.alternative_id
+        WHERE av.ip_hash = $1 AND ta.promoted_at IS NULL) AS count;
 
 -- name: CreatePublicFeedback :one
 INSERT INTO public_feedback (translation_id, ip_hash, feedback_text)
@@ -256,8 +512,159 @@ RETURNING *;
 SELECT pf.*, pt.username, pt.translation
 FROM public_feedback pf
 JOIN public_translations pt ON pt.id = pf.translation_id
+WHERE (pf.resolved_at IS NOT NULL) = $3::boolean
 ORDER BY pf.created_at DESC
 LIMIT $1 OFFSET $2;
 
 -- name: CountPublicFeedback :one
-SELECT COUNT(*) FROM public_feedback;
+SELECT COUNT(*) FROM public_feedback WHERE (resolved_at IS NOT NULL) = $1::boolean;
+
+-- name: ResolvePublicFeedback :execrows
+UPDATE public_feedback SET resolved_at = COALESCE(resolved_at, NOW()) WHERE id = $1;
+
+-- Translation alternative queries
+
+-- name: CreateTranslationAlternative :one
+INSERT INTO translation_alternatives (translation_id, translation, explanation, ip_hash, visitor_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (translation_id, visitor_id) DO NOTHING
+RETURNING *;
+
+-- name: GetTranslationAlternative :one
+SELECT * FROM translation_alternatives WHERE id = $1;
+
+-- name: ListTranslationAlternatives :many
+SELECT * FROM translation_alternatives
+WHERE translation_id = $1 AND promoted_at IS NULL
+ORDER BY (upvotes - downvotes) DESC, id;
+
+-- name: AdjustTranslationAlternativeVotes :one
+UPDATE translation_alternatives
+SET upvotes = upvotes + $2::int,
+    downvotes = downvotes + $3::int
+WHERE id = $1
+RETURNING *;
+
+-- name: UpsertAlternativeVote :one
+INSERT INTO alternative_votes (alternative_id, ip_hash, visitor_id, vote)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (alternative_id, visitor_id) DO UPDATE SET vote = $4
+RETURNING *;
+
+-- name: GetAlternativeVote :one
+SELECT * FROM alternative_votes WHERE alternative_id = $1 AND visitor_id = $2;
+
+-- name: DeleteAlternativeVote :execrows
+DELETE FROM alternative_votes WHERE alternative_id = $1 AND visitor_id = $2;
+
+-- Starts the lead of every alternative now at least $1 net votes ahead of its
+-- translation, and ends the lead of those that fell back.
+-- name: UpdateAlternativeLeads :execrows
+UPDATE translation_alternatives ta
+SET leading_since = CASE WHEN ta.leading_since IS NULL THEN NOW() END
+FROM public_translations pt
+WHERE pt.id = ta.translation_id
+  AND ta.promoted_at IS NULL
+  AND NOT pt.hidden
+  AND (ta.leading_since IS NULL) = (ta.upvotes - ta.downvotes >= pt.upvotes - pt.downvotes + $1::int);
+
+-- The alternatives that have led their translation since $1, the best-voted
+-- one per translation.
+-- name: ListPromotableAlternatives :many
+SELECT DISTINCT ON (translation_id) * FROM translation_alternatives
+WHERE promoted_at IS NULL AND leading_since <= $1
+ORDER BY translation_id, (upvotes - downvotes) DESC, leading_since, id;
+
+-- Saves the translation's current version as a revision and replaces it with
+-- the alternative, votes counts included. The translation's other alternatives
+-- lose their leads, since they were measured against the old version.
+-- name: PromoteTranslationAlternative :execrows
+WITH alternative AS (
+    SELECT id, translation_id, translation, explanation, upvotes, downvotes
+    FROM translation_alternatives
+    WHERE translation_alternatives.id = $1 AND promoted_at IS NULL
+), revision AS (
+    INSERT INTO translation_revisions (translation_id, translation, explanation, alternative_id)
+    SELECT pt.id, pt.translation, pt.explanation, alternative.id
+    FROM public_translations pt
+    JOIN alternative ON alternative.translation_id = pt.id
+), promoted AS (
+    UPDATE translation_alternatives ta
+    SET leading_since = NULL,
+        promoted_at = CASE WHEN ta.id = alternative.id THEN NOW() ELSE ta.promoted_at END
+    FROM alternative
+    WHERE ta.translation_id = alternative.translation_id
+)
+UPDATE public_translations pt
+SET translation = alternative.translation,
+    explanation = alternative.explanation,
+    upvotes = alternative.upvotes,
+    downvotes = alternative.downvotes,
+    hot_score = LOG(GREATEST(ABS(alternative.upvotes - alternative.downvotes), 1)::float8) + EXTRACT(EPOCH FROM pt.created_at)::float8 / 45000
+FROM alternative
+WHERE pt.id = alternative.translation_id;
+
+-- name: DeleteTranslationVotes :exec
+DELETE FROM votes WHERE translation_id = $1;
+
+-- Carries an alternative's votes over to its translation once promoted.
+-- name: CopyAlternativeVotes :exec
+INSERT INTO votes (translation_id, ip_hash, visitor_id, vote)
+SELECT ta.translation_id, av.ip_hash, av.visitor_id, av.vote
+FROM alternative_votes av
+JOIN translation_alternatives ta ON ta.id = av.alternative_id
+WHERE av.alternative_id = $1;
+
+-- name: ListTranslationRevisions :many
+SELECT * FROM translation_revisions
+WHERE translation_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- Stream event queries
+
+-- name: CreateStreamEvent :one
+INSERT INTO stream_events (type, payload)
+VALUES ($1, $2)
+RETURNING *;
+
+-- Sends $1 to the web instances listening on the stream_events channel, when
+-- the surrounding transaction commits.
+-- name: NotifyStreamEvent :exec
+SELECT pg_notify('stream_events', $1::text);
+
+-- name: ListStreamEventsAfter :many
+SELECT * FROM stream_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: DeleteOldStreamEvents :execrows
+DELETE FROM stream_events WHERE created_at < $1;
+
+-- Moderation queries
+
+-- name: CreateVoteBan :one
+INSERT INTO vote_bans (ip_key, visitor_id, reason)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListVoteBans :many
+SELECT * FROM vote_bans ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteVoteBan :execrows
+DELETE FROM vote_bans WHERE id = $1;
+
+-- name: IsVoteBanned :one
+SELECT EXISTS (SELECT 1 FROM vote_bans WHERE ip_key = $1 OR visitor_id = $2);
+
+-- name: CreateAdminAuditLog :exec
+INSERT INTO admin_audit_log (actor, action, target_id, details)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListAdminAuditLog :many
+SELECT * FROM admin_audit_log
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountAdminAuditLog :one
+SELECT COUNT(*) FROM admin_audit_log;
@@ -30,6 +30,30 @@ type UpdatePlayerStatsParams struct {
 	TopChampions sql.NullString
 }
 
+// PlayerRank is a player's solo queue rank at one point in time.
+type PlayerRank struct {
+	ID             int64
+	PlayerUsername string
+	Tier           string
+	Division       string
+	LeaguePoints   int32
+	RecordedAt     time.Time
+}
+
+type RecordPlayerRankParams struct {
+	PlayerUsername string
+	Tier           string
+	Division       string
+	LeaguePoints   int32
+}
+
+// ListPlayerRankHistoryParams lists a player's Limit most recent ranks, newest
+// first.
+type ListPlayerRankHistoryParams struct {
+	PlayerUsername string
+	Limit          int32
+}
+
 type PublicTranslation struct {
 	ID           int64
 	Username     string
@@ -45,6 +69,8 @@ type PublicTranslation struct {
 	Downvotes    int32
 	CreatedAt    time.Time
 	FirstSeen    time.Time
+	// Hidden translations are left out of public listings.
+	Hidden bool
 }
 
 // Vote represents an IP-hashed vote on a public translation
@@ -64,6 +90,7 @@ type PublicFeedback struct {
 	IpHash        string
 	FeedbackText  string
 	CreatedAt     time.Time
+	ResolvedAt    sql.NullTime
 }
 
 type UpsertPublicTranslationParams struct {
@@ -74,26 +101,162 @@ type UpsertPublicTranslationParams struct {
 	PlayerUsername string
 	SourceBotID    sql.NullString
 	RiotVerified   bool
+	// UsernameKey is the canonical form of Username that submissions are
+	// deduplicated on; see transliteration.CanonicalKey.
+	UsernameKey string
+	// Romanized is the name's transliteration.SearchKey.
+	Romanized string
+}
+
+// ListPublicTranslationsNewParams lists translations newest first. With
+// AfterID set, the list resumes after the row with that ID and AfterCreatedAt
+// (keyset pagination) and Offset is normally 0. The other list params page the
+// same way, by AfterID and the AfterScore the previous page's last row was
+// ranked by.
+type ListPublicTranslationsNewParams struct {
+	Region         string
+	Language       string
+	Limit          int32
+	Offset         int32
+	AfterID        int64
+	AfterCreatedAt time.Time
 }
 
-type ListPublicTranslationsNewParams struct {
-	Region   string
-	Language string
-	Limit    int32
-	Offset   int32
+type ListPublicTranslationsBestParams struct {
+	Region     string
+	Language   string
+	Limit      int32
+	Offset     int32
+	CreatedAt  time.Time
+	AfterID    int64
+	AfterScore float64
+}
+
+// ListPublicTranslationsBestRow is a translation with the lower bound of the
+// Wilson score interval for its share of upvotes, which it was ranked by.
+type ListPublicTranslationsBestRow struct {
+	PublicTranslation
+	BestScore float64
+}
+
+type ListPublicTranslationsControversialParams struct {
+	Region     string
+	Language   string
+	Limit      int32
+	Offset     int32
+	CreatedAt  time.Time
+	AfterID    int64
+	AfterScore float64
+}
+
+// ListPublicTranslationsControversialRow is a translation with the
+// controversy it was ranked by: high when many votes split close to evenly.
+type ListPublicTranslationsControversialRow struct {
+	PublicTranslation
+	Controversy float64
+}
+
+type ListPublicTranslationsHotParams struct {
+	Region     string
+	Language   string
+	Limit      int32
+	Offset     int32
+	AfterID    int64
+	AfterScore float64
+}
+
+// ListPublicTranslationsHotRow is a translation with the hot score it was
+// ranked by.
+type ListPublicTranslationsHotRow struct {
+	PublicTranslation
+	HotScore float64
 }
 
 type ListPublicTranslationsTopParams struct {
+	Region     string
+	Language   string
+	Limit      int32
+	Offset     int32
+	CreatedAt  time.Time
+	AfterID    int64
+	AfterScore float64
+}
+
+type ListTopVotedPublicTranslationsParams struct {
+	Language   string
+	MinUpvotes int32
+	Limit      int32
+}
+
+// CountPublicTranslationsParams counts the translations in the optional region
+// and language created after CreatedAt.
+type CountPublicTranslationsParams struct {
 	Region    string
 	Language  string
-	Limit     int32
-	Offset    int32
 	CreatedAt time.Time
 }
 
-type CountPublicTranslationsParams struct {
+type ListUnkeyedPublicTranslationsParams struct {
+	AfterID int64
+	Limit   int32
+}
+
+type SetPublicTranslationUsernameKeyParams struct {
+	ID          int64
+	UsernameKey string
+}
+
+type MergePublicTranslationParams struct {
+	FromID int64
+	IntoID int64
+}
+
+type ListUnromanizedPublicTranslationsParams struct {
+	AfterID int64
+	Limit   int32
+}
+
+type SetPublicTranslationRomanizedParams struct {
+	ID        int64
+	Romanized string
+}
+
+// SearchPublicTranslationsParams searches for Query, typed by a user, and for
+// Key, its transliteration.SearchKey, within the optional region and language.
+type SearchPublicTranslationsParams struct {
+	Query    string
+	Key      string
 	Region   string
 	Language string
+	Limit    int32
+	Offset   int32
+}
+
+type CountSearchPublicTranslationsParams struct {
+	Query    string
+	Key      string
+	Region   string
+	Language string
+}
+
+// SearchPublicTranslationsRow is a search result with its relevance; higher
+// ranks match better.
+type SearchPublicTranslationsRow struct {
+	PublicTranslation
+	SearchRank float64
+}
+
+// UpdatePublicTranslationParams edits a translation; unset fields are left
+// as they are.
+type UpdatePublicTranslationParams struct {
+	ID          int64
+	Translation sql.NullString
+	Explanation sql.NullString
+}
+
+type SetPublicTranslationHiddenParams struct {
+	ID     int64
+	Hidden bool
 }
 
 type UpsertVoteParams struct {
@@ -113,6 +276,80 @@ type DeleteVoteParams struct {
 	VisitorID     string
 }
 
+// TranslationAlternative is a visitor's suggested replacement for a public
+// translation, voted on like the translation itself.
+type TranslationAlternative struct {
+	ID            int64
+	TranslationID int64
+	Translation   string
+	Explanation   sql.NullString
+	IpHash        string
+	VisitorID     string
+	Upvotes       int32
+	Downvotes     int32
+	// LeadingSince is when the alternative last started leading the
+	// translation by the promotion margin; NULL while it doesn't.
+	LeadingSince sql.NullTime
+	PromotedAt   sql.NullTime
+	CreatedAt    time.Time
+}
+
+type CreateTranslationAlternativeParams struct {
+	TranslationID int64
+	Translation   string
+	Explanation   sql.NullString
+	IpHash        string
+	VisitorID     string
+}
+
+// AdjustTranslationAlternativeVotesParams adds Upvotes and Downvotes, each
+// -1, 0 or 1, to an alternative's vote counts.
+type AdjustTranslationAlternativeVotesParams struct {
+	ID        int64
+	Upvotes   int32
+	Downvotes int32
+}
+
+// AlternativeVote is an IP-hashed vote on a translation alternative
+type AlternativeVote struct {
+	ID            int64
+	AlternativeID int64
+	IpHash        string
+	VisitorID     string
+	Vote          int16
+	CreatedAt     time.Time
+}
+
+type UpsertAlternativeVoteParams struct {
+	AlternativeID int64
+	IpHash        string
+	VisitorID     string
+	Vote          int16
+}
+
+type GetAlternativeVoteParams struct {
+	AlternativeID int64
+	VisitorID     string
+}
+
+type DeleteAlternativeVoteParams struct {
+	AlternativeID int64
+	VisitorID     string
+}
+
+// TranslationRevision is a version of a public translation that a promoted
+// alternative replaced.
+type TranslationRevision struct {
+	ID            int64
+	TranslationID int64
+	Translation   string
+	Explanation   sql.NullString
+	// AlternativeID is the alternative that replaced this version, NULL once
+	// that alternative is deleted.
+	AlternativeID sql.NullInt64
+	CreatedAt     time.Time
+}
+
 type CreatePublicFeedbackParams struct {
 	TranslationID int64
 	IpHash        string
@@ -120,8 +357,9 @@ type CreatePublicFeedbackParams struct {
 }
 
 type ListPublicFeedbackParams struct {
-	Limit  int32
-	Offset int32
+	Resolved bool
+	Limit    int32
+	Offset   int32
 }
 
 type ListPublicFeedbackRow struct {
@@ -130,10 +368,77 @@ type ListPublicFeedbackRow struct {
 	IpHash        string
 	FeedbackText  string
 	CreatedAt     time.Time
+	ResolvedAt    sql.NullTime
 	Username      string
 	Translation   string
 }
 
+// VoteBan bars a voter from voting, by exactly one of IP key or visitor ID.
+// An IP key is a keyed hash of the address that, unlike the daily IP hash
+// votes are stored with, stays the same from day to day.
+type VoteBan struct {
+	ID        int64
+	IpKey     sql.NullString
+	VisitorID sql.NullString
+	Reason    string
+	CreatedAt time.Time
+}
+
+type CreateVoteBanParams struct {
+	IpKey     sql.NullString
+	VisitorID sql.NullString
+	Reason    string
+}
+
+type IsVoteBannedParams struct {
+	IpKey     string
+	VisitorID string
+}
+
+// StreamEvent is an event pushed to the website's live stream. Payload is
+// its JSON data.
+type StreamEvent struct {
+	ID        int64
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+type CreateStreamEventParams struct {
+	Type    string
+	Payload []byte
+}
+
+// ListStreamEventsAfterParams lists up to Limit events after the one with ID
+// AfterID, oldest first.
+type ListStreamEventsAfterParams struct {
+	AfterID int64
+	Limit   int32
+}
+
+// AdminAuditLog records one action taken through the admin API
+type AdminAuditLog struct {
+	ID       int64
+	Actor    string
+	Action   string
+	TargetID sql.NullInt64
+	// Details is a JSON object describing the action, or nil.
+	Details   []byte
+	CreatedAt time.Time
+}
+
+type CreateAdminAuditLogParams struct {
+	Actor    string
+	Action   string
+	TargetID sql.NullInt64
+	Details  []byte
+}
+
+type ListAdminAuditLogParams struct {
+	Limit  int32
+	Offset int32
+}
+
 // Subscription represents a user's subscription to track a LoL player
 type Subscription struct {
 	ID               int64
@@ -157,12 +462,35 @@ type Eval struct {
 
 // Translation represents a cached translation of a username
 type Translation struct {
-	ID          int64
-	Username    string
-	Translation string
-	Provider    string
-	Model       string
-	CreatedAt   time.Time
+	ID              int64
+	Username        string
+	Translation     string
+	Provider        string
+	Model           string
+	CreatedAt       time.Time
+	PromptVersion   string
+	SeenCount       int64
+	ExamplesVersion string
+	TargetLanguage  string
+	// UpdatedAt is when the translation was last written; CreatedAt, which
+	// retention goes by, is when it was first.
+	UpdatedAt time.Time
+}
+
+// ServerConfig holds a Discord server's settings. Scripts is a
+// comma-separated list of transliteration script names whose names get
+// translated; empty means all of them.
+type ServerConfig struct {
+	ServerID       string
+	TargetLanguage string
+	Scripts        string
+	UpdatedAt      time.Time
+}
+
+type UpsertServerConfigParams struct {
+	ServerID       string
+	TargetLanguage string
+	Scripts        string
 }
 
 // Feedback represents user feedback on a translation
@@ -230,10 +558,28 @@ type GetEvalByGameAndSubscriptionParams struct {
 }
 
 type CreateTranslationParams struct {
-	Username    string
-	Translation string
-	Provider    string
-	Model       string
+	Username        string
+	Translation     string
+	Provider        string
+	Model           string
+	PromptVersion   string
+	ExamplesVersion string
+	TargetLanguage  string
+}
+
+type GetTranslationParams struct {
+	Username       string
+	TargetLanguage string
+}
+
+type GetTranslationsParams struct {
+	Usernames      []string
+	TargetLanguage string
+}
+
+type IncrementTranslationSeenCountParams struct {
+	Usernames      []string
+	TargetLanguage string
 }
 
 type CreateTranslationToEvalParams struct {
@@ -287,6 +633,17 @@ type CacheGameStatusParams struct {
 	Participants []byte
 }
 
+// CreateLLMUsageParams records one LLM call's tokens against a Discord server.
+// ServerID is empty for calls not made on a server's behalf.
+type CreateLLMUsageParams struct {
+	ServerID     string
+	Provider     string
+	Model        string
+	InputTokens  int64
+	OutputTokens int64
+	CostMicroUSD int64
+}
+
 // Repository defines the interface for database operations
 type Repository interface {
 	// Subscriptions
@@ -309,10 +666,12 @@ type Repository interface {
 
 	// Translations
 	CreateTranslation(ctx context.Context, arg CreateTranslationParams) (Translation, error)
-	GetTranslation(ctx context.Context, username string) (Translation, error)
-	GetTranslations(ctx context.Context, usernames []string) ([]Translation, error)
+	GetTranslation(ctx context.Context, arg GetTranslationParams) (Translation, error)
+	GetTranslations(ctx context.Context, arg GetTranslationsParams) ([]Translation, error)
 	GetTranslationsForEval(ctx context.Context, evalID int64) ([]Translation, error)
 	CreateTranslationToEval(ctx context.Context, arg CreateTranslationToEvalParams) error
+	IncrementTranslationSeenCount(ctx context.Context, arg IncrementTranslationSeenCountParams) error
+	ListMostSeenTranslations(ctx context.Context, limit int32) ([]Translation, error)
 
 	// Feedback
 	CreateFeedback(ctx context.Context, arg CreateFeedbackParams) (Feedback, error)
@@ -331,19 +690,46 @@ type Repository interface {
 	DeleteExpiredAccountCache(ctx context.Context) error
 	DeleteExpiredGameCache(ctx context.Context) error
 
+	// Server configs
+	GetServerConfig(ctx context.Context, serverID string) (ServerConfig, error)
+	UpsertServerConfig(ctx context.Context, arg UpsertServerConfigParams) (ServerConfig, error)
+
+	// LLM usage
+	CreateLLMUsage(ctx context.Context, arg CreateLLMUsageParams) error
+	SumLLMUsageCostByServerSince(ctx context.Context, serverID string, since time.Time) (int64, error)
+
 	// Players
 	UpsertPlayer(ctx context.Context, arg UpsertPlayerParams) (Player, error)
 	GetPlayer(ctx context.Context, username string) (Player, error)
 	ListAllPlayers(ctx context.Context) ([]Player, error)
 	UpdatePlayerStats(ctx context.Context, arg UpdatePlayerStatsParams) error
+	RecordPlayerRank(ctx context.Context, arg RecordPlayerRankParams) (int64, error)
+	ListPlayerRankHistory(ctx context.Context, arg ListPlayerRankHistoryParams) ([]PlayerRank, error)
+	GetPeakPlayerRank(ctx context.Context, playerUsername string) (PlayerRank, error)
 
 	// Public Translations (companion website)
 	UpsertPublicTranslation(ctx context.Context, arg UpsertPublicTranslationParams) (PublicTranslation, error)
 	GetPublicTranslation(ctx context.Context, id int64) (PublicTranslation, error)
 	GetPublicTranslationByUsername(ctx context.Context, username string) (PublicTranslation, error)
+	GetPublicTranslationByUsernameKey(ctx context.Context, usernameKey string) (PublicTranslation, error)
 	ListPublicTranslationsNew(ctx context.Context, arg ListPublicTranslationsNewParams) ([]PublicTranslation, error)
+	ListPublicTranslationsBest(ctx context.Context, arg ListPublicTranslationsBestParams) ([]ListPublicTranslationsBestRow, error)
+	ListPublicTranslationsControversial(ctx context.Context, arg ListPublicTranslationsControversialParams) ([]ListPublicTranslationsControversialRow, error)
+	ListPublicTranslationsHot(ctx context.Context, arg ListPublicTranslationsHotParams) ([]ListPublicTranslationsHotRow, error)
 	ListPublicTranslationsTop(ctx context.Context, arg ListPublicTranslationsTopParams) ([]PublicTranslation, error)
+	ListTopVotedPublicTranslations(ctx context.Context, arg ListTopVotedPublicTranslationsParams) ([]PublicTranslation, error)
 	CountPublicTranslations(ctx context.Context, arg CountPublicTranslationsParams) (int64, error)
+	ListUnkeyedPublicTranslations(ctx context.Context, arg ListUnkeyedPublicTranslationsParams) ([]PublicTranslation, error)
+	SetPublicTranslationUsernameKey(ctx context.Context, arg SetPublicTranslationUsernameKeyParams) error
+	MergePublicTranslation(ctx context.Context, arg MergePublicTranslationParams) error
+	ListUnromanizedPublicTranslations(ctx context.Context, arg ListUnromanizedPublicTranslationsParams) ([]PublicTranslation, error)
+	SetPublicTranslationRomanized(ctx context.Context, arg SetPublicTranslationRomanizedParams) error
+	BackfillHotScores(ctx context.Context) (int64, error)
+	SearchPublicTranslations(ctx context.Context, arg SearchPublicTranslationsParams) ([]SearchPublicTranslationsRow, error)
+	CountSearchPublicTranslations(ctx context.Context, arg CountSearchPublicTranslationsParams) (int64, error)
+	UpdatePublicTranslation(ctx context.Context, arg UpdatePublicTranslationParams) (int64, error)
+	SetPublicTranslationHidden(ctx context.Context, arg SetPublicTranslationHiddenParams) (int64, error)
+	DeletePublicTranslation(ctx context.Context, id int64) (int64, error)
 	IncrementUpvotes(ctx context.Context, id int64) error
 	DecrementUpvotes(ctx context.Context, id int64) error
 	IncrementDownvotes(ctx context.Context, id int64) error
@@ -354,11 +740,42 @@ type Repository interface {
 	GetVote(ctx context.Context, arg GetVoteParams) (Vote, error)
 	DeleteVote(ctx context.Context, arg DeleteVoteParams) (int64, error)
 	CountVotesByIP(ctx context.Context, ipHash string) (int64, error)
+	IsVoteBanned(ctx context.Context, arg IsVoteBannedParams) (bool, error)
+
+	// Translation Alternatives
+	CreateTranslationAlternative(ctx context.Context, arg CreateTranslationAlternativeParams) (TranslationAlternative, error)
+	GetTranslationAlternative(ctx context.Context, id int64) (TranslationAlternative, error)
+	ListTranslationAlternatives(ctx context.Context, translationID int64) ([]TranslationAlternative, error)
+	AdjustTranslationAlternativeVotes(ctx context.Context, arg AdjustTranslationAlternativeVotesParams) (TranslationAlternative, error)
+	UpsertAlternativeVote(ctx context.Context, arg UpsertAlternativeVoteParams) (AlternativeVote, error)
+	GetAlternativeVote(ctx context.Context, arg GetAlternativeVoteParams) (AlternativeVote, error)
+	DeleteAlternativeVote(ctx context.Context, arg DeleteAlternativeVoteParams) (int64, error)
+	UpdateAlternativeLeads(ctx context.Context, margin int32) (int64, error)
+	ListPromotableAlternatives(ctx context.Context, leadingSince time.Time) ([]TranslationAlternative, error)
+	PromoteTranslationAlternative(ctx context.Context, id int64) (int64, error)
+	DeleteTranslationVotes(ctx context.Context, translationID int64) error
+	CopyAlternativeVotes(ctx context.Context, alternativeID int64) error
+	ListTranslationRevisions(ctx context.Context, translationID int64) ([]TranslationRevision, error)
 
 	// Public Feedback
 	CreatePublicFeedback(ctx context.Context, arg CreatePublicFeedbackParams) (PublicFeedback, error)
 	ListPublicFeedback(ctx context.Context, arg ListPublicFeedbackParams) ([]ListPublicFeedbackRow, error)
-	CountPublicFeedback(ctx context.Context) (int64, error)
+	CountPublicFeedback(ctx context.Context, resolved bool) (int64, error)
+	ResolvePublicFeedback(ctx context.Context, id int64) (int64, error)
+
+	// Stream Events
+	CreateStreamEvent(ctx context.Context, arg CreateStreamEventParams) (StreamEvent, error)
+	NotifyStreamEvent(ctx context.Context, payload string) error
+	ListStreamEventsAfter(ctx context.Context, arg ListStreamEventsAfterParams) ([]StreamEvent, error)
+	DeleteOldStreamEvents(ctx context.Context, before time.Time) (int64, error)
+
+	// Moderation
+	CreateVoteBan(ctx context.Context, arg CreateVoteBanParams) (VoteBan, error)
+	ListVoteBans(ctx context.Context) ([]VoteBan, error)
+	DeleteVoteBan(ctx context.Context, id int64) (int64, error)
+	CreateAdminAuditLog(ctx context.Context, arg CreateAdminAuditLogParams) error
+	ListAdminAuditLog(ctx context.Context, arg ListAdminAuditLogParams) ([]AdminAuditLog, error)
+	CountAdminAuditLog(ctx context.Context) (int64, error)
 
 	// Transaction support
 	WithTx(ctx context.Context, fn func(repo Repository) error) error
@@ -8,6 +8,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AdminAuditLog struct {
+	ID        int64              `json:"id"`
+	Actor     string             `json:"actor"`
+	Action    string             `json:"action"`
+	TargetID  pgtype.Int8        `json:"target_id"`
+	Details   []byte             `json:"details"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type AlternativeVote struct {
+	ID            int64              `json:"id"`
+	AlternativeID int64              `json:"alternative_id"`
+	IpHash        string             `json:"ip_hash"`
+	VisitorID     string             `json:"visitor_id"`
+	Vote          int16              `json:"vote"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
 type Eval struct {
 	ID               int64              `json:"id"`
 	SubscriptionID   int64              `json:"subscription_id"`
@@ -24,6 +42,17 @@ type Feedback struct {
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 }
 
+type LlmUsage struct {
+	ID           int64              `json:"id"`
+	ServerID     string             `json:"server_id"`
+	Provider     string             `json:"provider"`
+	Model        string             `json:"model"`
+	InputTokens  int64              `json:"input_tokens"`
+	OutputTokens int64              `json:"output_tokens"`
+	CostMicrousd int64              `json:"cost_microusd"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
 type Player struct {
 	Username     string             `json:"username"`
 	Region       string             `json:"region"`
@@ -34,12 +63,22 @@ type Player struct {
 	LastUpdated  pgtype.Timestamptz `json:"last_updated"`
 }
 
+type PlayerRankHistory struct {
+	ID             int64              `json:"id"`
+	PlayerUsername string             `json:"player_username"`
+	Tier           string             `json:"tier"`
+	Division       string             `json:"division"`
+	LeaguePoints   int32              `json:"league_points"`
+	RecordedAt     pgtype.Timestamptz `json:"recorded_at"`
+}
+
 type PublicFeedback struct {
 	ID            int64              `json:"id"`
 	TranslationID int64              `json:"translation_id"`
 	IpHash        string             `json:"ip_hash"`
 	FeedbackText  string             `json:"feedback_text"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ResolvedAt    pgtype.Timestamptz `json:"resolved_at"`
 }
 
 type PublicTranslation struct {
@@ -54,6 +93,12 @@ type PublicTranslation struct {
 	Upvotes        int32              `json:"upvotes"`
 	Downvotes      int32              `json:"downvotes"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UsernameKey    pgtype.Text        `json:"username_key"`
+	Hidden         bool               `json:"hidden"`
+	Romanized      string             `json:"romanized"`
+	HotScore       float64            `json:"hot_score"`
+	BestScore      float64            `json:"best_score"`
+	Controversy    float64            `json:"controversy"`
 }
 
 type RiotAccountCache struct {
@@ -77,6 +122,20 @@ type RiotGameCache struct {
 	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
 }
 
+type ServerConfig struct {
+	ServerID       string             `json:"server_id"`
+	TargetLanguage string             `json:"target_language"`
+	Scripts        string             `json:"scripts"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type StreamEvent struct {
+	ID        int64              `json:"id"`
+	Type      string             `json:"type"`
+	Payload   []byte             `json:"payload"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type Subscription struct {
 	ID               int64              `json:"id"`
 	DiscordChannelID string             `json:"discord_channel_id"`
@@ -88,12 +147,40 @@ type Subscription struct {
 }
 
 type Translation struct {
-	ID          int64              `json:"id"`
-	Username    string             `json:"username"`
-	Translation string             `json:"translation"`
-	Provider    string             `json:"provider"`
-	Model       string             `json:"model"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ID              int64              `json:"id"`
+	Username        string             `json:"username"`
+	Translation     string             `json:"translation"`
+	Provider        string             `json:"provider"`
+	Model           string             `json:"model"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	PromptVersion   string             `json:"prompt_version"`
+	SeenCount       int64              `json:"seen_count"`
+	ExamplesVersion string             `json:"examples_version"`
+	TargetLanguage  string             `json:"target_language"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TranslationAlternative struct {
+	ID            int64              `json:"id"`
+	TranslationID int64              `json:"translation_id"`
+	Translation   string             `json:"translation"`
+	Explanation   pgtype.Text        `json:"explanation"`
+	IpHash        string             `json:"ip_hash"`
+	VisitorID     string             `json:"visitor_id"`
+	Upvotes       int32              `json:"upvotes"`
+	Downvotes     int32              `json:"downvotes"`
+	LeadingSince  pgtype.Timestamptz `json:"leading_since"`
+	PromotedAt    pgtype.Timestamptz `json:"promoted_at"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type TranslationRevision struct {
+	ID            int64              `json:"id"`
+	TranslationID int64              `json:"translation_id"`
+	Translation   string             `json:"translation"`
+	Explanation   pgtype.Text        `json:"explanation"`
+	AlternativeID pgtype.Int8        `json:"alternative_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
 
 type TranslationToEval struct {
@@ -109,3 +196,11 @@ type Vote struct {
 	Vote          int16              `json:"vote"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
 }
+
+type VoteBan struct {
+	ID        int64              `json:"id"`
+	IpKey     pgtype.Text        `json:"ip_key"`
+	VisitorID pgtype.Text        `json:"visitor_id"`
+	Reason    string             `json:"reason"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
@@ -11,6 +11,54 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const adjustTranslationAlternativeVotes = `-- name: AdjustTranslationAlternativeVotes :one
+UPDATE translation_alternatives
+SET upvotes = upvotes + $2::int,
+    downvotes = downvotes + $3::int
+WHERE id = $1
+RETURNING id, translation_id, translation, explanation, ip_hash, visitor_id, upvotes, downvotes, leading_since, promoted_at, created_at
+`
+
+type AdjustTranslationAlternativeVotesParams struct {
+	ID      int64 `json:"id"`
+	Column2 int32 `json:"column_2"`
+	Column3 int32 `json:"column_3"`
+}
+
+func (q *Queries) AdjustTranslationAlternativeVotes(ctx context.Context, arg AdjustTranslationAlternativeVotesParams) (TranslationAlternative, error) {
+	row := q.db.QueryRow(ctx, adjustTranslationAlternativeVotes, arg.ID, arg.Column2, arg.Column3)
+	var i TranslationAlternative
+	err := row.Scan(
+		&i.ID,
+		&i.TranslationID,
+		&i.Translation,
+		&i.Explanation,
+		&i.IpHash,
+		&i.VisitorID,
+		&i.Upvotes,
+		&i.Downvotes,
+		&i.LeadingSince,
+		&i.PromotedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const backfillHotScores = `-- name: BackfillHotScores :execrows
+UPDATE public_translations
+SET hot_score = LOG(GREATEST(ABS(upvotes - downvotes), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE hot_score = 0
+`
+
+// Backfill of hot_score for rows written before it existed
+func (q *Queries) BackfillHotScores(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, backfillHotScores)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const cacheAccount = `-- name: CacheAccount :exec
 INSERT INTO riot_account_cache (game_name, tag_line, region, puuid, expires_at)
 VALUES ($1, $2, $3, $4, NOW() + interval '24 hours')
@@ -61,12 +109,37 @@ func (q *Queries) CacheGameStatus(ctx context.Context, arg CacheGameStatusParams
 	return err
 }
 
+const copyAlternativeVotes = `-- name: CopyAlternativeVotes :exec
+INSERT INTO votes (translation_id, ip_hash, visitor_id, vote)
+SELECT ta.translation_id, av.ip_hash, av.visitor_id, av.vote
+FROM alternative_votes av
+JOIN translation_alternatives ta ON ta.id = av.alternative_id
+WHERE av.alternative_id = $1
+`
+
+// Carries an alternative's votes over to its translation once promoted.
+func (q *Queries) CopyAlternativeVotes(ctx context.Context, alternativeID int64) error {
+	_, err := q.db.Exec(ctx, copyAlternativeVotes, alternativeID)
+	return err
+}
+
+const countAdminAuditLog = `-- name: CountAdminAuditLog :one
+SELECT COUNT(*) FROM admin_audit_log
+`
+
+func (q *Queries) CountAdminAuditLog(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countAdminAuditLog)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countPublicFeedback = `-- name: CountPublicFeedback :one
-SELECT COUNT(*) FROM public_feedback
+SELECT COUNT(*) FROM public_feedback WHERE (resolved_at IS NOT NULL) = $1::boolean
 `
 
-func (q *Queries) CountPublicFeedback(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countPublicFeedback)
+func (q *Queries) CountPublicFeedback(ctx context.Context, dollar_1 bool) (int64, error) {
+	row := q.db.QueryRow(ctx, countPublicFeedback, dollar_1)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -76,17 +149,56 @@ const countPublicTranslations = `-- name: CountPublicTranslations :one
 SELECT COUNT(*)
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
-WHERE ($1::text = '' OR p.region = $1)
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
   AND ($2::text = '' OR pt.language = $2)
+  AND pt.created_at > $3
 `
 
 type CountPublicTranslationsParams struct {
+	Column1   string             `json:"column_1"`
+	Column2   string             `json:"column_2"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CountPublicTranslations(ctx context.Context, arg CountPublicTranslationsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPublicTranslations, arg.Column1, arg.Column2, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPublicTranslations = `-- name: CountSearchPublicTranslations :one
+SELECT COUNT(*)
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($5::text = '' OR p.region = $5)
+  AND ($6::text = '' OR pt.language = $6)
+  AND (pt.username ILIKE $3::text
+       OR pt.username % $1::text
+       OR ($2::text <> '' AND (pt.romanized LIKE $4::text OR pt.romanized % $2::text))
+       OR to_tsvector('english', pt.translation || ' ' || COALESCE(pt.explanation, '')) @@ plainto_tsquery('english', $1::text))
+`
+
+type CountSearchPublicTranslationsParams struct {
 	Column1 string `json:"column_1"`
 	Column2 string `json:"column_2"`
+	Column3 string `json:"column_3"`
+	Column4 string `json:"column_4"`
+	Column5 string `json:"column_5"`
+	Column6 string `json:"column_6"`
 }
 
-func (q *Queries) CountPublicTranslations(ctx context.Context, arg CountPublicTranslationsParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countPublicTranslations, arg.Column1, arg.Column2)
+func (q *Queries) CountSearchPublicTranslations(ctx context.Context, arg CountSearchPublicTranslationsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchPublicTranslations,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -106,9 +218,15 @@ func (q *Queries) CountSubscriptionsByServer(ctx context.Context, serverID strin
 }
 
 const countVotesByIP = `-- name: CountVotesByIP :one
-SELECT COUNT(*) FROM votes WHERE ip_hash = $1
+SELECT (SELECT COUNT(*) FROM votes WHERE votes.ip_hash = $1)
+     + (SELECT COUNT(*) FROM alternative_votes av
+        JOIN translation_alternatives ta ON ta.id = av.alternative_id
+        WHERE av.ip_hash = $1 AND ta.promoted_at IS NULL) AS count
 `
 
+// Votes on translations and on their alternatives share one per-IP limit.
+// A promoted alternative's votes were copied into votes, so they're only
+// counted there.
 func (q *Queries) CountVotesByIP(ctx context.Context, ipHash string) (int64, error) {
 	row := q.db.QueryRow(ctx, countVotesByIP, ipHash)
 	var count int64
@@ -116,6 +234,28 @@ func (q *Queries) CountVotesByIP(ctx context.Context, ipHash string) (int64, err
 	return count, err
 }
 
+const createAdminAuditLog = `-- name: CreateAdminAuditLog :exec
+INSERT INTO admin_audit_log (actor, action, target_id, details)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateAdminAuditLogParams struct {
+	Actor    string      `json:"actor"`
+	Action   string      `json:"action"`
+	TargetID pgtype.Int8 `json:"target_id"`
+	Details  []byte      `json:"details"`
+}
+
+func (q *Queries) CreateAdminAuditLog(ctx context.Context, arg CreateAdminAuditLogParams) error {
+	_, err := q.db.Exec(ctx, createAdminAuditLog,
+		arg.Actor,
+		arg.Action,
+		arg.TargetID,
+		arg.Details,
+	)
+	return err
+}
+
 const createEval = `-- name: CreateEval :one
 INSERT INTO evals (subscription_id, eval_status, discord_message_id, game_id)
 VALUES ($1, $2, $3, $4)
@@ -171,10 +311,36 @@ func (q *Queries) CreateFeedback(ctx context.Context, arg CreateFeedbackParams)
 	return i, err
 }
 
+const createLLMUsage = `-- name: CreateLLMUsage :exec
+INSERT INTO llm_usage (server_id, provider, model, input_tokens, output_tokens, cost_microusd)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateLLMUsageParams struct {
+	ServerID     string `json:"server_id"`
+	Provider     string `json:"provider"`
+	Model        string `json:"model"`
+	InputTokens  int64  `json:"input_tokens"`
+	OutputTokens int64  `json:"output_tokens"`
+	CostMicrousd int64  `json:"cost_microusd"`
+}
+
+func (q *Queries) CreateLLMUsage(ctx context.Context, arg CreateLLMUsageParams) error {
+	_, err := q.db.Exec(ctx, createLLMUsage,
+		arg.ServerID,
+		arg.Provider,
+		arg.Model,
+		arg.InputTokens,
+		arg.OutputTokens,
+		arg.CostMicrousd,
+	)
+	return err
+}
+
 const createPublicFeedback = `-- name: CreatePublicFeedback :one
 INSERT INTO public_feedback (translation_id, ip_hash, feedback_text)
 VALUES ($1, $2, $3)
-RETURNING id, translation_id, ip_hash, feedback_text, created_at
+RETURNING id, translation_id, ip_hash, feedback_text, created_at, resolved_at
 `
 
 type CreatePublicFeedbackParams struct {
@@ -192,6 +358,30 @@ func (q *Queries) CreatePublicFeedback(ctx context.Context, arg CreatePublicFeed
 		&i.IpHash,
 		&i.FeedbackText,
 		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const createStreamEvent = `-- name: CreateStreamEvent :one
+INSERT INTO stream_events (type, payload)
+VALUES ($1, $2)
+RETURNING id, type, payload, created_at
+`
+
+type CreateStreamEventParams struct {
+	Type    string `json:"type"`
+	Payload []byte `json:"payload"`
+}
+
+func (q *Queries) CreateStreamEvent(ctx context.Context, arg CreateStreamEventParams) (StreamEvent, error) {
+	row := q.db.QueryRow(ctx, createStreamEvent, arg.Type, arg.Payload)
+	var i StreamEvent
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Payload,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -231,17 +421,20 @@ func (q *Queries) CreateSubscription(ctx context.Context, arg CreateSubscription
 }
 
 const createTranslation = `-- name: CreateTranslation :one
-INSERT INTO translations (username, translation, provider, model)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (username) DO UPDATE SET translation = $2, provider = $3, model = $4
-RETURNING id, username, translation, provider, model, created_at
+INSERT INTO translations (username, translation, provider, model, prompt_version, examples_version, target_language)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (username, target_language) DO UPDATE SET translation = $2, provider = $3, model = $4, prompt_version = $5, examples_version = $6, updated_at = NOW()
+RETURNING id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, target_language, updated_at
 `
 
 type CreateTranslationParams struct {
-	Username    string `json:"username"`
-	Translation string `json:"translation"`
-	Provider    string `json:"provider"`
-	Model       string `json:"model"`
+	Username        string `json:"username"`
+	Translation     string `json:"translation"`
+	Provider        string `json:"provider"`
+	Model           string `json:"model"`
+	PromptVersion   string `json:"prompt_version"`
+	ExamplesVersion string `json:"examples_version"`
+	TargetLanguage  string `json:"target_language"`
 }
 
 func (q *Queries) CreateTranslation(ctx context.Context, arg CreateTranslationParams) (Translation, error) {
@@ -250,6 +443,9 @@ func (q *Queries) CreateTranslation(ctx context.Context, arg CreateTranslationPa
 		arg.Translation,
 		arg.Provider,
 		arg.Model,
+		arg.PromptVersion,
+		arg.ExamplesVersion,
+		arg.TargetLanguage,
 	)
 	var i Translation
 	err := row.Scan(
@@ -259,6 +455,51 @@ func (q *Queries) CreateTranslation(ctx context.Context, arg CreateTranslationPa
 		&i.Provider,
 		&i.Model,
 		&i.CreatedAt,
+		&i.PromptVersion,
+		&i.SeenCount,
+		&i.ExamplesVersion,
+		&i.TargetLanguage,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createTranslationAlternative = `-- name: CreateTranslationAlternative :one
+INSERT INTO translation_alternatives (translation_id, translation, explanation, ip_hash, visitor_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (translation_id, visitor_id) DO NOTHING
+RETURNING id, translation_id, translation, explanation, ip_hash, visitor_id, upvotes, downvotes, leading_since, promoted_at, created_at
+`
+
+type CreateTranslationAlternativeParams struct {
+	TranslationID int64       `json:"translation_id"`
+	Translation   string      `json:"translation"`
+	Explanation   pgtype.Text `json:"explanation"`
+	IpHash        string      `json:"ip_hash"`
+	VisitorID     string      `json:"visitor_id"`
+}
+
+func (q *Queries) CreateTranslationAlternative(ctx context.Context, arg CreateTranslationAlternativeParams) (TranslationAlternative, error) {
+	row := q.db.QueryRow(ctx, createTranslationAlternative,
+		arg.TranslationID,
+		arg.Translation,
+		arg.Explanation,
+		arg.IpHash,
+		arg.VisitorID,
+	)
+	var i TranslationAlternative
+	err := row.Scan(
+		&i.ID,
+		&i.TranslationID,
+		&i.Translation,
+		&i.Explanation,
+		&i.IpHash,
+		&i.VisitorID,
+		&i.Upvotes,
+		&i.Downvotes,
+		&i.LeadingSince,
+		&i.PromotedAt,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -279,8 +520,36 @@ func (q *Queries) CreateTranslationToEval(ctx context.Context, arg CreateTransla
 	return err
 }
 
+const createVoteBan = `-- name: CreateVoteBan :one
+INSERT INTO vote_bans (ip_key, visitor_id, reason)
+VALUES ($1, $2, $3)
+RETURNING id, ip_key, visitor_id, reason, created_at
+`
+
+type CreateVoteBanParams struct {
+	IpKey     pgtype.Text `json:"ip_key"`
+	VisitorID pgtype.Text `json:"visitor_id"`
+	Reason    string      `json:"reason"`
+}
+
+func (q *Queries) CreateVoteBan(ctx context.Context, arg CreateVoteBanParams) (VoteBan, error) {
+	row := q.db.QueryRow(ctx, createVoteBan, arg.IpKey, arg.VisitorID, arg.Reason)
+	var i VoteBan
+	err := row.Scan(
+		&i.ID,
+		&i.IpKey,
+		&i.VisitorID,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const decrementDownvotes = `-- name: DecrementDownvotes :exec
-UPDATE public_translations SET downvotes = downvotes - 1 WHERE id = $1
+UPDATE public_translations
+SET downvotes = downvotes - 1,
+    hot_score = LOG(GREATEST(ABS(upvotes - (downvotes - 1)), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1
 `
 
 func (q *Queries) DecrementDownvotes(ctx context.Context, id int64) error {
@@ -289,7 +558,10 @@ func (q *Queries) DecrementDownvotes(ctx context.Context, id int64) error {
 }
 
 const decrementUpvotes = `-- name: DecrementUpvotes :exec
-UPDATE public_translations SET upvotes = upvotes - 1 WHERE id = $1
+UPDATE public_translations
+SET upvotes = upvotes - 1,
+    hot_score = LOG(GREATEST(ABS(upvotes - 1 - downvotes), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1
 `
 
 func (q *Queries) DecrementUpvotes(ctx context.Context, id int64) error {
@@ -297,6 +569,23 @@ func (q *Queries) DecrementUpvotes(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteAlternativeVote = `-- name: DeleteAlternativeVote :execrows
+DELETE FROM alternative_votes WHERE alternative_id = $1 AND visitor_id = $2
+`
+
+type DeleteAlternativeVoteParams struct {
+	AlternativeID int64  `json:"alternative_id"`
+	VisitorID     string `json:"visitor_id"`
+}
+
+func (q *Queries) DeleteAlternativeVote(ctx context.Context, arg DeleteAlternativeVoteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAlternativeVote, arg.AlternativeID, arg.VisitorID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteEvals = `-- name: DeleteEvals :execrows
 DELETE FROM evals
 WHERE evaluated_at < $1
@@ -341,6 +630,18 @@ func (q *Queries) DeleteOldFeedback(ctx context.Context, createdAt pgtype.Timest
 	return result.RowsAffected(), nil
 }
 
+const deleteOldStreamEvents = `-- name: DeleteOldStreamEvents :execrows
+DELETE FROM stream_events WHERE created_at < $1
+`
+
+func (q *Queries) DeleteOldStreamEvents(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOldStreamEvents, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteOldTranslations = `-- name: DeleteOldTranslations :execrows
 DELETE FROM translations WHERE created_at < $1
 `
@@ -353,6 +654,18 @@ func (q *Queries) DeleteOldTranslations(ctx context.Context, createdAt pgtype.Ti
 	return result.RowsAffected(), nil
 }
 
+const deletePublicTranslation = `-- name: DeletePublicTranslation :execrows
+DELETE FROM public_translations WHERE id = $1
+`
+
+func (q *Queries) DeletePublicTranslation(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePublicTranslation, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteSubscription = `-- name: DeleteSubscription :execrows
 DELETE FROM subscriptions
 WHERE discord_channel_id = $1 AND lol_username = $2 AND region = $3
@@ -398,6 +711,15 @@ func (q *Queries) DeleteSubscriptionsByServer(ctx context.Context, serverID stri
 	return result.RowsAffected(), nil
 }
 
+const deleteTranslationVotes = `-- name: DeleteTranslationVotes :exec
+DELETE FROM votes WHERE translation_id = $1
+`
+
+func (q *Queries) DeleteTranslationVotes(ctx context.Context, translationID int64) error {
+	_, err := q.db.Exec(ctx, deleteTranslationVotes, translationID)
+	return err
+}
+
 const deleteVote = `-- name: DeleteVote :execrows
 DELETE FROM votes WHERE translation_id = $1 AND visitor_id = $2
 `
@@ -415,6 +737,18 @@ func (q *Queries) DeleteVote(ctx context.Context, arg DeleteVoteParams) (int64,
 	return result.RowsAffected(), nil
 }
 
+const deleteVoteBan = `-- name: DeleteVoteBan :execrows
+DELETE FROM vote_bans WHERE id = $1
+`
+
+func (q *Queries) DeleteVoteBan(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteVoteBan, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const findSubscriptionsWithExpiredNewestOnlineEval = `-- name: FindSubscriptionsWithExpiredNewestOnlineEval :many
 SELECT subscription_id, MAX(evaluated_at) as newest_online_eval
 FROM evals
@@ -481,6 +815,29 @@ func (q *Queries) GetAllSubscriptions(ctx context.Context) ([]Subscription, erro
 	return items, nil
 }
 
+const getAlternativeVote = `-- name: GetAlternativeVote :one
+SELECT id, alternative_id, ip_hash, visitor_id, vote, created_at FROM alternative_votes WHERE alternative_id = $1 AND visitor_id = $2
+`
+
+type GetAlternativeVoteParams struct {
+	AlternativeID int64  `json:"alternative_id"`
+	VisitorID     string `json:"visitor_id"`
+}
+
+func (q *Queries) GetAlternativeVote(ctx context.Context, arg GetAlternativeVoteParams) (AlternativeVote, error) {
+	row := q.db.QueryRow(ctx, getAlternativeVote, arg.AlternativeID, arg.VisitorID)
+	var i AlternativeVote
+	err := row.Scan(
+		&i.ID,
+		&i.AlternativeID,
+		&i.IpHash,
+		&i.VisitorID,
+		&i.Vote,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getCachedAccount = `-- name: GetCachedAccount :one
 SELECT game_name, tag_line, region, puuid
 FROM riot_account_cache
@@ -592,6 +949,33 @@ func (q *Queries) GetLatestEvalForSubscription(ctx context.Context, subscription
 	return i, err
 }
 
+const getPeakPlayerRank = `-- name: GetPeakPlayerRank :one
+SELECT id, player_username, tier, division, league_points, recorded_at FROM player_rank_history
+WHERE player_username = $1
+ORDER BY CASE tier
+             WHEN 'CHALLENGER' THEN 9 WHEN 'GRANDMASTER' THEN 8 WHEN 'MASTER' THEN 7
+             WHEN 'DIAMOND' THEN 6 WHEN 'EMERALD' THEN 5 WHEN 'PLATINUM' THEN 4
+             WHEN 'GOLD' THEN 3 WHEN 'SILVER' THEN 2 WHEN 'BRONZE' THEN 1 ELSE 0
+         END DESC,
+         CASE division WHEN 'I' THEN 4 WHEN 'II' THEN 3 WHEN 'III' THEN 2 ELSE 1 END DESC,
+         league_points DESC, recorded_at
+LIMIT 1
+`
+
+func (q *Queries) GetPeakPlayerRank(ctx context.Context, playerUsername string) (PlayerRankHistory, error) {
+	row := q.db.QueryRow(ctx, getPeakPlayerRank, playerUsername)
+	var i PlayerRankHistory
+	err := row.Scan(
+		&i.ID,
+		&i.PlayerUsername,
+		&i.Tier,
+		&i.Division,
+		&i.LeaguePoints,
+		&i.RecordedAt,
+	)
+	return i, err
+}
+
 const getPlayer = `-- name: GetPlayer :one
 SELECT username, region, rank, top_champions, puuid, first_seen, last_updated FROM players WHERE username = $1
 `
@@ -614,7 +998,7 @@ func (q *Queries) GetPlayer(ctx context.Context, username string) (Player, error
 const getPublicTranslation = `-- name: GetPublicTranslation :one
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
 WHERE pt.id = $1
@@ -635,6 +1019,7 @@ type GetPublicTranslationRow struct {
 	Downvotes    int32              `json:"downvotes"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
 }
 
 func (q *Queries) GetPublicTranslation(ctx context.Context, id int64) (GetPublicTranslationRow, error) {
@@ -655,6 +1040,7 @@ func (q *Queries) GetPublicTranslation(ctx context.Context, id int64) (GetPublic
 		&i.Downvotes,
 		&i.CreatedAt,
 		&i.FirstSeen,
+		&i.Hidden,
 	)
 	return i, err
 }
@@ -662,7 +1048,7 @@ func (q *Queries) GetPublicTranslation(ctx context.Context, id int64) (GetPublic
 const getPublicTranslationByUsername = `-- name: GetPublicTranslationByUsername :one
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
 WHERE pt.username = $1
@@ -683,6 +1069,7 @@ type GetPublicTranslationByUsernameRow struct {
 	Downvotes    int32              `json:"downvotes"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
 }
 
 func (q *Queries) GetPublicTranslationByUsername(ctx context.Context, username string) (GetPublicTranslationByUsernameRow, error) {
@@ -703,6 +1090,74 @@ func (q *Queries) GetPublicTranslationByUsername(ctx context.Context, username s
 		&i.Downvotes,
 		&i.CreatedAt,
 		&i.FirstSeen,
+		&i.Hidden,
+	)
+	return i, err
+}
+
+const getPublicTranslationByUsernameKey = `-- name: GetPublicTranslationByUsernameKey :one
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE pt.username_key = $1
+`
+
+type GetPublicTranslationByUsernameKeyRow struct {
+	ID           int64              `json:"id"`
+	Username     string             `json:"username"`
+	Translation  string             `json:"translation"`
+	Explanation  pgtype.Text        `json:"explanation"`
+	Language     string             `json:"language"`
+	Region       string             `json:"region"`
+	SourceBotID  pgtype.Text        `json:"source_bot_id"`
+	RiotVerified bool               `json:"riot_verified"`
+	Rank         pgtype.Text        `json:"rank"`
+	TopChampions pgtype.Text        `json:"top_champions"`
+	Upvotes      int32              `json:"upvotes"`
+	Downvotes    int32              `json:"downvotes"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+}
+
+func (q *Queries) GetPublicTranslationByUsernameKey(ctx context.Context, usernameKey pgtype.Text) (GetPublicTranslationByUsernameKeyRow, error) {
+	row := q.db.QueryRow(ctx, getPublicTranslationByUsernameKey, usernameKey)
+	var i GetPublicTranslationByUsernameKeyRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Translation,
+		&i.Explanation,
+		&i.Language,
+		&i.Region,
+		&i.SourceBotID,
+		&i.RiotVerified,
+		&i.Rank,
+		&i.TopChampions,
+		&i.Upvotes,
+		&i.Downvotes,
+		&i.CreatedAt,
+		&i.FirstSeen,
+		&i.Hidden,
+	)
+	return i, err
+}
+
+const getServerConfig = `-- name: GetServerConfig :one
+SELECT server_id, target_language, scripts, updated_at FROM server_configs
+WHERE server_id = $1
+`
+
+func (q *Queries) GetServerConfig(ctx context.Context, serverID string) (ServerConfig, error) {
+	row := q.db.QueryRow(ctx, getServerConfig, serverID)
+	var i ServerConfig
+	err := row.Scan(
+		&i.ServerID,
+		&i.TargetLanguage,
+		&i.Scripts,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -762,12 +1217,17 @@ func (q *Queries) GetSubscriptionsByChannel(ctx context.Context, discordChannelI
 }
 
 const getTranslation = `-- name: GetTranslation :one
-SELECT id, username, translation, provider, model, created_at FROM translations
-WHERE username = $1
+SELECT id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, target_language, updated_at FROM translations
+WHERE username = $1 AND target_language = $2
 `
 
-func (q *Queries) GetTranslation(ctx context.Context, username string) (Translation, error) {
-	row := q.db.QueryRow(ctx, getTranslation, username)
+type GetTranslationParams struct {
+	Username       string `json:"username"`
+	TargetLanguage string `json:"target_language"`
+}
+
+func (q *Queries) GetTranslation(ctx context.Context, arg GetTranslationParams) (Translation, error) {
+	row := q.db.QueryRow(ctx, getTranslation, arg.Username, arg.TargetLanguage)
 	var i Translation
 	err := row.Scan(
 		&i.ID,
@@ -776,20 +1236,53 @@ func (q *Queries) GetTranslation(ctx context.Context, username string) (Translat
 		&i.Provider,
 		&i.Model,
 		&i.CreatedAt,
+		&i.PromptVersion,
+		&i.SeenCount,
+		&i.ExamplesVersion,
+		&i.TargetLanguage,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getTranslations = `-- name: GetTranslations :many
-SELECT id, username, translation, provider, model, created_at FROM translations
-WHERE username = ANY($1::text[])
+const getTranslationAlternative = `-- name: GetTranslationAlternative :one
+SELECT id, translation_id, translation, explanation, ip_hash, visitor_id, upvotes, downvotes, leading_since, promoted_at, created_at FROM translation_alternatives WHERE id = $1
 `
 
-func (q *Queries) GetTranslations(ctx context.Context, dollar_1 []string) ([]Translation, error) {
-	rows, err := q.db.Query(ctx, getTranslations, dollar_1)
-	if err != nil {
-		return nil, err
-	}
+func (q *Queries) GetTranslationAlternative(ctx context.Context, id int64) (TranslationAlternative, error) {
+	row := q.db.QueryRow(ctx, getTranslationAlternative, id)
+	var i TranslationAlternative
+	err := row.Scan(
+		&i.ID,
+		&i.TranslationID,
+		&i.Translation,
+		&i.Explanation,
+		&i.IpHash,
+		&i.VisitorID,
+		&i.Upvotes,
+		&i.Downvotes,
+		&i.LeadingSince,
+		&i.PromotedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTranslations = `-- name: GetTranslations :many
+SELECT id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, target_language, updated_at FROM translations
+WHERE target_language = $1 AND username = ANY($2::text[])
+`
+
+type GetTranslationsParams struct {
+	TargetLanguage string   `json:"target_language"`
+	Column2        []string `json:"column_2"`
+}
+
+func (q *Queries) GetTranslations(ctx context.Context, arg GetTranslationsParams) ([]Translation, error) {
+	rows, err := q.db.Query(ctx, getTranslations, arg.TargetLanguage, arg.Column2)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 	items := []Translation{}
 	for rows.Next() {
@@ -801,6 +1294,11 @@ func (q *Queries) GetTranslations(ctx context.Context, dollar_1 []string) ([]Tra
 			&i.Provider,
 			&i.Model,
 			&i.CreatedAt,
+			&i.PromptVersion,
+			&i.SeenCount,
+			&i.ExamplesVersion,
+			&i.TargetLanguage,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -813,7 +1311,7 @@ func (q *Queries) GetTranslations(ctx context.Context, dollar_1 []string) ([]Tra
 }
 
 const getTranslationsForEval = `-- name: GetTranslationsForEval :many
-SELECT t.id, t.username, t.translation, t.provider, t.model, t.created_at
+SELECT t.id, t.username, t.translation, t.provider, t.model, t.created_at, t.prompt_version, t.seen_count, t.examples_version, t.target_language, t.updated_at
 FROM translations t
 JOIN translation_to_evals tte ON t.id = tte.translation_id
 WHERE tte.eval_id = $1
@@ -835,6 +1333,11 @@ func (q *Queries) GetTranslationsForEval(ctx context.Context, evalID int64) ([]T
 			&i.Provider,
 			&i.Model,
 			&i.CreatedAt,
+			&i.PromptVersion,
+			&i.SeenCount,
+			&i.ExamplesVersion,
+			&i.TargetLanguage,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -870,7 +1373,10 @@ func (q *Queries) GetVote(ctx context.Context, arg GetVoteParams) (Vote, error)
 }
 
 const incrementDownvotes = `-- name: IncrementDownvotes :exec
-UPDATE public_translations SET downvotes = downvotes + 1 WHERE id = $1
+UPDATE public_translations
+SET downvotes = downvotes + 1,
+    hot_score = LOG(GREATEST(ABS(upvotes - (downvotes + 1)), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1
 `
 
 func (q *Queries) IncrementDownvotes(ctx context.Context, id int64) error {
@@ -878,8 +1384,26 @@ func (q *Queries) IncrementDownvotes(ctx context.Context, id int64) error {
 	return err
 }
 
+const incrementTranslationSeenCount = `-- name: IncrementTranslationSeenCount :exec
+UPDATE translations SET seen_count = seen_count + 1
+WHERE target_language = $1 AND username = ANY($2::text[])
+`
+
+type IncrementTranslationSeenCountParams struct {
+	TargetLanguage string   `json:"target_language"`
+	Column2        []string `json:"column_2"`
+}
+
+func (q *Queries) IncrementTranslationSeenCount(ctx context.Context, arg IncrementTranslationSeenCountParams) error {
+	_, err := q.db.Exec(ctx, incrementTranslationSeenCount, arg.TargetLanguage, arg.Column2)
+	return err
+}
+
 const incrementUpvotes = `-- name: IncrementUpvotes :exec
-UPDATE public_translations SET upvotes = upvotes + 1 WHERE id = $1
+UPDATE public_translations
+SET upvotes = upvotes + 1,
+    hot_score = LOG(GREATEST(ABS(upvotes + 1 - downvotes), 1)::float8) + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $1
 `
 
 func (q *Queries) IncrementUpvotes(ctx context.Context, id int64) error {
@@ -887,6 +1411,60 @@ func (q *Queries) IncrementUpvotes(ctx context.Context, id int64) error {
 	return err
 }
 
+const isVoteBanned = `-- name: IsVoteBanned :one
+SELECT EXISTS (SELECT 1 FROM vote_bans WHERE ip_key = $1 OR visitor_id = $2)
+`
+
+type IsVoteBannedParams struct {
+	IpKey     pgtype.Text `json:"ip_key"`
+	VisitorID pgtype.Text `json:"visitor_id"`
+}
+
+func (q *Queries) IsVoteBanned(ctx context.Context, arg IsVoteBannedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isVoteBanned, arg.IpKey, arg.VisitorID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listAdminAuditLog = `-- name: ListAdminAuditLog :many
+SELECT id, actor, action, target_id, details, created_at FROM admin_audit_log
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListAdminAuditLogParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListAdminAuditLog(ctx context.Context, arg ListAdminAuditLogParams) ([]AdminAuditLog, error) {
+	rows, err := q.db.Query(ctx, listAdminAuditLog, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AdminAuditLog{}
+	for rows.Next() {
+		var i AdminAuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.Action,
+			&i.TargetID,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAllPlayers = `-- name: ListAllPlayers :many
 SELECT username, region, rank, top_champions, puuid, first_seen, last_updated FROM players ORDER BY username
 `
@@ -919,17 +1497,136 @@ func (q *Queries) ListAllPlayers(ctx context.Context) ([]Player, error) {
 	return items, nil
 }
 
+const listMostSeenTranslations = `-- name: ListMostSeenTranslations :many
+SELECT id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, target_language, updated_at FROM translations
+ORDER BY seen_count DESC, created_at DESC
+LIMIT $1
+`
+
+func (q *Queries) ListMostSeenTranslations(ctx context.Context, limit int32) ([]Translation, error) {
+	rows, err := q.db.Query(ctx, listMostSeenTranslations, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Translation{}
+	for rows.Next() {
+		var i Translation
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Translation,
+			&i.Provider,
+			&i.Model,
+			&i.CreatedAt,
+			&i.PromptVersion,
+			&i.SeenCount,
+			&i.ExamplesVersion,
+			&i.TargetLanguage,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlayerRankHistory = `-- name: ListPlayerRankHistory :many
+SELECT id, player_username, tier, division, league_points, recorded_at FROM player_rank_history
+WHERE player_username = $1
+ORDER BY recorded_at DESC, id DESC
+LIMIT $2
+`
+
+type ListPlayerRankHistoryParams struct {
+	PlayerUsername string `json:"player_username"`
+	Limit          int32  `json:"limit"`
+}
+
+func (q *Queries) ListPlayerRankHistory(ctx context.Context, arg ListPlayerRankHistoryParams) ([]PlayerRankHistory, error) {
+	rows, err := q.db.Query(ctx, listPlayerRankHistory, arg.PlayerUsername, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PlayerRankHistory{}
+	for rows.Next() {
+		var i PlayerRankHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlayerUsername,
+			&i.Tier,
+			&i.Division,
+			&i.LeaguePoints,
+			&i.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPromotableAlternatives = `-- name: ListPromotableAlternatives :many
+SELECT DISTINCT ON (translation_id) id, translation_id, translation, explanation, ip_hash, visitor_id, upvotes, downvotes, leading_since, promoted_at, created_at FROM translation_alternatives
+WHERE promoted_at IS NULL AND leading_since <= $1
+ORDER BY translation_id, (upvotes - downvotes) DESC, leading_since, id
+`
+
+// The alternatives that have led their translation since $1, the best-voted
+// one per translation.
+func (q *Queries) ListPromotableAlternatives(ctx context.Context, leadingSince pgtype.Timestamptz) ([]TranslationAlternative, error) {
+	rows, err := q.db.Query(ctx, listPromotableAlternatives, leadingSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TranslationAlternative{}
+	for rows.Next() {
+		var i TranslationAlternative
+		if err := rows.Scan(
+			&i.ID,
+			&i.TranslationID,
+			&i.Translation,
+			&i.Explanation,
+			&i.IpHash,
+			&i.VisitorID,
+			&i.Upvotes,
+			&i.Downvotes,
+			&i.LeadingSince,
+			&i.PromotedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPublicFeedback = `-- name: ListPublicFeedback :many
-SELECT pf.id, pf.translation_id, pf.ip_hash, pf.feedback_text, pf.created_at, pt.username, pt.translation
+SELECT pf.id, pf.translation_id, pf.ip_hash, pf.feedback_text, pf.created_at, pf.resolved_at, pt.username, pt.translation
 FROM public_feedback pf
 JOIN public_translations pt ON pt.id = pf.translation_id
+WHERE (pf.resolved_at IS NOT NULL) = $3::boolean
 ORDER BY pf.created_at DESC
 LIMIT $1 OFFSET $2
 `
 
 type ListPublicFeedbackParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Limit   int32 `json:"limit"`
+	Offset  int32 `json:"offset"`
+	Column3 bool  `json:"column_3"`
 }
 
 type ListPublicFeedbackRow struct {
@@ -938,12 +1635,13 @@ type ListPublicFeedbackRow struct {
 	IpHash        string             `json:"ip_hash"`
 	FeedbackText  string             `json:"feedback_text"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ResolvedAt    pgtype.Timestamptz `json:"resolved_at"`
 	Username      string             `json:"username"`
 	Translation   string             `json:"translation"`
 }
 
 func (q *Queries) ListPublicFeedback(ctx context.Context, arg ListPublicFeedbackParams) ([]ListPublicFeedbackRow, error) {
-	rows, err := q.db.Query(ctx, listPublicFeedback, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listPublicFeedback, arg.Limit, arg.Offset, arg.Column3)
 	if err != nil {
 		return nil, err
 	}
@@ -957,6 +1655,7 @@ func (q *Queries) ListPublicFeedback(ctx context.Context, arg ListPublicFeedback
 			&i.IpHash,
 			&i.FeedbackText,
 			&i.CreatedAt,
+			&i.ResolvedAt,
 			&i.Username,
 			&i.Translation,
 		); err != nil {
@@ -970,26 +1669,32 @@ func (q *Queries) ListPublicFeedback(ctx context.Context, arg ListPublicFeedback
 	return items, nil
 }
 
-const listPublicTranslationsNew = `-- name: ListPublicTranslationsNew :many
+const listPublicTranslationsBest = `-- name: ListPublicTranslationsBest :many
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden, pt.best_score
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
-WHERE ($1::text = '' OR p.region = $1)
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
   AND ($2::text = '' OR pt.language = $2)
-ORDER BY pt.created_at DESC
+  AND pt.created_at > $5
+  AND ($6::bigint = 0 OR (pt.best_score, pt.id) < ($7::float8, $6::bigint))
+ORDER BY pt.best_score DESC, pt.id DESC
 LIMIT $3 OFFSET $4
 `
 
-type ListPublicTranslationsNewParams struct {
-	Column1 string `json:"column_1"`
-	Column2 string `json:"column_2"`
-	Limit   int32  `json:"limit"`
-	Offset  int32  `json:"offset"`
+type ListPublicTranslationsBestParams struct {
+	Column1   string             `json:"column_1"`
+	Column2   string             `json:"column_2"`
+	Limit     int32              `json:"limit"`
+	Offset    int32              `json:"offset"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Column6   int64              `json:"column_6"`
+	Column7   float64            `json:"column_7"`
 }
 
-type ListPublicTranslationsNewRow struct {
+type ListPublicTranslationsBestRow struct {
 	ID           int64              `json:"id"`
 	Username     string             `json:"username"`
 	Translation  string             `json:"translation"`
@@ -1004,22 +1709,27 @@ type ListPublicTranslationsNewRow struct {
 	Downvotes    int32              `json:"downvotes"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+	BestScore    float64            `json:"best_score"`
 }
 
-func (q *Queries) ListPublicTranslationsNew(ctx context.Context, arg ListPublicTranslationsNewParams) ([]ListPublicTranslationsNewRow, error) {
-	rows, err := q.db.Query(ctx, listPublicTranslationsNew,
+func (q *Queries) ListPublicTranslationsBest(ctx context.Context, arg ListPublicTranslationsBestParams) ([]ListPublicTranslationsBestRow, error) {
+	rows, err := q.db.Query(ctx, listPublicTranslationsBest,
 		arg.Column1,
 		arg.Column2,
 		arg.Limit,
 		arg.Offset,
+		arg.CreatedAt,
+		arg.Column6,
+		arg.Column7,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListPublicTranslationsNewRow{}
+	items := []ListPublicTranslationsBestRow{}
 	for rows.Next() {
-		var i ListPublicTranslationsNewRow
+		var i ListPublicTranslationsBestRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Username,
@@ -1035,6 +1745,8 @@ func (q *Queries) ListPublicTranslationsNew(ctx context.Context, arg ListPublicT
 			&i.Downvotes,
 			&i.CreatedAt,
 			&i.FirstSeen,
+			&i.Hidden,
+			&i.BestScore,
 		); err != nil {
 			return nil, err
 		}
@@ -1046,28 +1758,32 @@ func (q *Queries) ListPublicTranslationsNew(ctx context.Context, arg ListPublicT
 	return items, nil
 }
 
-const listPublicTranslationsTop = `-- name: ListPublicTranslationsTop :many
+const listPublicTranslationsControversial = `-- name: ListPublicTranslationsControversial :many
 SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
        p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
-       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden, pt.controversy
 FROM public_translations pt
 JOIN players p ON pt.player_username = p.username
-WHERE ($1::text = '' OR p.region = $1)
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
   AND ($2::text = '' OR pt.language = $2)
   AND pt.created_at > $5
-ORDER BY (pt.upvotes - pt.downvotes) DESC, pt.created_at DESC
+  AND ($6::bigint = 0 OR (pt.controversy, pt.id) < ($7::float8, $6::bigint))
+ORDER BY pt.controversy DESC, pt.id DESC
 LIMIT $3 OFFSET $4
 `
 
-type ListPublicTranslationsTopParams struct {
+type ListPublicTranslationsControversialParams struct {
 	Column1   string             `json:"column_1"`
 	Column2   string             `json:"column_2"`
 	Limit     int32              `json:"limit"`
 	Offset    int32              `json:"offset"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Column6   int64              `json:"column_6"`
+	Column7   float64            `json:"column_7"`
 }
 
-type ListPublicTranslationsTopRow struct {
+type ListPublicTranslationsControversialRow struct {
 	ID           int64              `json:"id"`
 	Username     string             `json:"username"`
 	Translation  string             `json:"translation"`
@@ -1082,23 +1798,27 @@ type ListPublicTranslationsTopRow struct {
 	Downvotes    int32              `json:"downvotes"`
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+	Controversy  float64            `json:"controversy"`
 }
 
-func (q *Queries) ListPublicTranslationsTop(ctx context.Context, arg ListPublicTranslationsTopParams) ([]ListPublicTranslationsTopRow, error) {
-	rows, err := q.db.Query(ctx, listPublicTranslationsTop,
+func (q *Queries) ListPublicTranslationsControversial(ctx context.Context, arg ListPublicTranslationsControversialParams) ([]ListPublicTranslationsControversialRow, error) {
+	rows, err := q.db.Query(ctx, listPublicTranslationsControversial,
 		arg.Column1,
 		arg.Column2,
 		arg.Limit,
 		arg.Offset,
 		arg.CreatedAt,
+		arg.Column6,
+		arg.Column7,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListPublicTranslationsTopRow{}
+	items := []ListPublicTranslationsControversialRow{}
 	for rows.Next() {
-		var i ListPublicTranslationsTopRow
+		var i ListPublicTranslationsControversialRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Username,
@@ -1114,6 +1834,8 @@ func (q *Queries) ListPublicTranslationsTop(ctx context.Context, arg ListPublicT
 			&i.Downvotes,
 			&i.CreatedAt,
 			&i.FirstSeen,
+			&i.Hidden,
+			&i.Controversy,
 		); err != nil {
 			return nil, err
 		}
@@ -1125,42 +1847,964 @@ func (q *Queries) ListPublicTranslationsTop(ctx context.Context, arg ListPublicT
 	return items, nil
 }
 
-const updatePlayerStats = `-- name: UpdatePlayerStats :exec
-UPDATE players SET rank = $2, top_champions = $3, last_updated = NOW()
-WHERE username = $1
+const listPublicTranslationsHot = `-- name: ListPublicTranslationsHot :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden, pt.hot_score
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
+  AND ($2::text = '' OR pt.language = $2)
+  AND ($5::bigint = 0 OR (pt.hot_score, pt.id) < ($6::float8, $5::bigint))
+ORDER BY pt.hot_score DESC, pt.id DESC
+LIMIT $3 OFFSET $4
 `
 
-type UpdatePlayerStatsParams struct {
-	Username     string      `json:"username"`
-	Rank         pgtype.Text `json:"rank"`
-	TopChampions pgtype.Text `json:"top_champions"`
+type ListPublicTranslationsHotParams struct {
+	Column1 string  `json:"column_1"`
+	Column2 string  `json:"column_2"`
+	Limit   int32   `json:"limit"`
+	Offset  int32   `json:"offset"`
+	Column5 int64   `json:"column_5"`
+	Column6 float64 `json:"column_6"`
 }
 
-func (q *Queries) UpdatePlayerStats(ctx context.Context, arg UpdatePlayerStatsParams) error {
-	_, err := q.db.Exec(ctx, updatePlayerStats, arg.Username, arg.Rank, arg.TopChampions)
-	return err
+type ListPublicTranslationsHotRow struct {
+	ID           int64              `json:"id"`
+	Username     string             `json:"username"`
+	Translation  string             `json:"translation"`
+	Explanation  pgtype.Text        `json:"explanation"`
+	Language     string             `json:"language"`
+	Region       string             `json:"region"`
+	SourceBotID  pgtype.Text        `json:"source_bot_id"`
+	RiotVerified bool               `json:"riot_verified"`
+	Rank         pgtype.Text        `json:"rank"`
+	TopChampions pgtype.Text        `json:"top_champions"`
+	Upvotes      int32              `json:"upvotes"`
+	Downvotes    int32              `json:"downvotes"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+	HotScore     float64            `json:"hot_score"`
 }
 
-const updateSubscriptionLastEvaluatedAt = `-- name: UpdateSubscriptionLastEvaluatedAt :exec
-UPDATE subscriptions
-SET last_evaluated_at = NOW()
-WHERE id = $1
-`
-
-func (q *Queries) UpdateSubscriptionLastEvaluatedAt(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, updateSubscriptionLastEvaluatedAt, id)
-	return err
+func (q *Queries) ListPublicTranslationsHot(ctx context.Context, arg ListPublicTranslationsHotParams) ([]ListPublicTranslationsHotRow, error) {
+	rows, err := q.db.Query(ctx, listPublicTranslationsHot,
+		arg.Column1,
+		arg.Column2,
+		arg.Limit,
+		arg.Offset,
+		arg.Column5,
+		arg.Column6,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPublicTranslationsHotRow{}
+	for rows.Next() {
+		var i ListPublicTranslationsHotRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Translation,
+			&i.Explanation,
+			&i.Language,
+			&i.Region,
+			&i.SourceBotID,
+			&i.RiotVerified,
+			&i.Rank,
+			&i.TopChampions,
+			&i.Upvotes,
+			&i.Downvotes,
+			&i.CreatedAt,
+			&i.FirstSeen,
+			&i.Hidden,
+			&i.HotScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const upsertPlayer = `-- name: UpsertPlayer :one
+const listPublicTranslationsNew = `-- name: ListPublicTranslationsNew :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
+  AND ($2::text = '' OR pt.language = $2)
+  AND ($5::bigint = 0 OR (pt.created_at, pt.id) < ($6::timestamptz, $5::bigint))
+ORDER BY pt.created_at DESC, pt.id DESC
+LIMIT $3 OFFSET $4
+`
 
+type ListPublicTranslationsNewParams struct {
+	Column1 string             `json:"column_1"`
+	Column2 string             `json:"column_2"`
+	Limit   int32              `json:"limit"`
+	Offset  int32              `json:"offset"`
+	Column5 int64              `json:"column_5"`
+	Column6 pgtype.Timestamptz `json:"column_6"`
+}
 
-INSERT INTO players (username, region, rank, top_champions, puuid)
-VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (username) DO UPDATE SET
-    region = EXCLUDED.region,
-    rank = COALESCE(EXCLUDED.rank, players.rank),
-    top_champions = COALESCE(EXCLUDED.top_champions, players.top_champions),
+type ListPublicTranslationsNewRow struct {
+	ID           int64              `json:"id"`
+	Username     string             `json:"username"`
+	Translation  string             `json:"translation"`
+	Explanation  pgtype.Text        `json:"explanation"`
+	Language     string             `json:"language"`
+	Region       string             `json:"region"`
+	SourceBotID  pgtype.Text        `json:"source_bot_id"`
+	RiotVerified bool               `json:"riot_verified"`
+	Rank         pgtype.Text        `json:"rank"`
+	TopChampions pgtype.Text        `json:"top_champions"`
+	Upvotes      int32              `json:"upvotes"`
+	Downvotes    int32              `json:"downvotes"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+}
+
+func (q *Queries) ListPublicTranslationsNew(ctx context.Context, arg ListPublicTranslationsNewParams) ([]ListPublicTranslationsNewRow, error) {
+	rows, err := q.db.Query(ctx, listPublicTranslationsNew,
+		arg.Column1,
+		arg.Column2,
+		arg.Limit,
+		arg.Offset,
+		arg.Column5,
+		arg.Column6,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPublicTranslationsNewRow{}
+	for rows.Next() {
+		var i ListPublicTranslationsNewRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Translation,
+			&i.Explanation,
+			&i.Language,
+			&i.Region,
+			&i.SourceBotID,
+			&i.RiotVerified,
+			&i.Rank,
+			&i.TopChampions,
+			&i.Upvotes,
+			&i.Downvotes,
+			&i.CreatedAt,
+			&i.FirstSeen,
+			&i.Hidden,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicTranslationsTop = `-- name: ListPublicTranslationsTop :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($1::text = '' OR p.region = $1)
+  AND ($2::text = '' OR pt.language = $2)
+  AND pt.created_at > $5
+  AND ($6::bigint = 0 OR (pt.upvotes - pt.downvotes, pt.id) < ($7::float8, $6::bigint))
+ORDER BY (pt.upvotes - pt.downvotes) DESC, pt.id DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListPublicTranslationsTopParams struct {
+	Column1   string             `json:"column_1"`
+	Column2   string             `json:"column_2"`
+	Limit     int32              `json:"limit"`
+	Offset    int32              `json:"offset"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Column6   int64              `json:"column_6"`
+	Column7   float64            `json:"column_7"`
+}
+
+type ListPublicTranslationsTopRow struct {
+	ID           int64              `json:"id"`
+	Username     string             `json:"username"`
+	Translation  string             `json:"translation"`
+	Explanation  pgtype.Text        `json:"explanation"`
+	Language     string             `json:"language"`
+	Region       string             `json:"region"`
+	SourceBotID  pgtype.Text        `json:"source_bot_id"`
+	RiotVerified bool               `json:"riot_verified"`
+	Rank         pgtype.Text        `json:"rank"`
+	TopChampions pgtype.Text        `json:"top_champions"`
+	Upvotes      int32              `json:"upvotes"`
+	Downvotes    int32              `json:"downvotes"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+}
+
+func (q *Queries) ListPublicTranslationsTop(ctx context.Context, arg ListPublicTranslationsTopParams) ([]ListPublicTranslationsTopRow, error) {
+	rows, err := q.db.Query(ctx, listPublicTranslationsTop,
+		arg.Column1,
+		arg.Column2,
+		arg.Limit,
+		arg.Offset,
+		arg.CreatedAt,
+		arg.Column6,
+		arg.Column7,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPublicTranslationsTopRow{}
+	for rows.Next() {
+		var i ListPublicTranslationsTopRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Translation,
+			&i.Explanation,
+			&i.Language,
+			&i.Region,
+			&i.SourceBotID,
+			&i.RiotVerified,
+			&i.Rank,
+			&i.TopChampions,
+			&i.Upvotes,
+			&i.Downvotes,
+			&i.CreatedAt,
+			&i.FirstSeen,
+			&i.Hidden,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStreamEventsAfter = `-- name: ListStreamEventsAfter :many
+SELECT id, type, payload, created_at FROM stream_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListStreamEventsAfterParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+func (q *Queries) ListStreamEventsAfter(ctx context.Context, arg ListStreamEventsAfterParams) ([]StreamEvent, error) {
+	rows, err := q.db.Query(ctx, listStreamEventsAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []StreamEvent{}
+	for rows.Next() {
+		var i StreamEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Payload,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopVotedPublicTranslations = `-- name: ListTopVotedPublicTranslations :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND pt.language = $1
+  AND pt.upvotes >= $2
+  AND pt.downvotes * 5 <= pt.upvotes
+ORDER BY (pt.upvotes - pt.downvotes) DESC, pt.id
+LIMIT $3
+`
+
+type ListTopVotedPublicTranslationsParams struct {
+	Language string `json:"language"`
+	Upvotes  int32  `json:"upvotes"`
+	Limit    int32  `json:"limit"`
+}
+
+type ListTopVotedPublicTranslationsRow struct {
+	ID           int64              `json:"id"`
+	Username     string             `json:"username"`
+	Translation  string             `json:"translation"`
+	Explanation  pgtype.Text        `json:"explanation"`
+	Language     string             `json:"language"`
+	Region       string             `json:"region"`
+	SourceBotID  pgtype.Text        `json:"source_bot_id"`
+	RiotVerified bool               `json:"riot_verified"`
+	Rank         pgtype.Text        `json:"rank"`
+	TopChampions pgtype.Text        `json:"top_champions"`
+	Upvotes      int32              `json:"upvotes"`
+	Downvotes    int32              `json:"downvotes"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+}
+
+func (q *Queries) ListTopVotedPublicTranslations(ctx context.Context, arg ListTopVotedPublicTranslationsParams) ([]ListTopVotedPublicTranslationsRow, error) {
+	rows, err := q.db.Query(ctx, listTopVotedPublicTranslations, arg.Language, arg.Upvotes, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTopVotedPublicTranslationsRow{}
+	for rows.Next() {
+		var i ListTopVotedPublicTranslationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Translation,
+			&i.Explanation,
+			&i.Language,
+			&i.Region,
+			&i.SourceBotID,
+			&i.RiotVerified,
+			&i.Rank,
+			&i.TopChampions,
+			&i.Upvotes,
+			&i.Downvotes,
+			&i.CreatedAt,
+			&i.FirstSeen,
+			&i.Hidden,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTranslationAlternatives = `-- name: ListTranslationAlternatives :many
+SELECT id, translation_id, translation, explanation, ip_hash, visitor_id, upvotes, downvotes, leading_since, promoted_at, created_at FROM translation_alternatives
+WHERE translation_id = $1 AND promoted_at IS NULL
+ORDER BY (upvotes - downvotes) DESC, id
+`
+
+func (q *Queries) ListTranslationAlternatives(ctx context.Context, translationID int64) ([]TranslationAlternative, error) {
+	rows, err := q.db.Query(ctx, listTranslationAlternatives, translationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TranslationAlternative{}
+	for rows.Next() {
+		var i TranslationAlternative
+		if err := rows.Scan(
+			&i.ID,
+			&i.TranslationID,
+			&i.Translation,
+			&i.Explanation,
+			&i.IpHash,
+			&i.VisitorID,
+			&i.Upvotes,
+			&i.Downvotes,
+			&i.LeadingSince,
+			&i.PromotedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTranslationRevisions = `-- name: ListTranslationRevisions :many
+SELECT id, translation_id, translation, explanation, alternative_id, created_at FROM translation_revisions
+WHERE translation_id = $1
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListTranslationRevisions(ctx context.Context, translationID int64) ([]TranslationRevision, error) {
+	rows, err := q.db.Query(ctx, listTranslationRevisions, translationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TranslationRevision{}
+	for rows.Next() {
+		var i TranslationRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.TranslationID,
+			&i.Translation,
+			&i.Explanation,
+			&i.AlternativeID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnkeyedPublicTranslations = `-- name: ListUnkeyedPublicTranslations :many
+SELECT id, username FROM public_translations
+WHERE username_key IS NULL AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListUnkeyedPublicTranslationsParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+type ListUnkeyedPublicTranslationsRow struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Backfill of username_key for rows written before it existed
+func (q *Queries) ListUnkeyedPublicTranslations(ctx context.Context, arg ListUnkeyedPublicTranslationsParams) ([]ListUnkeyedPublicTranslationsRow, error) {
+	rows, err := q.db.Query(ctx, listUnkeyedPublicTranslations, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnkeyedPublicTranslationsRow{}
+	for rows.Next() {
+		var i ListUnkeyedPublicTranslationsRow
+		if err := rows.Scan(&i.ID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnromanizedPublicTranslations = `-- name: ListUnromanizedPublicTranslations :many
+SELECT id, username FROM public_translations
+WHERE romanized = '' AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListUnromanizedPublicTranslationsParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+type ListUnromanizedPublicTranslationsRow struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Backfill of romanized for rows written before it existed
+func (q *Queries) ListUnromanizedPublicTranslations(ctx context.Context, arg ListUnromanizedPublicTranslationsParams) ([]ListUnromanizedPublicTranslationsRow, error) {
+	rows, err := q.db.Query(ctx, listUnromanizedPublicTranslations, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnromanizedPublicTranslationsRow{}
+	for rows.Next() {
+		var i ListUnromanizedPublicTranslationsRow
+		if err := rows.Scan(&i.ID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVoteBans = `-- name: ListVoteBans :many
+SELECT id, ip_key, visitor_id, reason, created_at FROM vote_bans ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListVoteBans(ctx context.Context) ([]VoteBan, error) {
+	rows, err := q.db.Query(ctx, listVoteBans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []VoteBan{}
+	for rows.Next() {
+		var i VoteBan
+		if err := rows.Scan(
+			&i.ID,
+			&i.IpKey,
+			&i.VisitorID,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const mergePublicTranslation = `-- name: MergePublicTranslation :exec
+WITH moved_votes AS (
+    UPDATE votes SET translation_id = $2
+    WHERE translation_id = $1
+      AND visitor_id NOT IN (SELECT visitor_id FROM votes WHERE translation_id = $2)
+    RETURNING vote
+), moved_alternatives AS (
+    UPDATE translation_alternatives SET translation_id = $2
+    WHERE translation_id = $1
+      AND visitor_id NOT IN (SELECT visitor_id FROM translation_alternatives WHERE translation_id = $2)
+), moved_revisions AS (
+    UPDATE translation_revisions SET translation_id = $2 WHERE translation_id = $1
+), moved_feedback AS (
+    UPDATE public_feedback SET translation_id = $2 WHERE translation_id = $1
+)
+UPDATE public_translations SET
+    upvotes = upvotes + (SELECT COUNT(*) FROM moved_votes WHERE vote = 1),
+    downvotes = downvotes + (SELECT COUNT(*) FROM moved_votes WHERE vote = -1),
+    hot_score = LOG(GREATEST(ABS(upvotes + (SELECT COUNT(*) FROM moved_votes WHERE vote = 1)
+                                 - downvotes - (SELECT COUNT(*) FROM moved_votes WHERE vote = -1)), 1)::float8)
+                + EXTRACT(EPOCH FROM created_at)::float8 / 45000
+WHERE id = $2
+`
+
+type MergePublicTranslationParams struct {
+	FromID int64 `json:"from_id"`
+	IntoID int64 `json:"into_id"`
+}
+
+// Moves the votes, alternatives, revisions and feedback of a duplicate of a
+// translation onto it, adding the moved votes to its counts. A visitor who
+// voted on (or suggested an alternative for) both keeps their vote on the
+// survivor. The duplicate is deleted afterwards, taking what wasn't moved.
+func (q *Queries) MergePublicTranslation(ctx context.Context, arg MergePublicTranslationParams) error {
+	_, err := q.db.Exec(ctx, mergePublicTranslation, arg.FromID, arg.IntoID)
+	return err
+}
+
+const notifyStreamEvent = `-- name: NotifyStreamEvent :exec
+SELECT pg_notify('stream_events', $1::text)
+`
+
+// Sends $1 to the web instances listening on the stream_events channel, when
+// the surrounding transaction commits.
+func (q *Queries) NotifyStreamEvent(ctx context.Context, dollar_1 string) error {
+	_, err := q.db.Exec(ctx, notifyStreamEvent, dollar_1)
+	return err
+}
+
+const promoteTranslationAlternative = `-- name: PromoteTranslationAlternative :execrows
+WITH alternative AS (
+    SELECT id, translation_id, translation, explanation, upvotes, downvotes
+    FROM translation_alternatives
+    WHERE translation_alternatives.id = $1 AND promoted_at IS NULL
+), revision AS (
+    INSERT INTO translation_revisions (translation_id, translation, explanation, alternative_id)
+    SELECT pt.id, pt.translation, pt.explanation, alternative.id
+    FROM public_translations pt
+    JOIN alternative ON alternative.translation_id = pt.id
+), promoted AS (
+    UPDATE translation_alternatives ta
+    SET leading_since = NULL,
+        promoted_at = CASE WHEN ta.id = alternative.id THEN NOW() ELSE ta.promoted_at END
+    FROM alternative
+    WHERE ta.translation_id = alternative.translation_id
+)
+UPDATE public_translations pt
+SET translation = alternative.translation,
+    explanation = alternative.explanation,
+    upvotes = alternative.upvotes,
+    downvotes = alternative.downvotes,
+    hot_score = LOG(GREATEST(ABS(alternative.upvotes - alternative.downvotes), 1)::float8) + EXTRACT(EPOCH FROM pt.created_at)::float8 / 45000
+FROM alternative
+WHERE pt.id = alternative.translation_id
+`
+
+// Saves the translation's current version as a revision and replaces it with
+// the alternative, votes counts included. The translation's other alternatives
+// lose their leads, since they were measured against the old version.
+func (q *Queries) PromoteTranslationAlternative(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, promoteTranslationAlternative, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordPlayerRank = `-- name: RecordPlayerRank :execrows
+INSERT INTO player_rank_history (player_username, tier, division, league_points)
+SELECT $1, $2, $3, $4
+WHERE NOT EXISTS (
+    SELECT 1 FROM (
+        SELECT tier, division, league_points FROM player_rank_history
+        WHERE player_username = $1
+        ORDER BY recorded_at DESC, id DESC
+        LIMIT 1
+    ) latest
+    WHERE latest.tier = $2 AND latest.division = $3 AND latest.league_points = $4
+)
+`
+
+type RecordPlayerRankParams struct {
+	PlayerUsername string `json:"player_username"`
+	Tier           string `json:"tier"`
+	Division       string `json:"division"`
+	LeaguePoints   int32  `json:"league_points"`
+}
+
+// Appends a rank unless it is the player's latest already
+func (q *Queries) RecordPlayerRank(ctx context.Context, arg RecordPlayerRankParams) (int64, error) {
+	result, err := q.db.Exec(ctx, recordPlayerRank,
+		arg.PlayerUsername,
+		arg.Tier,
+		arg.Division,
+		arg.LeaguePoints,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const resolvePublicFeedback = `-- name: ResolvePublicFeedback :execrows
+UPDATE public_feedback SET resolved_at = COALESCE(resolved_at, NOW()) WHERE id = $1
+`
+
+func (q *Queries) ResolvePublicFeedback(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, resolvePublicFeedback, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const searchPublicTranslations = `-- name: SearchPublicTranslations :many
+SELECT pt.id, pt.username, pt.translation, pt.explanation, pt.language,
+       p.region, pt.source_bot_id, pt.riot_verified, p.rank, p.top_champions,
+       pt.upvotes, pt.downvotes, pt.created_at, p.first_seen, pt.hidden,
+       (GREATEST(
+            similarity(pt.username, $1::text),
+            CASE WHEN $2::text = '' THEN 0 ELSE similarity(pt.romanized, $2::text) END,
+            ts_rank(to_tsvector('english', pt.translation || ' ' || COALESCE(pt.explanation, '')), plainto_tsquery('english', $1::text))
+        ) + CASE WHEN pt.username ILIKE $3::text OR ($2::text <> '' AND pt.romanized LIKE $4::text) THEN 1 ELSE 0 END)::float8 AS search_rank
+FROM public_translations pt
+JOIN players p ON pt.player_username = p.username
+WHERE NOT pt.hidden
+  AND ($5::text = '' OR p.region = $5)
+  AND ($6::text = '' OR pt.language = $6)
+  AND (pt.username ILIKE $3::text
+       OR pt.username % $1::text
+       OR ($2::text <> '' AND (pt.romanized LIKE $4::text OR pt.romanized % $2::text))
+       OR to_tsvector('english', pt.translation || ' ' || COALESCE(pt.explanation, '')) @@ plainto_tsquery('english', $1::text))
+ORDER BY search_rank DESC, (pt.upvotes - pt.downvotes) DESC, pt.id DESC
+LIMIT $7 OFFSET $8
+`
+
+type SearchPublicTranslationsParams struct {
+	Column1 string `json:"column_1"`
+	Column2 string `json:"column_2"`
+	Column3 string `json:"column_3"`
+	Column4 string `json:"column_4"`
+	Column5 string `json:"column_5"`
+	Column6 string `json:"column_6"`
+	Limit   int32  `json:"limit"`
+	Offset  int32  `json:"offset"`
+}
+
+type SearchPublicTranslationsRow struct {
+	ID           int64              `json:"id"`
+	Username     string             `json:"username"`
+	Translation  string             `json:"translation"`
+	Explanation  pgtype.Text        `json:"explanation"`
+	Language     string             `json:"language"`
+	Region       string             `json:"region"`
+	SourceBotID  pgtype.Text        `json:"source_bot_id"`
+	RiotVerified bool               `json:"riot_verified"`
+	Rank         pgtype.Text        `json:"rank"`
+	TopChampions pgtype.Text        `json:"top_champions"`
+	Upvotes      int32              `json:"upvotes"`
+	Downvotes    int32              `json:"downvotes"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	FirstSeen    pgtype.Timestamptz `json:"first_seen"`
+	Hidden       bool               `json:"hidden"`
+	SearchRank   float64            `json:"search_rank"`
+}
+
+// Ranked search over usernames (substring or trigram), their romanizations and
+// the translation text. $1 is the query as typed and $2 its SearchKey; $3 and $4
+// are LIKE patterns for them, with wildcards escaped.
+func (q *Queries) SearchPublicTranslations(ctx context.Context, arg SearchPublicTranslationsParams) ([]SearchPublicTranslationsRow, error) {
+	rows, err := q.db.Query(ctx, searchPublicTranslations,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchPublicTranslationsRow{}
+	for rows.Next() {
+		var i SearchPublicTranslationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Translation,
+			&i.Explanation,
+			&i.Language,
+			&i.Region,
+			&i.SourceBotID,
+			&i.RiotVerified,
+			&i.Rank,
+			&i.TopChampions,
+			&i.Upvotes,
+			&i.Downvotes,
+			&i.CreatedAt,
+			&i.FirstSeen,
+			&i.Hidden,
+			&i.SearchRank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPublicTranslationHidden = `-- name: SetPublicTranslationHidden :execrows
+UPDATE public_translations SET hidden = $2 WHERE id = $1
+`
+
+type SetPublicTranslationHiddenParams struct {
+	ID     int64 `json:"id"`
+	Hidden bool  `json:"hidden"`
+}
+
+func (q *Queries) SetPublicTranslationHidden(ctx context.Context, arg SetPublicTranslationHiddenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPublicTranslationHidden, arg.ID, arg.Hidden)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setPublicTranslationRomanized = `-- name: SetPublicTranslationRomanized :exec
+UPDATE public_translations SET romanized = $2 WHERE id = $1
+`
+
+type SetPublicTranslationRomanizedParams struct {
+	ID        int64  `json:"id"`
+	Romanized string `json:"romanized"`
+}
+
+func (q *Queries) SetPublicTranslationRomanized(ctx context.Context, arg SetPublicTranslationRomanizedParams) error {
+	_, err := q.db.Exec(ctx, setPublicTranslationRomanized, arg.ID, arg.Romanized)
+	return err
+}
+
+const setPublicTranslationUsernameKey = `-- name: SetPublicTranslationUsernameKey :exec
+UPDATE public_translations SET username_key = $2
+WHERE id = $1
+  AND NOT EXISTS (SELECT 1 FROM public_translations WHERE username_key = $2)
+`
+
+type SetPublicTranslationUsernameKeyParams struct {
+	ID          int64       `json:"id"`
+	UsernameKey pgtype.Text `json:"username_key"`
+}
+
+// Leaves the key unset when another row already has it: that row is the same
+// name, and keeps receiving its submissions.
+func (q *Queries) SetPublicTranslationUsernameKey(ctx context.Context, arg SetPublicTranslationUsernameKeyParams) error {
+	_, err := q.db.Exec(ctx, setPublicTranslationUsernameKey, arg.ID, arg.UsernameKey)
+	return err
+}
+
+const sumLLMUsageCostByServerSince = `-- name: SumLLMUsageCostByServerSince :one
+SELECT COALESCE(SUM(cost_microusd), 0)::bigint
+FROM llm_usage
+WHERE server_id = $1 AND created_at >= $2
+`
+
+type SumLLMUsageCostByServerSinceParams struct {
+	ServerID  string             `json:"server_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) SumLLMUsageCostByServerSince(ctx context.Context, arg SumLLMUsageCostByServerSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, sumLLMUsageCostByServerSince, arg.ServerID, arg.CreatedAt)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const updateAlternativeLeads = `-- name: UpdateAlternativeLeads :execrows
+UPDATE translation_alternatives ta
+SET leading_since = CASE WHEN ta.leading_since IS NULL THEN NOW() END
+FROM public_translations pt
+WHERE pt.id = ta.translation_id
+  AND ta.promoted_at IS NULL
+  AND NOT pt.hidden
+  AND (ta.leading_since IS NULL) = (ta.upvotes - ta.downvotes >= pt.upvotes - pt.downvotes + $1::int)
+`
+
+// Starts the lead of every alternative now at least $1 net votes ahead of its
+// translation, and ends the lead of those that fell back.
+func (q *Queries) UpdateAlternativeLeads(ctx context.Context, dollar_1 int32) (int64, error) {
+	result, err := q.db.Exec(ctx, updateAlternativeLeads, dollar_1)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updatePlayerStats = `-- name: UpdatePlayerStats :exec
+UPDATE players SET rank = $2, top_champions = $3, last_updated = NOW()
+WHERE username = $1
+`
+
+type UpdatePlayerStatsParams struct {
+	Username     string      `json:"username"`
+	Rank         pgtype.Text `json:"rank"`
+	TopChampions pgtype.Text `json:"top_champions"`
+}
+
+func (q *Queries) UpdatePlayerStats(ctx context.Context, arg UpdatePlayerStatsParams) error {
+	_, err := q.db.Exec(ctx, updatePlayerStats, arg.Username, arg.Rank, arg.TopChampions)
+	return err
+}
+
+const updatePublicTranslation = `-- name: UpdatePublicTranslation :execrows
+UPDATE public_translations SET
+    translation = COALESCE($2, translation),
+    explanation = COALESCE($3, explanation)
+WHERE id = $1
+`
+
+type UpdatePublicTranslationParams struct {
+	ID          int64       `json:"id"`
+	Translation pgtype.Text `json:"translation"`
+	Explanation pgtype.Text `json:"explanation"`
+}
+
+// Moderator edit; NULL leaves a column as it is
+func (q *Queries) UpdatePublicTranslation(ctx context.Context, arg UpdatePublicTranslationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePublicTranslation, arg.ID, arg.Translation, arg.Explanation)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateSubscriptionLastEvaluatedAt = `-- name: UpdateSubscriptionLastEvaluatedAt :exec
+UPDATE subscriptions
+SET last_evaluated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) UpdateSubscriptionLastEvaluatedAt(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, updateSubscriptionLastEvaluatedAt, id)
+	return err
+}
+
+const upsertAlternativeVote = `-- name: UpsertAlternativeVote :one
+INSERT INTO alternative_votes (alternative_id, ip_hash, visitor_id, vote)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (alternative_id, visitor_id) DO UPDATE SET vote = $4
+RETURNING id, alternative_id, ip_hash, visitor_id, vote, created_at
+`
+
+type UpsertAlternativeVoteParams struct {
+	AlternativeID int64  `json:"alternative_id"`
+	IpHash        string `json:"ip_hash"`
+	VisitorID     string `json:"visitor_id"`
+	Vote          int16  `json:"vote"`
+}
+
+func (q *Queries) UpsertAlternativeVote(ctx context.Context, arg UpsertAlternativeVoteParams) (AlternativeVote, error) {
+	row := q.db.QueryRow(ctx, upsertAlternativeVote,
+		arg.AlternativeID,
+		arg.IpHash,
+		arg.VisitorID,
+		arg.Vote,
+	)
+	var i AlternativeVote
+	err := row.Scan(
+		&i.ID,
+		&i.AlternativeID,
+		&i.IpHash,
+		&i.VisitorID,
+		&i.Vote,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertPlayer = `-- name: UpsertPlayer :one
+
+
+INSERT INTO players (username, region, rank, top_champions, puuid)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (username) DO UPDATE SET
+    region = EXCLUDED.region,
+    rank = COALESCE(EXCLUDED.rank, players.rank),
+    top_champions = COALESCE(EXCLUDED.top_champions, players.top_champions),
     puuid = COALESCE(EXCLUDED.puuid, players.puuid),
     last_updated = NOW()
 RETURNING username, region, rank, top_champions, puuid, first_seen, last_updated
@@ -1201,16 +2845,15 @@ func (q *Queries) UpsertPlayer(ctx context.Context, arg UpsertPlayerParams) (Pla
 
 const upsertPublicTranslation = `-- name: UpsertPublicTranslation :one
 
-INSERT INTO public_translations (username, translation, explanation, language, player_username, source_bot_id, riot_verified)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-ON CONFLICT (username) DO UPDATE SET
+INSERT INTO public_translations (username, translation, explanation, language, player_username, source_bot_id, riot_verified, username_key, romanized, hot_score)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, EXTRACT(EPOCH FROM NOW())::float8 / 45000)
+ON CONFLICT (username_key) DO UPDATE SET
     translation = EXCLUDED.translation,
     explanation = EXCLUDED.explanation,
     language = EXCLUDED.language,
-    player_username = EXCLUDED.player_username,
     source_bot_id = EXCLUDED.source_bot_id,
     riot_verified = EXCLUDED.riot_verified
-RETURNING id, username, translation, explanation, language, player_username, source_bot_id, riot_verified, upvotes, downvotes, created_at
+RETURNING id, username, translation, explanation, language, player_username, source_bot_id, riot_verified, upvotes, downvotes, created_at, username_key, hidden, romanized, hot_score, best_score, controversy
 `
 
 type UpsertPublicTranslationParams struct {
@@ -1221,9 +2864,13 @@ type UpsertPublicTranslationParams struct {
 	PlayerUsername string      `json:"player_username"`
 	SourceBotID    pgtype.Text `json:"source_bot_id"`
 	RiotVerified   bool        `json:"riot_verified"`
+	UsernameKey    pgtype.Text `json:"username_key"`
+	Romanized      string      `json:"romanized"`
 }
 
 // Public translation queries (JOIN against players for region/rank/top_champions)
+// A name resubmitted under a lookalike spelling keeps the username and player
+// it was first saved under.
 func (q *Queries) UpsertPublicTranslation(ctx context.Context, arg UpsertPublicTranslationParams) (PublicTranslation, error) {
 	row := q.db.QueryRow(ctx, upsertPublicTranslation,
 		arg.Username,
@@ -1233,6 +2880,8 @@ func (q *Queries) UpsertPublicTranslation(ctx context.Context, arg UpsertPublicT
 		arg.PlayerUsername,
 		arg.SourceBotID,
 		arg.RiotVerified,
+		arg.UsernameKey,
+		arg.Romanized,
 	)
 	var i PublicTranslation
 	err := row.Scan(
@@ -1247,6 +2896,37 @@ func (q *Queries) UpsertPublicTranslation(ctx context.Context, arg UpsertPublicT
 		&i.Upvotes,
 		&i.Downvotes,
 		&i.CreatedAt,
+		&i.UsernameKey,
+		&i.Hidden,
+		&i.Romanized,
+		&i.HotScore,
+		&i.BestScore,
+		&i.Controversy,
+	)
+	return i, err
+}
+
+const upsertServerConfig = `-- name: UpsertServerConfig :one
+INSERT INTO server_configs (server_id, target_language, scripts)
+VALUES ($1, $2, $3)
+ON CONFLICT (server_id) DO UPDATE SET target_language = $2, scripts = $3, updated_at = NOW()
+RETURNING server_id, target_language, scripts, updated_at
+`
+
+type UpsertServerConfigParams struct {
+	ServerID       string `json:"server_id"`
+	TargetLanguage string `json:"target_language"`
+	Scripts        string `json:"scripts"`
+}
+
+func (q *Queries) UpsertServerConfig(ctx context.Context, arg UpsertServerConfigParams) (ServerConfig, error) {
+	row := q.db.QueryRow(ctx, upsertServerConfig, arg.ServerID, arg.TargetLanguage, arg.Scripts)
+	var i ServerConfig
+	err := row.Scan(
+		&i.ServerID,
+		&i.TargetLanguage,
+		&i.Scripts,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
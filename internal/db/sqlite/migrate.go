@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// columnMigrations lists columns added to tables after their first release.
+// schema.sql uses CREATE TABLE IF NOT EXISTS, so it never alters a table that
+// already exists in a user's database; these are applied with ALTER TABLE instead.
+// ALTER TABLE only takes constant defaults, so a column whose default is an
+// expression is added with a placeholder and set by fill.
+var columnMigrations = []struct {
+	table  string
+	column string
+	ddl    string
+	fill   string
+}{
+	{"translations", "prompt_version", "TEXT NOT NULL DEFAULT ''", ""},
+	{"translations", "seen_count", "INTEGER NOT NULL DEFAULT 1", ""},
+	{"translations", "examples_version", "TEXT NOT NULL DEFAULT ''", ""},
+	{"translations", "updated_at", "TEXT NOT NULL DEFAULT ''", "UPDATE translations SET updated_at = created_at"},
+	{"server_configs", "scripts", "TEXT NOT NULL DEFAULT ''", ""},
+}
+
+// tableRebuilds lists tables whose constraints changed after their first
+// release. SQLite can't alter a constraint in place, so a table that lacks
+// column is recreated from create (which must name the table <table>_new) and
+// its rows copied across, with column taking its default.
+var tableRebuilds = []struct {
+	table   string
+	column  string
+	create  string
+	columns string
+}{
+	{
+		table:  "translations",
+		column: "target_language",
+		create: `CREATE TABLE translations_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL,
+			translation TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			model TEXT NOT NULL,
+			created_at TEXT NOT NULL DEFAULT (datetime('now')),
+			prompt_version TEXT NOT NULL DEFAULT '',
+			seen_count INTEGER NOT NULL DEFAULT 1,
+			examples_version TEXT NOT NULL DEFAULT '',
+			target_language TEXT NOT NULL DEFAULT 'en',
+			updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+			UNIQUE (username, target_language)
+		)`,
+		columns: "id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, updated_at",
+	},
+}
+
+// migrate brings an existing database up to date with schema.sql. Missing
+// columns are added first, then tables with changed constraints are rebuilt,
+// so that indexes in schema.sql can reference the new columns.
+func migrate(ctx context.Context, sqliteDB *sql.DB) error {
+	for _, m := range columnMigrations {
+		columns, err := tableColumns(ctx, sqliteDB, m.table)
+		if err != nil {
+			return fmt.Errorf("reading columns of %s: %w", m.table, err)
+		}
+		// Table doesn't exist yet; schema.sql will create it with the column.
+		if len(columns) == 0 {
+			continue
+		}
+		if columns[m.column] {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.ddl)
+		if _, err := sqliteDB.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("adding column %s.%s: %w", m.table, m.column, err)
+		}
+		if m.fill != "" {
+			if _, err := sqliteDB.ExecContext(ctx, m.fill); err != nil {
+				return fmt.Errorf("filling column %s.%s: %w", m.table, m.column, err)
+			}
+		}
+	}
+
+	for _, m := range tableRebuilds {
+		columns, err := tableColumns(ctx, sqliteDB, m.table)
+		if err != nil {
+			return fmt.Errorf("reading columns of %s: %w", m.table, err)
+		}
+		if len(columns) == 0 || columns[m.column] {
+			continue
+		}
+		if err := rebuildTable(ctx, sqliteDB, m.table, m.create, m.columns); err != nil {
+			return fmt.Errorf("rebuilding %s: %w", m.table, err)
+		}
+	}
+
+	if _, err := sqliteDB.ExecContext(ctx, schemaSQL); err != nil {
+		return fmt.Errorf("applying schema: %w", err)
+	}
+	return nil
+}
+
+// rebuildTable follows SQLite's recommended procedure for schema changes ALTER
+// TABLE can't make: create the new table, copy the rows, drop the old table and
+// rename the new one into place. Foreign keys are switched off on a dedicated
+// connection for the duration so that tables referencing this one keep their
+// rows; ids are copied unchanged so those references stay valid.
+func rebuildTable(ctx context.Context, sqliteDB *sql.DB, table, create, columns string) error {
+	conn, err := sqliteDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys=OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys=ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmts := []string{
+		create,
+		fmt.Sprintf("INSERT INTO %s_new (%s) SELECT %s FROM %s", table, columns, columns, table),
+		fmt.Sprintf("DROP TABLE %s", table),
+		fmt.Sprintf("ALTER TABLE %s_new RENAME TO %s", table, table),
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func tableColumns(ctx context.Context, sqliteDB *sql.DB, table string) (map[string]bool, error) {
+	rows, err := sqliteDB.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
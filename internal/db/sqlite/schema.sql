@@ -29,13 +29,23 @@ CREATE INDEX IF NOT EXISTS idx_evals_subscription_game ON evals(subscription_id,
 -- Translations table (cached username translations)
 CREATE TABLE IF NOT EXISTS translations (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
-    username TEXT NOT NULL UNIQUE,
+    username TEXT NOT NULL,
     translation TEXT NOT NULL,
     provider TEXT NOT NULL,
     model TEXT NOT NULL,
-    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    prompt_version TEXT NOT NULL DEFAULT '',
+    seen_count INTEGER NOT NULL DEFAULT 1,
+    examples_version TEXT NOT NULL DEFAULT '',
+    target_language TEXT NOT NULL DEFAULT 'en',
+    -- When the translation was last written. created_at stays put so that
+    -- retranslated rows still age out under the retention policy.
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (username, target_language)
 );
 
+CREATE INDEX IF NOT EXISTS idx_translations_seen_count ON translations(seen_count);
+
 -- Translation to evals junction table
 CREATE TABLE IF NOT EXISTS translation_to_evals (
     translation_id INTEGER NOT NULL REFERENCES translations(id) ON DELETE CASCADE,
@@ -81,3 +91,66 @@ CREATE TABLE IF NOT EXISTS riot_game_cache (
 );
 
 CREATE INDEX IF NOT EXISTS idx_riot_game_cache_expires ON riot_game_cache(expires_at);
+
+-- Per-Discord-server settings
+CREATE TABLE IF NOT EXISTS server_configs (
+    server_id TEXT PRIMARY KEY,
+    target_language TEXT NOT NULL DEFAULT 'en',
+    scripts TEXT NOT NULL DEFAULT '',
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+-- LLM token usage, one row per call per Discord server it was made for
+CREATE TABLE IF NOT EXISTS llm_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    server_id TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    input_tokens INTEGER NOT NULL,
+    output_tokens INTEGER NOT NULL,
+    cost_microusd INTEGER NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_usage_server_created ON llm_usage(server_id, created_at);
+
+-- Translations shared with the companion website. SQLite keeps no players, so
+-- they have no region, rank or champions. created_at is RFC 3339 in UTC so
+-- that it compares and sorts as text.
+CREATE TABLE IF NOT EXISTS public_translations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    translation TEXT NOT NULL,
+    explanation TEXT,
+    language TEXT NOT NULL,
+    player_username TEXT NOT NULL,
+    source_bot_id TEXT,
+    riot_verified INTEGER NOT NULL DEFAULT 0,
+    upvotes INTEGER NOT NULL DEFAULT 0,
+    downvotes INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    username_key TEXT UNIQUE,
+    hidden INTEGER NOT NULL DEFAULT 0,
+    romanized TEXT NOT NULL DEFAULT '',
+    -- The listing scores, as Postgres computes them: the hot score from the
+    -- net votes and age, the lower bound of the 95% Wilson score interval
+    -- for the share of upvotes, and the controversy of votes split close to
+    -- evenly.
+    hot_score REAL GENERATED ALWAYS AS (
+        log10(max(abs(upvotes - downvotes), 1)) + strftime('%s', created_at) / 45000.0
+    ) VIRTUAL,
+    best_score REAL GENERATED ALWAYS AS (
+        CASE WHEN upvotes + downvotes = 0 THEN 0
+        ELSE ((upvotes + 1.9208) / (upvotes + downvotes)
+              - 1.96 * sqrt(upvotes * 1.0 * downvotes / (upvotes + downvotes) + 0.9604) / (upvotes + downvotes))
+             / (1 + 3.8416 / (upvotes + downvotes))
+        END
+    ) VIRTUAL,
+    controversy REAL GENERATED ALWAYS AS (
+        CASE WHEN upvotes <= 0 OR downvotes <= 0 THEN 0
+        ELSE pow(upvotes + downvotes, min(upvotes, downvotes) * 1.0 / max(upvotes, downvotes))
+        END
+    ) VIRTUAL
+);
+
+CREATE INDEX IF NOT EXISTS idx_public_translations_created ON public_translations(created_at, id);
@@ -62,11 +62,11 @@ func New(ctx context.Context, dbPath string) (*Repository, error) {
 		executor: sqliteDB,
 	}
 
+	if err := migrate(ctx, sqliteDB); err != nil {
+		sqliteDB.Close()
+		return nil, fmt.Errorf("initializing schema: %w", err)
+	}
 	if isNew {
-		if _, err := sqliteDB.ExecContext(ctx, schemaSQL); err != nil {
-			sqliteDB.Close()
-			return nil, fmt.Errorf("initializing schema: %w", err)
-		}
 		slog.Info("created new SQLite database", "path", dbPath)
 	}
 
@@ -322,41 +322,41 @@ func (r *Repository) FindSubscriptionsWithExpiredNewestOnlineEval(ctx context.Co
 
 func (r *Repository) CreateTranslation(ctx context.Context, arg db.CreateTranslationParams) (db.Translation, error) {
 	_, err := r.executor.ExecContext(ctx, `
-		INSERT INTO translations (username, translation, provider, model)
-		VALUES (?, ?, ?, ?)
-		ON CONFLICT (username) DO UPDATE SET translation = ?, provider = ?, model = ?
-	`, arg.Username, arg.Translation, arg.Provider, arg.Model, arg.Translation, arg.Provider, arg.Model)
+		INSERT INTO translations (username, translation, provider, model, prompt_version, examples_version, target_language)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (username, target_language) DO UPDATE SET translation = ?, provider = ?, model = ?, prompt_version = ?, examples_version = ?, updated_at = datetime('now')
+	`, arg.Username, arg.Translation, arg.Provider, arg.Model, arg.PromptVersion, arg.ExamplesVersion, arg.TargetLanguage, arg.Translation, arg.Provider, arg.Model, arg.PromptVersion, arg.ExamplesVersion)
 	if err != nil {
 		return db.Translation{}, err
 	}
 
-	return r.GetTranslation(ctx, arg.Username)
+	return r.GetTranslation(ctx, db.GetTranslationParams{Username: arg.Username, TargetLanguage: arg.TargetLanguage})
 }
 
-func (r *Repository) GetTranslation(ctx context.Context, username string) (db.Translation, error) {
+func (r *Repository) GetTranslation(ctx context.Context, arg db.GetTranslationParams) (db.Translation, error) {
 	row := r.executor.QueryRowContext(ctx, `
-		SELECT id, username, translation, provider, model, created_at
-		FROM translations WHERE username = ?
-	`, username)
+		SELECT id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, target_language, updated_at
+		FROM translations WHERE username = ? AND target_language = ?
+	`, arg.Username, arg.TargetLanguage)
 
 	return scanTranslation(row)
 }
 
-func (r *Repository) GetTranslations(ctx context.Context, usernames []string) ([]db.Translation, error) {
-	if len(usernames) == 0 {
+func (r *Repository) GetTranslations(ctx context.Context, arg db.GetTranslationsParams) ([]db.Translation, error) {
+	if len(arg.Usernames) == 0 {
 		return []db.Translation{}, nil
 	}
 
-	placeholders := make([]string, len(usernames))
-	args := make([]interface{}, len(usernames))
-	for i, u := range usernames {
+	placeholders := make([]string, len(arg.Usernames))
+	args := []interface{}{arg.TargetLanguage}
+	for i, u := range arg.Usernames {
 		placeholders[i] = "?"
-		args[i] = u
+		args = append(args, u)
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, username, translation, provider, model, created_at
-		FROM translations WHERE username IN (%s)
+		SELECT id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, target_language, updated_at
+		FROM translations WHERE target_language = ? AND username IN (%s)
 	`, strings.Join(placeholders, ","))
 
 	rows, err := r.executor.QueryContext(ctx, query, args...)
@@ -370,7 +370,7 @@ func (r *Repository) GetTranslations(ctx context.Context, usernames []string) ([
 
 func (r *Repository) GetTranslationsForEval(ctx context.Context, evalID int64) ([]db.Translation, error) {
 	rows, err := r.executor.QueryContext(ctx, `
-		SELECT t.id, t.username, t.translation, t.provider, t.model, t.created_at
+		SELECT t.id, t.username, t.translation, t.provider, t.model, t.created_at, t.prompt_version, t.seen_count, t.examples_version, t.target_language, t.updated_at
 		FROM translations t
 		JOIN translation_to_evals tte ON t.id = tte.translation_id
 		WHERE tte.eval_id = ?
@@ -392,6 +392,42 @@ func (r *Repository) CreateTranslationToEval(ctx context.Context, arg db.CreateT
 	return err
 }
 
+func (r *Repository) IncrementTranslationSeenCount(ctx context.Context, arg db.IncrementTranslationSeenCountParams) error {
+	if len(arg.Usernames) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(arg.Usernames))
+	args := []interface{}{arg.TargetLanguage}
+	for i, u := range arg.Usernames {
+		placeholders[i] = "?"
+		args = append(args, u)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE translations SET seen_count = seen_count + 1
+		WHERE target_language = ? AND username IN (%s)
+	`, strings.Join(placeholders, ","))
+
+	_, err := r.executor.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *Repository) ListMostSeenTranslations(ctx context.Context, limit int32) ([]db.Translation, error) {
+	rows, err := r.executor.QueryContext(ctx, `
+		SELECT id, username, translation, provider, model, created_at, prompt_version, seen_count, examples_version, target_language, updated_at
+		FROM translations
+		ORDER BY seen_count DESC, created_at DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTranslations(rows)
+}
+
 // Feedback methods
 
 func (r *Repository) CreateFeedback(ctx context.Context, arg db.CreateFeedbackParams) (db.Feedback, error) {
@@ -490,6 +526,42 @@ func (r *Repository) DeleteOldFeedback(ctx context.Context, before time.Time) (i
 	return result.RowsAffected()
 }
 
+func (r *Repository) GetServerConfig(ctx context.Context, serverID string) (db.ServerConfig, error) {
+	row := r.executor.QueryRowContext(ctx, `
+		SELECT server_id, target_language, scripts, updated_at FROM server_configs WHERE server_id = ?
+	`, serverID)
+	return scanServerConfig(row)
+}
+
+func (r *Repository) UpsertServerConfig(ctx context.Context, arg db.UpsertServerConfigParams) (db.ServerConfig, error) {
+	_, err := r.executor.ExecContext(ctx, `
+		INSERT INTO server_configs (server_id, target_language, scripts)
+		VALUES (?, ?, ?)
+		ON CONFLICT (server_id) DO UPDATE SET target_language = ?, scripts = ?, updated_at = datetime('now')
+	`, arg.ServerID, arg.TargetLanguage, arg.Scripts, arg.TargetLanguage, arg.Scripts)
+	if err != nil {
+		return db.ServerConfig{}, err
+	}
+	return r.GetServerConfig(ctx, arg.ServerID)
+}
+
+func (r *Repository) CreateLLMUsage(ctx context.Context, arg db.CreateLLMUsageParams) error {
+	_, err := r.executor.ExecContext(ctx, `
+		INSERT INTO llm_usage (server_id, provider, model, input_tokens, output_tokens, cost_microusd)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, arg.ServerID, arg.Provider, arg.Model, arg.InputTokens, arg.OutputTokens, arg.CostMicroUSD)
+	return err
+}
+
+func (r *Repository) SumLLMUsageCostByServerSince(ctx context.Context, serverID string, since time.Time) (int64, error) {
+	var total int64
+	// created_at is written by datetime('now'), so compare in the same UTC layout.
+	err := r.executor.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(cost_microusd), 0) FROM llm_usage WHERE server_id = ? AND created_at >= ?
+	`, serverID, since.UTC().Format(time.DateTime)).Scan(&total)
+	return total, err
+}
+
 func (r *Repository) DeleteExpiredAccountCache(ctx context.Context) error {
 	_, err := r.executor.ExecContext(ctx, `DELETE FROM riot_account_cache WHERE expires_at < datetime('now')`)
 	return err
@@ -518,48 +590,300 @@ func (r *Repository) UpdatePlayerStats(_ context.Context, _ db.UpdatePlayerStats
 	return fmt.Errorf("players not supported in SQLite mode")
 }
 
-// Public Translation stubs (companion website is Postgres-only)
+func (r *Repository) RecordPlayerRank(_ context.Context, _ db.RecordPlayerRankParams) (int64, error) {
+	return 0, fmt.Errorf("players not supported in SQLite mode")
+}
 
-func (r *Repository) UpsertPublicTranslation(_ context.Context, _ db.UpsertPublicTranslationParams) (db.PublicTranslation, error) {
-	return db.PublicTranslation{}, fmt.Errorf("public translations not supported in SQLite mode")
+func (r *Repository) ListPlayerRankHistory(_ context.Context, _ db.ListPlayerRankHistoryParams) ([]db.PlayerRank, error) {
+	return nil, fmt.Errorf("players not supported in SQLite mode")
 }
 
-func (r *Repository) GetPublicTranslation(_ context.Context, _ int64) (db.PublicTranslation, error) {
-	return db.PublicTranslation{}, fmt.Errorf("public translations not supported in SQLite mode")
+func (r *Repository) GetPeakPlayerRank(_ context.Context, _ string) (db.PlayerRank, error) {
+	return db.PlayerRank{}, fmt.Errorf("players not supported in SQLite mode")
 }
 
-func (r *Repository) GetPublicTranslationByUsername(_ context.Context, _ string) (db.PublicTranslation, error) {
-	return db.PublicTranslation{}, fmt.Errorf("public translations not supported in SQLite mode")
+// Public Translation methods. SQLite keeps what the bot, the listings and
+// search need; moderation and backfills are Postgres-only.
+
+// publicTranslationColumns are the columns scanPublicTranslation reads.
+const publicTranslationColumns = `id, username, translation, explanation, language, source_bot_id,
+	riot_verified, upvotes, downvotes, created_at, hidden`
+
+func (r *Repository) UpsertPublicTranslation(ctx context.Context, arg db.UpsertPublicTranslationParams) (db.PublicTranslation, error) {
+	var key interface{}
+	if arg.UsernameKey != "" {
+		key = arg.UsernameKey
+	}
+	// A name resubmitted under a lookalike spelling keeps the username and
+	// player it was first saved under.
+	row := r.executor.QueryRowContext(ctx, `
+		INSERT INTO public_translations (username, translation, explanation, language, player_username, source_bot_id, riot_verified, username_key, romanized)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (username_key) DO UPDATE SET
+			translation = excluded.translation,
+			explanation = excluded.explanation,
+			language = excluded.language,
+			source_bot_id = excluded.source_bot_id,
+			riot_verified = excluded.riot_verified
+		RETURNING `+publicTranslationColumns,
+		arg.Username, arg.Translation, nullString(arg.Explanation), arg.Language, arg.PlayerUsername,
+		nullString(arg.SourceBotID), arg.RiotVerified, key, arg.Romanized)
+	return scanPublicTranslation(row.Scan)
 }
 
-func (r *Repository) ListPublicTranslationsNew(_ context.Context, _ db.ListPublicTranslationsNewParams) ([]db.PublicTranslation, error) {
-	return nil, fmt.Errorf("public translations not supported in SQLite mode")
+func (r *Repository) GetPublicTranslation(ctx context.Context, id int64) (db.PublicTranslation, error) {
+	row := r.executor.QueryRowContext(ctx, `
+		SELECT `+publicTranslationColumns+` FROM public_translations WHERE id = ?
+	`, id)
+	return scanPublicTranslation(row.Scan)
 }
 
-func (r *Repository) ListPublicTranslationsTop(_ context.Context, _ db.ListPublicTranslationsTopParams) ([]db.PublicTranslation, error) {
-	return nil, fmt.Errorf("public translations not supported in SQLite mode")
+func (r *Repository) GetPublicTranslationByUsername(ctx context.Context, username string) (db.PublicTranslation, error) {
+	row := r.executor.QueryRowContext(ctx, `
+		SELECT `+publicTranslationColumns+` FROM public_translations WHERE username = ?
+	`, username)
+	return scanPublicTranslation(row.Scan)
 }
 
-func (r *Repository) CountPublicTranslations(_ context.Context, _ db.CountPublicTranslationsParams) (int64, error) {
-	return 0, fmt.Errorf("public translations not supported in SQLite mode")
+func (r *Repository) GetPublicTranslationByUsernameKey(ctx context.Context, usernameKey string) (db.PublicTranslation, error) {
+	row := r.executor.QueryRowContext(ctx, `
+		SELECT `+publicTranslationColumns+` FROM public_translations WHERE username_key = ?
+	`, usernameKey)
+	return scanPublicTranslation(row.Scan)
 }
 
-func (r *Repository) IncrementUpvotes(_ context.Context, _ int64) error {
-	return fmt.Errorf("public translations not supported in SQLite mode")
+// publicTranslationListWhere filters the listings as Postgres does, except
+// that without players there are no regions, so a region filter matches
+// nothing. Its arguments are listArgs.
+const publicTranslationListWhere = `
+	WHERE NOT hidden
+	  AND ? = ''
+	  AND (? = '' OR language = ?)
+	  AND created_at > ?`
+
+func listArgs(region, language string, createdAfter time.Time) []interface{} {
+	return []interface{}{region, language, language, createdAfter.UTC().Format(time.RFC3339)}
+}
+
+func (r *Repository) ListPublicTranslationsNew(ctx context.Context, arg db.ListPublicTranslationsNewParams) ([]db.PublicTranslation, error) {
+	args := append(listArgs(arg.Region, arg.Language, time.Time{}),
+		arg.AfterID, arg.AfterCreatedAt.UTC().Format(time.RFC3339), arg.AfterID, arg.Limit, arg.Offset)
+	translations, _, err := r.queryPublicTranslations(ctx, `
+		SELECT `+publicTranslationColumns+`, 0.0 FROM public_translations`+publicTranslationListWhere+`
+		  AND (? = 0 OR (created_at, id) < (?, ?))
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, args...)
+	return translations, err
+}
+
+// listScoredPublicTranslations lists translations by score, a column or
+// expression, highest first, resuming after afterID and afterScore.
+func (r *Repository) listScoredPublicTranslations(ctx context.Context, score, region, language string, createdAfter time.Time, afterID int64, afterScore float64, limit, offset int32) ([]db.PublicTranslation, []float64, error) {
+	args := append(listArgs(region, language, createdAfter), afterID, afterScore, afterID, limit, offset)
+	return r.queryPublicTranslations(ctx, fmt.Sprintf(`
+		SELECT %[1]s, %[2]s FROM public_translations%[3]s
+		  AND (? = 0 OR (%[2]s, id) < (?, ?))
+		ORDER BY %[2]s DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, publicTranslationColumns, score, publicTranslationListWhere), args...)
+}
+
+// queryPublicTranslations reads rows of publicTranslationColumns followed by
+// a score.
+func (r *Repository) queryPublicTranslations(ctx context.Context, query string, args ...interface{}) ([]db.PublicTranslation, []float64, error) {
+	rows, err := r.executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var translations []db.PublicTranslation
+	var scores []float64
+	for rows.Next() {
+		var score float64
+		t, err := scanPublicTranslation(rows.Scan, &score)
+		if err != nil {
+			return nil, nil, err
+		}
+		translations = append(translations, t)
+		scores = append(scores, score)
+	}
+	return translations, scores, rows.Err()
+}
+
+func (r *Repository) ListPublicTranslationsBest(ctx context.Context, arg db.ListPublicTranslationsBestParams) ([]db.ListPublicTranslationsBestRow, error) {
+	translations, scores, err := r.listScoredPublicTranslations(ctx, "best_score",
+		arg.Region, arg.Language, arg.CreatedAt, arg.AfterID, arg.AfterScore, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.ListPublicTranslationsBestRow, len(translations))
+	for i, t := range translations {
+		out[i] = db.ListPublicTranslationsBestRow{PublicTranslation: t, BestScore: scores[i]}
+	}
+	return out, nil
+}
+
+func (r *Repository) ListPublicTranslationsControversial(ctx context.Context, arg db.ListPublicTranslationsControversialParams) ([]db.ListPublicTranslationsControversialRow, error) {
+	translations, scores, err := r.listScoredPublicTranslations(ctx, "controversy",
+		arg.Region, arg.Language, arg.CreatedAt, arg.AfterID, arg.AfterScore, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.ListPublicTranslationsControversialRow, len(translations))
+	for i, t := range translations {
+		out[i] = db.ListPublicTranslationsControversialRow{PublicTranslation: t, Controversy: scores[i]}
+	}
+	return out, nil
+}
+
+func (r *Repository) ListPublicTranslationsHot(ctx context.Context, arg db.ListPublicTranslationsHotParams) ([]db.ListPublicTranslationsHotRow, error) {
+	translations, scores, err := r.listScoredPublicTranslations(ctx, "hot_score",
+		arg.Region, arg.Language, time.Time{}, arg.AfterID, arg.AfterScore, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]db.ListPublicTranslationsHotRow, len(translations))
+	for i, t := range translations {
+		out[i] = db.ListPublicTranslationsHotRow{PublicTranslation: t, HotScore: scores[i]}
+	}
+	return out, nil
+}
+
+func (r *Repository) ListPublicTranslationsTop(ctx context.Context, arg db.ListPublicTranslationsTopParams) ([]db.PublicTranslation, error) {
+	translations, _, err := r.listScoredPublicTranslations(ctx, "(upvotes - downvotes)",
+		arg.Region, arg.Language, arg.CreatedAt, arg.AfterID, arg.AfterScore, arg.Limit, arg.Offset)
+	return translations, err
 }
 
-func (r *Repository) DecrementUpvotes(_ context.Context, _ int64) error {
+// ListTopVotedPublicTranslations returns no rows in SQLite mode: there are no
+// community votes to draw few-shot examples from, so the translator falls back
+// to its built-in examples.
+func (r *Repository) ListTopVotedPublicTranslations(_ context.Context, _ db.ListTopVotedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	return []db.PublicTranslation{}, nil
+}
+
+func (r *Repository) CountPublicTranslations(ctx context.Context, arg db.CountPublicTranslationsParams) (int64, error) {
+	var count int64
+	err := r.executor.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM public_translations`+publicTranslationListWhere,
+		listArgs(arg.Region, arg.Language, arg.CreatedAt)...,
+	).Scan(&count)
+	return count, err
+}
+
+func (r *Repository) ListUnkeyedPublicTranslations(_ context.Context, _ db.ListUnkeyedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	return nil, fmt.Errorf("public translations not supported in SQLite mode")
+}
+
+func (r *Repository) SetPublicTranslationUsernameKey(_ context.Context, _ db.SetPublicTranslationUsernameKeyParams) error {
 	return fmt.Errorf("public translations not supported in SQLite mode")
 }
 
-func (r *Repository) IncrementDownvotes(_ context.Context, _ int64) error {
+func (r *Repository) MergePublicTranslation(_ context.Context, _ db.MergePublicTranslationParams) error {
 	return fmt.Errorf("public translations not supported in SQLite mode")
 }
 
-func (r *Repository) DecrementDownvotes(_ context.Context, _ int64) error {
+func (r *Repository) ListUnromanizedPublicTranslations(_ context.Context, _ db.ListUnromanizedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	return nil, fmt.Errorf("public translations not supported in SQLite mode")
+}
+
+func (r *Repository) SetPublicTranslationRomanized(_ context.Context, _ db.SetPublicTranslationRomanizedParams) error {
 	return fmt.Errorf("public translations not supported in SQLite mode")
 }
 
+func (r *Repository) BackfillHotScores(_ context.Context) (int64, error) {
+	return 0, fmt.Errorf("public translations not supported in SQLite mode")
+}
+
+// searchPublicTranslationsWhere matches the username, or the romanization of
+// the name against the query's, as a substring. SQLite has no trigram or
+// full-text index here, so near misses and words of the translation aren't
+// found as they are in Postgres. LIKE ignores case for ASCII. Without players
+// there are no regions, so a region filter matches nothing.
+const searchPublicTranslationsWhere = `
+	WHERE NOT hidden
+	  AND ? = ''
+	  AND (? = '' OR language = ?)
+	  AND (username LIKE ? ESCAPE '\' OR (? <> '' AND romanized LIKE ? ESCAPE '\'))`
+
+// SearchPublicTranslations ranks names the query spells out exactly, or
+// whose romanization is the query's, above those that only contain it.
+func (r *Repository) SearchPublicTranslations(ctx context.Context, arg db.SearchPublicTranslationsParams) ([]db.SearchPublicTranslationsRow, error) {
+	rows, err := r.executor.QueryContext(ctx, `
+		SELECT `+publicTranslationColumns+`,
+			CASE WHEN lower(username) = lower(?) OR (? <> '' AND romanized = ?) THEN 2.0 ELSE 1.0 END AS search_rank
+		FROM public_translations`+searchPublicTranslationsWhere+`
+		ORDER BY search_rank DESC, (upvotes - downvotes) DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, arg.Query, arg.Key, arg.Key,
+		arg.Region, arg.Language, arg.Language, likePattern(arg.Query), arg.Key, likePattern(arg.Key),
+		arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []db.SearchPublicTranslationsRow
+	for rows.Next() {
+		var row db.SearchPublicTranslationsRow
+		row.PublicTranslation, err = scanPublicTranslation(rows.Scan, &row.SearchRank)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+func (r *Repository) CountSearchPublicTranslations(ctx context.Context, arg db.CountSearchPublicTranslationsParams) (int64, error) {
+	var count int64
+	err := r.executor.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM public_translations`+searchPublicTranslationsWhere,
+		arg.Region, arg.Language, arg.Language, likePattern(arg.Query), arg.Key, likePattern(arg.Key),
+	).Scan(&count)
+	return count, err
+}
+
+// likePattern matches s anywhere in a LIKE with ESCAPE '\', with its
+// wildcards escaped.
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
+
+func (r *Repository) UpdatePublicTranslation(_ context.Context, _ db.UpdatePublicTranslationParams) (int64, error) {
+	return 0, fmt.Errorf("public translations not supported in SQLite mode")
+}
+
+func (r *Repository) SetPublicTranslationHidden(_ context.Context, _ db.SetPublicTranslationHiddenParams) (int64, error) {
+	return 0, fmt.Errorf("public translations not supported in SQLite mode")
+}
+
+func (r *Repository) DeletePublicTranslation(_ context.Context, _ int64) (int64, error) {
+	return 0, fmt.Errorf("public translations not supported in SQLite mode")
+}
+
+func (r *Repository) IncrementUpvotes(ctx context.Context, id int64) error {
+	_, err := r.executor.ExecContext(ctx, `UPDATE public_translations SET upvotes = upvotes + 1 WHERE id = ?`, id)
+	return err
+}
+
+func (r *Repository) DecrementUpvotes(ctx context.Context, id int64) error {
+	_, err := r.executor.ExecContext(ctx, `UPDATE public_translations SET upvotes = upvotes - 1 WHERE id = ?`, id)
+	return err
+}
+
+func (r *Repository) IncrementDownvotes(ctx context.Context, id int64) error {
+	_, err := r.executor.ExecContext(ctx, `UPDATE public_translations SET downvotes = downvotes + 1 WHERE id = ?`, id)
+	return err
+}
+
+func (r *Repository) DecrementDownvotes(ctx context.Context, id int64) error {
+	_, err := r.executor.ExecContext(ctx, `UPDATE public_translations SET downvotes = downvotes - 1 WHERE id = ?`, id)
+	return err
+}
+
 func (r *Repository) UpsertVote(_ context.Context, _ db.UpsertVoteParams) (db.Vote, error) {
 	return db.Vote{}, fmt.Errorf("votes not supported in SQLite mode")
 }
@@ -580,7 +904,11 @@ func (r *Repository) ListPublicFeedback(_ context.Context, _ db.ListPublicFeedba
 	return nil, fmt.Errorf("public feedback not supported in SQLite mode")
 }
 
-func (r *Repository) CountPublicFeedback(_ context.Context) (int64, error) {
+func (r *Repository) CountPublicFeedback(_ context.Context, _ bool) (int64, error) {
+	return 0, fmt.Errorf("public feedback not supported in SQLite mode")
+}
+
+func (r *Repository) ResolvePublicFeedback(_ context.Context, _ int64) (int64, error) {
 	return 0, fmt.Errorf("public feedback not supported in SQLite mode")
 }
 
@@ -588,6 +916,102 @@ func (r *Repository) CountVotesByIP(_ context.Context, _ string) (int64, error)
 	return 0, fmt.Errorf("votes not supported in SQLite mode")
 }
 
+func (r *Repository) IsVoteBanned(_ context.Context, _ db.IsVoteBannedParams) (bool, error) {
+	return false, fmt.Errorf("votes not supported in SQLite mode")
+}
+
+func (r *Repository) CreateTranslationAlternative(_ context.Context, _ db.CreateTranslationAlternativeParams) (db.TranslationAlternative, error) {
+	return db.TranslationAlternative{}, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) GetTranslationAlternative(_ context.Context, _ int64) (db.TranslationAlternative, error) {
+	return db.TranslationAlternative{}, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) ListTranslationAlternatives(_ context.Context, _ int64) ([]db.TranslationAlternative, error) {
+	return nil, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) AdjustTranslationAlternativeVotes(_ context.Context, _ db.AdjustTranslationAlternativeVotesParams) (db.TranslationAlternative, error) {
+	return db.TranslationAlternative{}, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) UpsertAlternativeVote(_ context.Context, _ db.UpsertAlternativeVoteParams) (db.AlternativeVote, error) {
+	return db.AlternativeVote{}, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) GetAlternativeVote(_ context.Context, _ db.GetAlternativeVoteParams) (db.AlternativeVote, error) {
+	return db.AlternativeVote{}, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) DeleteAlternativeVote(_ context.Context, _ db.DeleteAlternativeVoteParams) (int64, error) {
+	return 0, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) UpdateAlternativeLeads(_ context.Context, _ int32) (int64, error) {
+	return 0, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) ListPromotableAlternatives(_ context.Context, _ time.Time) ([]db.TranslationAlternative, error) {
+	return nil, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) PromoteTranslationAlternative(_ context.Context, _ int64) (int64, error) {
+	return 0, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) DeleteTranslationVotes(_ context.Context, _ int64) error {
+	return fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) CopyAlternativeVotes(_ context.Context, _ int64) error {
+	return fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) ListTranslationRevisions(_ context.Context, _ int64) ([]db.TranslationRevision, error) {
+	return nil, fmt.Errorf("translation alternatives not supported in SQLite mode")
+}
+
+func (r *Repository) CreateStreamEvent(_ context.Context, _ db.CreateStreamEventParams) (db.StreamEvent, error) {
+	return db.StreamEvent{}, fmt.Errorf("stream events not supported in SQLite mode")
+}
+
+func (r *Repository) NotifyStreamEvent(_ context.Context, _ string) error {
+	return fmt.Errorf("stream events not supported in SQLite mode")
+}
+
+func (r *Repository) ListStreamEventsAfter(_ context.Context, _ db.ListStreamEventsAfterParams) ([]db.StreamEvent, error) {
+	return nil, fmt.Errorf("stream events not supported in SQLite mode")
+}
+
+func (r *Repository) DeleteOldStreamEvents(_ context.Context, _ time.Time) (int64, error) {
+	return 0, fmt.Errorf("stream events not supported in SQLite mode")
+}
+
+func (r *Repository) CreateVoteBan(_ context.Context, _ db.CreateVoteBanParams) (db.VoteBan, error) {
+	return db.VoteBan{}, fmt.Errorf("moderation not supported in SQLite mode")
+}
+
+func (r *Repository) ListVoteBans(_ context.Context) ([]db.VoteBan, error) {
+	return nil, fmt.Errorf("moderation not supported in SQLite mode")
+}
+
+func (r *Repository) DeleteVoteBan(_ context.Context, _ int64) (int64, error) {
+	return 0, fmt.Errorf("moderation not supported in SQLite mode")
+}
+
+func (r *Repository) CreateAdminAuditLog(_ context.Context, _ db.CreateAdminAuditLogParams) error {
+	return fmt.Errorf("moderation not supported in SQLite mode")
+}
+
+func (r *Repository) ListAdminAuditLog(_ context.Context, _ db.ListAdminAuditLogParams) ([]db.AdminAuditLog, error) {
+	return nil, fmt.Errorf("moderation not supported in SQLite mode")
+}
+
+func (r *Repository) CountAdminAuditLog(_ context.Context) (int64, error) {
+	return 0, fmt.Errorf("moderation not supported in SQLite mode")
+}
+
 // Helper functions
 
 func scanSubscription(row *sql.Row) (db.Subscription, error) {
@@ -636,8 +1060,8 @@ func scanEval(row *sql.Row) (db.Eval, error) {
 
 func scanTranslation(row *sql.Row) (db.Translation, error) {
 	var t db.Translation
-	var createdAtStr string
-	err := row.Scan(&t.ID, &t.Username, &t.Translation, &t.Provider, &t.Model, &createdAtStr)
+	var createdAtStr, updatedAtStr string
+	err := row.Scan(&t.ID, &t.Username, &t.Translation, &t.Provider, &t.Model, &createdAtStr, &t.PromptVersion, &t.SeenCount, &t.ExamplesVersion, &t.TargetLanguage, &updatedAtStr)
 	if err == sql.ErrNoRows {
 		return db.Translation{}, db.ErrNoRows
 	}
@@ -645,18 +1069,51 @@ func scanTranslation(row *sql.Row) (db.Translation, error) {
 		return db.Translation{}, err
 	}
 	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr)
+	t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
 	return t, nil
 }
 
+// scanPublicTranslation reads publicTranslationColumns, followed by extra.
+func scanPublicTranslation(scan func(dest ...interface{}) error, extra ...interface{}) (db.PublicTranslation, error) {
+	var t db.PublicTranslation
+	var createdAtStr string
+	dest := append([]interface{}{&t.ID, &t.Username, &t.Translation, &t.Explanation, &t.Language, &t.SourceBotID,
+		&t.RiotVerified, &t.Upvotes, &t.Downvotes, &createdAtStr, &t.Hidden}, extra...)
+	err := scan(dest...)
+	if err == sql.ErrNoRows {
+		return db.PublicTranslation{}, db.ErrNoRows
+	}
+	if err != nil {
+		return db.PublicTranslation{}, err
+	}
+	t.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr)
+	return t, nil
+}
+
+func scanServerConfig(row *sql.Row) (db.ServerConfig, error) {
+	var c db.ServerConfig
+	var updatedAtStr string
+	err := row.Scan(&c.ServerID, &c.TargetLanguage, &c.Scripts, &updatedAtStr)
+	if err == sql.ErrNoRows {
+		return db.ServerConfig{}, db.ErrNoRows
+	}
+	if err != nil {
+		return db.ServerConfig{}, err
+	}
+	c.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
+	return c, nil
+}
+
 func scanTranslations(rows *sql.Rows) ([]db.Translation, error) {
 	var translations []db.Translation
 	for rows.Next() {
 		var t db.Translation
-		var createdAtStr string
-		if err := rows.Scan(&t.ID, &t.Username, &t.Translation, &t.Provider, &t.Model, &createdAtStr); err != nil {
+		var createdAtStr, updatedAtStr string
+		if err := rows.Scan(&t.ID, &t.Username, &t.Translation, &t.Provider, &t.Model, &createdAtStr, &t.PromptVersion, &t.SeenCount, &t.ExamplesVersion, &t.TargetLanguage, &updatedAtStr); err != nil {
 			return nil, err
 		}
 		t.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr)
+		t.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
 		translations = append(translations, t)
 	}
 	return translations, rows.Err()
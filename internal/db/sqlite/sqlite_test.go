@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -131,38 +132,84 @@ func TestTranslationCRUD(t *testing.T) {
 	ctx := context.Background()
 
 	tr, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username:    "玩家",
-		Translation: "Player",
-		Provider:    "anthropic",
-		Model:       "claude-3",
+		Username:       "玩家",
+		TargetLanguage: "en",
+		Translation:    "Player",
+		Provider:       "anthropic",
+		Model:          "claude-3",
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "Player", tr.Translation)
 
-	got, err := repo.GetTranslation(ctx, "玩家")
+	got, err := repo.GetTranslation(ctx, db.GetTranslationParams{Username: "玩家", TargetLanguage: "en"})
 	require.NoError(t, err)
 	assert.Equal(t, tr.ID, got.ID)
 
-	batch, err := repo.GetTranslations(ctx, []string{"玩家", "nonexistent"})
+	batch, err := repo.GetTranslations(ctx, db.GetTranslationsParams{Usernames: []string{"玩家", "nonexistent"}, TargetLanguage: "en"})
 	require.NoError(t, err)
 	assert.Len(t, batch, 1)
 
-	empty, err := repo.GetTranslations(ctx, []string{})
+	empty, err := repo.GetTranslations(ctx, db.GetTranslationsParams{Usernames: []string{}, TargetLanguage: "en"})
 	require.NoError(t, err)
 	assert.Empty(t, empty)
 
 	// Upsert
 	updated, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username:    "玩家",
-		Translation: "Gamer",
-		Provider:    "google",
-		Model:       "gemini",
+		Username:       "玩家",
+		TargetLanguage: "en",
+		Translation:    "Gamer",
+		Provider:       "google",
+		Model:          "gemini",
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "Gamer", updated.Translation)
 	assert.Equal(t, "google", updated.Provider)
 }
 
+func TestTranslationPerTargetLanguage(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for lang, translated := range map[string]string{"en": "Player", "es": "Jugador"} {
+		_, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
+			Username: "玩家", TargetLanguage: lang, Translation: translated, Provider: "test", Model: "test",
+		})
+		require.NoError(t, err)
+	}
+
+	es, err := repo.GetTranslations(ctx, db.GetTranslationsParams{Usernames: []string{"玩家"}, TargetLanguage: "es"})
+	require.NoError(t, err)
+	require.Len(t, es, 1)
+	assert.Equal(t, "Jugador", es[0].Translation)
+
+	require.NoError(t, repo.IncrementTranslationSeenCount(ctx, db.IncrementTranslationSeenCountParams{Usernames: []string{"玩家"}, TargetLanguage: "es"}))
+	en, err := repo.GetTranslation(ctx, db.GetTranslationParams{Username: "玩家", TargetLanguage: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), en.SeenCount)
+
+	_, err = repo.GetTranslation(ctx, db.GetTranslationParams{Username: "玩家", TargetLanguage: "pt"})
+	assert.True(t, db.IsNoRows(err))
+}
+
+func TestServerConfig(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.GetServerConfig(ctx, "server-1")
+	assert.True(t, db.IsNoRows(err))
+
+	cfg, err := repo.UpsertServerConfig(ctx, db.UpsertServerConfigParams{ServerID: "server-1", TargetLanguage: "es"})
+	require.NoError(t, err)
+	assert.Equal(t, "es", cfg.TargetLanguage)
+
+	_, err = repo.UpsertServerConfig(ctx, db.UpsertServerConfigParams{ServerID: "server-1", TargetLanguage: "pt", Scripts: "thai,cyrillic"})
+	require.NoError(t, err)
+	cfg, err = repo.GetServerConfig(ctx, "server-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pt", cfg.TargetLanguage)
+	assert.Equal(t, "thai,cyrillic", cfg.Scripts)
+}
+
 func TestFeedback(t *testing.T) {
 	repo := newTestRepo(t)
 	ctx := context.Background()
@@ -181,12 +228,12 @@ func TestDeleteOldTranslations(t *testing.T) {
 	ctx := context.Background()
 
 	_, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username: "old_user", Translation: "Old", Provider: "test", Model: "test",
+		Username: "old_user", TargetLanguage: "en", Translation: "Old", Provider: "test", Model: "test",
 	})
 	require.NoError(t, err)
 
 	_, err = repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username: "new_user", Translation: "New", Provider: "test", Model: "test",
+		Username: "new_user", TargetLanguage: "en", Translation: "New", Provider: "test", Model: "test",
 	})
 	require.NoError(t, err)
 
@@ -195,7 +242,7 @@ func TestDeleteOldTranslations(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), deleted)
 
-	remaining, err := repo.GetTranslations(ctx, []string{"old_user", "new_user"})
+	remaining, err := repo.GetTranslations(ctx, db.GetTranslationsParams{Usernames: []string{"old_user", "new_user"}, TargetLanguage: "en"})
 	require.NoError(t, err)
 	assert.Empty(t, remaining)
 }
@@ -214,6 +261,31 @@ func TestDeleteOldFeedback(t *testing.T) {
 	assert.Equal(t, int64(1), deleted)
 }
 
+func TestLLMUsage(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, u := range []db.CreateLLMUsageParams{
+		{ServerID: "server-1", Provider: "anthropic", Model: "m", InputTokens: 100, OutputTokens: 10, CostMicroUSD: 150},
+		{ServerID: "server-1", Provider: "anthropic", Model: "m", InputTokens: 50, OutputTokens: 5, CostMicroUSD: 75},
+		{ServerID: "server-2", Provider: "anthropic", Model: "m", InputTokens: 100, OutputTokens: 10, CostMicroUSD: 150},
+	} {
+		require.NoError(t, repo.CreateLLMUsage(ctx, u))
+	}
+
+	spent, err := repo.SumLLMUsageCostByServerSince(ctx, "server-1", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(225), spent)
+
+	spent, err = repo.SumLLMUsageCostByServerSince(ctx, "server-1", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, spent)
+
+	spent, err = repo.SumLLMUsageCostByServerSince(ctx, "unknown", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, spent)
+}
+
 func TestWithTxCommit(t *testing.T) {
 	repo := newTestRepo(t)
 	ctx := context.Background()
@@ -368,7 +440,7 @@ func TestTranslationToEval(t *testing.T) {
 	require.NoError(t, err)
 
 	tr, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
-		Username: "玩家", Translation: "Player", Provider: "test", Model: "test",
+		Username: "玩家", TargetLanguage: "en", Translation: "Player", Provider: "test", Model: "test",
 	})
 	require.NoError(t, err)
 
@@ -419,3 +491,238 @@ func TestDeleteSubscriptions(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), deleted)
 }
+
+func TestTranslationPromptVersionAndSeenCount(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"一", "二", "三"} {
+		tr, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
+			Username: name, TargetLanguage: "en", Translation: "x", Provider: "test", Model: "m1", PromptVersion: "v1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "v1", tr.PromptVersion)
+		assert.Equal(t, int64(1), tr.SeenCount)
+	}
+
+	require.NoError(t, repo.IncrementTranslationSeenCount(ctx, db.IncrementTranslationSeenCountParams{Usernames: []string{"二", "三"}, TargetLanguage: "en"}))
+	require.NoError(t, repo.IncrementTranslationSeenCount(ctx, db.IncrementTranslationSeenCountParams{Usernames: []string{"三"}, TargetLanguage: "en"}))
+	require.NoError(t, repo.IncrementTranslationSeenCount(ctx, db.IncrementTranslationSeenCountParams{Usernames: []string{}, TargetLanguage: "en"}))
+
+	top, err := repo.ListMostSeenTranslations(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	assert.Equal(t, "三", top[0].Username)
+	assert.Equal(t, int64(3), top[0].SeenCount)
+	assert.Equal(t, "二", top[1].Username)
+
+	// Re-translating keeps the seen count and updates the version tag
+	updated, err := repo.CreateTranslation(ctx, db.CreateTranslationParams{
+		Username: "三", TargetLanguage: "en", Translation: "Three", Provider: "test", Model: "m2", PromptVersion: "v2", ExamplesVersion: "3,7",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "m2", updated.Model)
+	assert.Equal(t, "v2", updated.PromptVersion)
+	assert.Equal(t, "3,7", updated.ExamplesVersion)
+	assert.Equal(t, int64(3), updated.SeenCount)
+}
+
+func TestMigrateAddsMissingColumns(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// Simulate a database created before prompt_version/seen_count existed
+	oldDB, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = oldDB.ExecContext(ctx, `
+		CREATE TABLE translations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL UNIQUE,
+			translation TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			model TEXT NOT NULL,
+			created_at TEXT NOT NULL DEFAULT (datetime('now'))
+		);
+		INSERT INTO translations (username, translation, provider, model) VALUES ('玩家', 'Player', 'test', 'old');
+		CREATE TABLE server_configs (
+			server_id TEXT PRIMARY KEY,
+			target_language TEXT NOT NULL DEFAULT 'en',
+			updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+		);
+		INSERT INTO server_configs (server_id, target_language) VALUES ('server-1', 'es');
+	`)
+	require.NoError(t, err)
+	require.NoError(t, oldDB.Close())
+
+	repo, err := New(ctx, path)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	tr, err := repo.GetTranslation(ctx, db.GetTranslationParams{Username: "玩家", TargetLanguage: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "", tr.PromptVersion)
+	assert.Equal(t, int64(1), tr.SeenCount)
+	assert.Equal(t, "", tr.ExamplesVersion)
+	assert.Equal(t, "en", tr.TargetLanguage)
+
+	// The old UNIQUE (username) constraint is rebuilt as (username, target_language)
+	_, err = repo.CreateTranslation(ctx, db.CreateTranslationParams{
+		Username: "玩家", TargetLanguage: "es", Translation: "Jugador", Provider: "test", Model: "new",
+	})
+	require.NoError(t, err)
+	tr, err = repo.GetTranslation(ctx, db.GetTranslationParams{Username: "玩家", TargetLanguage: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "Player", tr.Translation)
+
+	cfg, err := repo.GetServerConfig(ctx, "server-1")
+	require.NoError(t, err)
+	assert.Equal(t, "es", cfg.TargetLanguage)
+	assert.Equal(t, "", cfg.Scripts)
+
+	// Tables that didn't exist yet are created too
+	_, err = repo.CreateFeedback(ctx, db.CreateFeedbackParams{DiscordMessageID: "msg-1", FeedbackText: "ok"})
+	require.NoError(t, err)
+}
+
+func TestPublicTranslationUpsert(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	first, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+		Username: "Faker#KR1", Translation: "Faker", Language: "korean", PlayerUsername: "Faker#KR1",
+		UsernameKey: "Faker#KR1", Romanized: "faker",
+	})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), first.CreatedAt, time.Minute)
+
+	// The same name under a lookalike spelling updates the first row.
+	again, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+		Username: "Fаker#KR1", Translation: "The Faker", Language: "korean", PlayerUsername: "Fаker#KR1",
+		UsernameKey: "Faker#KR1", Romanized: "faker", RiotVerified: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, "Faker#KR1", again.Username)
+	assert.Equal(t, "The Faker", again.Translation)
+	assert.True(t, again.RiotVerified)
+
+	byKey, err := repo.GetPublicTranslationByUsernameKey(ctx, "Faker#KR1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, byKey.ID)
+
+	_, err = repo.GetPublicTranslation(ctx, first.ID+1)
+	assert.ErrorIs(t, err, db.ErrNoRows)
+}
+
+func TestSearchPublicTranslations(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, p := range []db.UpsertPublicTranslationParams{
+		{Username: "페이커#KR1", Translation: "Faker", Language: "korean", UsernameKey: "페이커#KR1", Romanized: "peikeo"},
+		{Username: "페이커팬#KR1", Translation: "Faker fan", Language: "korean", UsernameKey: "페이커팬#KR1", Romanized: "peikeopaen"},
+		{Username: "100%_win#NA1", Translation: "100% win", Language: "chinese", UsernameKey: "100%_win#NA1"},
+		{Username: "大魔王#TW1", Translation: "Great Demon King", Language: "chinese", UsernameKey: "大魔王#TW1", Romanized: "damowang"},
+	} {
+		p.PlayerUsername = p.Username
+		_, err := repo.UpsertPublicTranslation(ctx, p)
+		require.NoError(t, err)
+	}
+
+	search := func(query, key, language string) []string {
+		t.Helper()
+		results, err := repo.SearchPublicTranslations(ctx, db.SearchPublicTranslationsParams{
+			Query: query, Key: key, Language: language, Limit: 10,
+		})
+		require.NoError(t, err)
+		count, err := repo.CountSearchPublicTranslations(ctx, db.CountSearchPublicTranslationsParams{
+			Query: query, Key: key, Language: language,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(results)), count)
+		names := make([]string, len(results))
+		for i, r := range results {
+			names[i] = r.Username
+		}
+		return names
+	}
+
+	// The exact romanization ranks above a longer name that contains it.
+	assert.Equal(t, []string{"페이커#KR1", "페이커팬#KR1"}, search("peikeo", "peikeo", ""))
+	assert.Equal(t, []string{"大魔王#TW1"}, search("魔王", "mowang", ""))
+	assert.Equal(t, []string{"大魔王#TW1"}, search("DaMoWang", "damowang", "chinese"))
+	assert.Empty(t, search("peikeo", "peikeo", "chinese"))
+	// Wildcards in the query are matched literally.
+	assert.Equal(t, []string{"100%_win#NA1"}, search("%_", "", ""))
+
+	results, err := repo.SearchPublicTranslations(ctx, db.SearchPublicTranslationsParams{Query: "peikeo", Key: "peikeo", Region: "KR", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, results, "SQLite keeps no regions")
+}
+
+func TestListPublicTranslationsByScore(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	votes := map[string][2]int{
+		"sweep#NA1":    {3, 0},
+		"loved#NA1":    {95, 10},
+		"split#NA1":    {10, 9},
+		"lopsided#NA1": {50, 1},
+	}
+	ids := make(map[int64]string)
+	for name, v := range votes {
+		pt, err := repo.UpsertPublicTranslation(ctx, db.UpsertPublicTranslationParams{
+			Username: name, Translation: name, Language: "chinese", PlayerUsername: name, UsernameKey: name,
+		})
+		require.NoError(t, err)
+		ids[pt.ID] = name
+		for range v[0] {
+			require.NoError(t, repo.IncrementUpvotes(ctx, pt.ID))
+		}
+		for range v[1] {
+			require.NoError(t, repo.IncrementDownvotes(ctx, pt.ID))
+		}
+	}
+
+	best, err := repo.ListPublicTranslationsBest(ctx, db.ListPublicTranslationsBestParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, best, 4)
+	// A 95-10 translation outranks a 3-0 one.
+	assert.Equal(t, []string{"lopsided#NA1", "loved#NA1", "sweep#NA1", "split#NA1"}, []string{
+		best[0].Username, best[1].Username, best[2].Username, best[3].Username,
+	})
+	assert.InDelta(t, 0.8335, best[1].BestScore, 0.001)
+	assert.Equal(t, int32(95), best[1].Upvotes)
+
+	// The next page resumes after the last row's score.
+	next, err := repo.ListPublicTranslationsBest(ctx, db.ListPublicTranslationsBestParams{
+		Limit: 10, AfterID: best[1].ID, AfterScore: best[1].BestScore,
+	})
+	require.NoError(t, err)
+	require.Len(t, next, 2)
+	assert.Equal(t, "sweep#NA1", next[0].Username)
+
+	controversial, err := repo.ListPublicTranslationsControversial(ctx, db.ListPublicTranslationsControversialParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, controversial, 4)
+	assert.Equal(t, "split#NA1", controversial[0].Username)
+	assert.Equal(t, "loved#NA1", controversial[1].Username)
+	assert.Zero(t, controversial[3].Controversy, "no votes against")
+
+	top, err := repo.ListPublicTranslationsTop(ctx, db.ListPublicTranslationsTopParams{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "loved#NA1", top[0].Username)
+
+	// The period leaves out translations created before it.
+	recent, err := repo.ListPublicTranslationsBest(ctx, db.ListPublicTranslationsBestParams{
+		Limit: 10, CreatedAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, recent)
+
+	count, err := repo.CountPublicTranslations(ctx, db.CountPublicTranslationsParams{Language: "chinese"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+}
@@ -0,0 +1,244 @@
+// Package dictionary provides offline word lookups for Chinese and Korean
+// summoner names. It backs the translator when no LLM is available, so the
+// output is a word-by-word gloss rather than a real translation, and breaks
+// Han names down into per-character readings and meanings.
+package dictionary
+
+//go:generate go run gen.go
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jusunglee/leagueofren/internal/cedict"
+	"github.com/samber/lo"
+)
+
+//go:embed data
+var data embed.FS
+
+// Segment is a run of a name that was looked up as one unit. Gloss is empty
+// when no dictionary word covered the run.
+type Segment struct {
+	Text  string
+	Gloss string
+}
+
+// Dictionary segments names by greedy longest match against its word lists.
+type Dictionary struct {
+	words   map[string]string
+	maxLen  int
+	entries int
+
+	// Per-character data for Glyphs: CC-CEDICT glosses of single
+	// characters, simplified to traditional forms, and the bundled Hanja and
+	// kanji readings.
+	meanings    map[string][]string
+	traditional map[string]string
+	hanja       map[string]string
+	kanji       map[string]kanjiEntry
+}
+
+// Bundled returns a dictionary built from the CC-CEDICT release and word lists
+// shipped with the binary.
+func Bundled() (*Dictionary, error) {
+	r, err := cedict.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening bundled CC-CEDICT: %w", err)
+	}
+	defer r.Close()
+
+	return WithCEDICT(r)
+}
+
+// WithCEDICT returns a dictionary built from a CC-CEDICT file in place of the
+// bundled release, together with the bundled gaming senses, Korean word list
+// and Hanja and kanji readings.
+func WithCEDICT(r io.Reader) (*Dictionary, error) {
+	generated, err := fs.Sub(data, "data")
+	if err != nil {
+		return nil, err
+	}
+	return build(generated, r)
+}
+
+// build returns a dictionary built from a CC-CEDICT file and the lists gen.go
+// writes (korean.tsv, hanja.tsv and kanji.tsv) in generated, together with the
+// bundled gaming senses, which take precedence over both word lists.
+func build(generated fs.FS, r io.Reader) (*Dictionary, error) {
+	names, err := data.Open("data/names.u8")
+	if err != nil {
+		return nil, fmt.Errorf("opening bundled name glosses: %w", err)
+	}
+	defer names.Close()
+
+	koreanNames, err := data.Open("data/korean_names.tsv")
+	if err != nil {
+		return nil, fmt.Errorf("opening bundled Korean name glosses: %w", err)
+	}
+	defer koreanNames.Close()
+
+	korean, err := generated.Open("korean.tsv")
+	if err != nil {
+		return nil, fmt.Errorf("opening bundled Korean word list: %w", err)
+	}
+	defer korean.Close()
+
+	// The first entry for a word wins, so the gaming senses go first.
+	d, err := New(io.MultiReader(names, r), io.MultiReader(koreanNames, korean))
+	if err != nil {
+		return nil, err
+	}
+
+	hanja, err := generated.Open("hanja.tsv")
+	if err != nil {
+		return nil, fmt.Errorf("opening bundled Hanja list: %w", err)
+	}
+	defer hanja.Close()
+
+	kanji, err := generated.Open("kanji.tsv")
+	if err != nil {
+		return nil, fmt.Errorf("opening bundled KANJIDIC2: %w", err)
+	}
+	defer kanji.Close()
+
+	if err := d.LoadReadings(hanja, kanji); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// New builds a dictionary from a CC-CEDICT file and a tab-separated Korean
+// word list. Either reader may be nil. The Hanja and kanji readings Glyphs
+// reports are added by LoadReadings, which Bundled and WithCEDICT call with
+// the bundled lists.
+func New(cedict, korean io.Reader) (*Dictionary, error) {
+	d := &Dictionary{
+		words:       make(map[string]string),
+		meanings:    make(map[string][]string),
+		traditional: make(map[string]string),
+		hanja:       make(map[string]string),
+		kanji:       make(map[string]kanjiEntry),
+	}
+	if cedict != nil {
+		if err := d.loadCEDICT(cedict); err != nil {
+			return nil, fmt.Errorf("loading CC-CEDICT: %w", err)
+		}
+	}
+	if korean != nil {
+		if err := d.loadKorean(korean); err != nil {
+			return nil, fmt.Errorf("loading Korean word list: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// Len returns the number of distinct words in the dictionary.
+func (d *Dictionary) Len() int {
+	return d.entries
+}
+
+// Segment splits name into dictionary words, preferring the longest match at
+// each position. Consecutive characters that match nothing are grouped into a
+// single unglossed segment.
+func (d *Dictionary) Segment(name string) []Segment {
+	runes := []rune(name)
+	var segments []Segment
+	var unknown []rune
+
+	flush := func() {
+		if len(unknown) > 0 {
+			segments = append(segments, Segment{Text: string(unknown)})
+			unknown = nil
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			flush()
+			i++
+			continue
+		}
+
+		matched := false
+		for n := min(d.maxLen, len(runes)-i); n > 0; n-- {
+			word := string(runes[i : i+n])
+			if gloss, ok := d.words[word]; ok {
+				flush()
+				segments = append(segments, Segment{Text: word, Gloss: gloss})
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unknown = append(unknown, runes[i])
+			i++
+		}
+	}
+	flush()
+
+	return segments
+}
+
+func (d *Dictionary) add(word, gloss string) {
+	if word == "" || gloss == "" {
+		return
+	}
+	// The first entry wins: CC-CEDICT lists the most common reading first.
+	if _, ok := d.words[word]; ok {
+		return
+	}
+	d.words[word] = gloss
+	d.entries++
+	d.maxLen = max(d.maxLen, utf8.RuneCountInString(word))
+}
+
+// loadCEDICT keeps the first gloss of each entry for both its traditional and
+// simplified forms. Single characters also keep their first few glosses for
+// Glyphs.
+func (d *Dictionary) loadCEDICT(r io.Reader) error {
+	return cedict.Parse(r, func(e cedict.Entry) {
+		glosses := lo.Filter(e.Glosses, func(g string, _ int) bool {
+			return !isFiller(g)
+		})
+		if len(glosses) == 0 {
+			return
+		}
+		d.add(e.Simplified, glosses[0])
+		d.add(e.Traditional, glosses[0])
+		if utf8.RuneCountInString(e.Simplified) == 1 {
+			d.addCharacter(e.Traditional, e.Simplified, glosses)
+		}
+	})
+}
+
+// isFiller reports whether a CC-CEDICT gloss says nothing about a name:
+// measure words, and the surname entries listed ahead of a character's
+// ordinary senses.
+func isFiller(gloss string) bool {
+	gloss = strings.TrimSpace(gloss)
+	return gloss == "" || strings.HasPrefix(gloss, "CL:") || strings.HasPrefix(gloss, "surname ")
+}
+
+func (d *Dictionary) loadKorean(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, gloss, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		d.add(strings.TrimSpace(word), strings.TrimSpace(gloss))
+	}
+	return sc.Err()
+}
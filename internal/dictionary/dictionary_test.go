@@ -0,0 +1,142 @@
+package dictionary
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundled(t *testing.T) {
+	d, err := Bundled()
+	require.NoError(t, err)
+	assert.Greater(t, d.Len(), 100)
+}
+
+// fixture returns a dictionary built from the CC-CEDICT subset and word and
+// reading lists in testdata rather than the bundled ones, so the glosses and
+// readings the tests expect don't change with them.
+func fixture(t *testing.T) *Dictionary {
+	t.Helper()
+	f, err := os.Open("testdata/cedict.u8")
+	require.NoError(t, err)
+	defer f.Close()
+
+	d, err := build(os.DirFS("testdata"), f)
+	require.NoError(t, err)
+	return d
+}
+
+func TestSegment(t *testing.T) {
+	d := fixture(t)
+
+	tests := []struct {
+		name string
+		want []Segment
+	}{
+		{"大魔王", []Segment{{Text: "大魔王", Gloss: "great demon king"}}},
+		{"龍王歸來", []Segment{{Text: "龍王", Gloss: "Dragon King"}, {Text: "歸來", Gloss: "to return"}}},
+		{"暗黑破坏神", []Segment{{Text: "暗黑", Gloss: "dark"}, {Text: "破坏神", Gloss: "god of destruction"}}},
+		{"하늘바라기", []Segment{{Text: "하늘", Gloss: "sky"}, {Text: "바라기", Gloss: "one who gazes at"}}},
+		{"꿈을꾸다", []Segment{{Text: "꿈", Gloss: "dream"}, {Text: "을"}, {Text: "꾸다", Gloss: "to dream"}}},
+		{"페이커 팬", []Segment{{Text: "페이커", Gloss: "Faker (pro player)"}, {Text: "팬"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, d.Segment(tt.name))
+		})
+	}
+}
+
+func TestNewParsesCEDICT(t *testing.T) {
+	cedict := strings.NewReader(`# comment
+電腦 电脑 [dian4 nao3] /computer/CL:臺|台[tai2]/
+電 电 [dian4] /electric/
+電 电 [Dian4] /surname Dian/
+malformed line
+`)
+	d, err := New(cedict, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, d.Len(), "traditional and simplified forms are both indexed")
+	assert.Equal(t, []Segment{{Text: "電腦", Gloss: "computer"}}, d.Segment("電腦"))
+	assert.Equal(t, []Segment{{Text: "电", Gloss: "electric"}}, d.Segment("电"))
+}
+
+func TestGlyphs(t *testing.T) {
+	d := fixture(t)
+
+	glyphs := d.Glyphs("大魔王#NA1")
+	require.Len(t, glyphs, 3, "only Han characters, without the tag")
+	assert.Equal(t, Glyph{
+		Char:     "大",
+		Pinyin:   "dà",
+		Eum:      "대",
+		On:       []string{"ダイ", "タイ"},
+		Kun:      []string{"おお"},
+		Meanings: []string{"big", "great"},
+	}, glyphs[0])
+	assert.Equal(t, "魔", glyphs[1].Char)
+	assert.Equal(t, "王", glyphs[2].Char)
+	assert.Equal(t, "왕", glyphs[2].Eum)
+}
+
+func TestGlyphsSimplifiedUsesTraditionalReadings(t *testing.T) {
+	d := fixture(t)
+
+	glyphs := d.Glyphs("龙")
+	require.Len(t, glyphs, 1)
+	assert.Equal(t, "lóng", glyphs[0].Pinyin)
+	assert.Equal(t, "룡", glyphs[0].Eum, "read from 龍")
+	assert.Equal(t, []string{"リュウ", "リョウ"}, glyphs[0].On)
+	assert.Equal(t, []string{"dragon"}, glyphs[0].Meanings)
+}
+
+func TestGlyphsFallsBackToKanjiMeanings(t *testing.T) {
+	d := fixture(t)
+
+	// 暗 has no single-character entry in the CC-CEDICT fixture.
+	glyphs := d.Glyphs("暗")
+	require.Len(t, glyphs, 1)
+	assert.Equal(t, []string{"darkness", "disappear"}, glyphs[0].Meanings)
+	assert.Empty(t, d.Glyphs("Faker 페이커"))
+}
+
+func TestNewKeepsCharacterGlosses(t *testing.T) {
+	cedict := strings.NewReader(`電 电 [dian4] /electric/electricity/CL:度[du4]/
+電 电 [Dian4] /surname Dian/
+`)
+	d, err := New(cedict, nil)
+	require.NoError(t, err)
+
+	glyphs := d.Glyphs("电")
+	require.Len(t, glyphs, 1)
+	assert.Equal(t, []string{"electric", "electricity"}, glyphs[0].Meanings)
+	assert.Empty(t, glyphs[0].Eum, "New doesn't load the bundled readings")
+}
+
+func TestBundledBeyondCuratedLists(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the bundled lists are development subsets until make dictionaries")
+	}
+	d, err := Bundled()
+	require.NoError(t, err)
+	// gen.go refuses downloads with fewer entries than these.
+	require.GreaterOrEqual(t, len(d.kanji), 10_000, "kanji.tsv is the development subset; run make dictionaries")
+	require.GreaterOrEqual(t, len(d.hanja), 7_000, "hanja.tsv is the development subset; run make dictionaries")
+
+	// Nothing in 鬱金香 (tulip) or 도서관 (library) was ever in the
+	// hand-picked lists.
+	assert.Equal(t, []Segment{{Text: "鬱金香", Gloss: "tulip"}}, d.Segment("鬱金香"))
+	segments := d.Segment("도서관")
+	require.Len(t, segments, 1)
+	assert.NotEmpty(t, segments[0].Gloss, "korean.tsv is the development subset; run make dictionaries")
+
+	glyphs := d.Glyphs("鬱")
+	require.Len(t, glyphs, 1)
+	assert.Equal(t, "울", glyphs[0].Eum)
+	assert.Contains(t, glyphs[0].On, "ウツ")
+	assert.NotEmpty(t, glyphs[0].Meanings)
+}
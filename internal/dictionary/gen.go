@@ -0,0 +1,291 @@
+//go:build ignore
+
+// gen downloads kengdic, KANJIDIC2 and the Unihan database and writes the
+// Korean word list and the Hanja and kanji reading lists in data/. Run it with
+// go generate (or make dictionaries) to update them.
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	kengdicURL  = "https://raw.githubusercontent.com/garfieldnate/kengdic/master/kengdic_2011.tsv"
+	kanjidicURL = "http://www.edrdg.org/kanjidic/kanjidic2.xml.gz"
+	unihanURL   = "https://www.unicode.org/Public/UCD/latest/ucd/Unihan.zip"
+
+	// minKorean, minKanji and minHanja guard against replacing the lists
+	// with a truncated download; kengdic has over 100,000 words, KANJIDIC2
+	// over 13,000 characters and Unihan over 8,000 with a Korean reading.
+	minKorean = 50_000
+	minKanji  = 10_000
+	minHanja  = 7_000
+)
+
+const koreanHeader = `# Korean word list from the Korean-English Dictionary (kengdic), written by
+# gen.go, for offline translation.
+# kengdic is licensed under the Mozilla Public License 2.0.
+# Source: https://github.com/garfieldnate/kengdic
+#
+# One entry per line: word<TAB>English gloss
+`
+
+const kanjiHeader = `# KANJIDIC2, written by gen.go, for the per-character breakdown.
+# KANJIDIC2 is the property of the Electronic Dictionary Research and
+# Development Group, and is used in conformance with the Group's licence:
+# Creative Commons Attribution-ShareAlike 4.0 International.
+# Source: https://www.edrdg.org/wiki/index.php/KANJIDIC_Project
+#
+# One entry per line: character<TAB>on readings<TAB>kun readings<TAB>meanings
+# Readings are space-separated, on in katakana and kun in hiragana without
+# okurigana; meanings are separated by "; ".
+`
+
+const hanjaHeader = `# Hanja readings from the kHangul field of the Unicode Han Database (Unihan),
+# written by gen.go, for the per-character breakdown.
+# Copyright © Unicode, Inc. Licensed under the Unicode License v3:
+# https://www.unicode.org/license.txt
+#
+# One entry per line: character<TAB>Korean reading (eum), in its dictionary
+# form before the initial sound rule (龍 룡, not 용).
+`
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	kengdic, err := download(kengdicURL)
+	if err != nil {
+		return err
+	}
+	korean, err := parseKengdic(kengdic)
+	if err != nil {
+		return fmt.Errorf("parsing kengdic: %w", err)
+	}
+	if len(korean) < minKorean {
+		return fmt.Errorf("kengdic download has only %d words", len(korean))
+	}
+
+	kanjidic, err := download(kanjidicURL)
+	if err != nil {
+		return err
+	}
+	kanji, err := parseKanjidic(kanjidic)
+	if err != nil {
+		return fmt.Errorf("parsing KANJIDIC2: %w", err)
+	}
+	if len(kanji) < minKanji {
+		return fmt.Errorf("KANJIDIC2 download has only %d characters", len(kanji))
+	}
+
+	unihan, err := download(unihanURL)
+	if err != nil {
+		return err
+	}
+	hanja, err := parseUnihan(unihan)
+	if err != nil {
+		return fmt.Errorf("parsing Unihan: %w", err)
+	}
+	if len(hanja) < minHanja {
+		return fmt.Errorf("Unihan download has only %d Korean readings", len(hanja))
+	}
+
+	if err := writeList("data/korean.tsv", koreanHeader, korean); err != nil {
+		return err
+	}
+	if err := writeList("data/kanji.tsv", kanjiHeader, kanji); err != nil {
+		return err
+	}
+	if err := writeList("data/hanja.tsv", hanjaHeader, hanja); err != nil {
+		return err
+	}
+	log.Printf("wrote data/korean.tsv (%d words), data/kanji.tsv (%d characters) and data/hanja.tsv (%d characters)",
+		len(korean), len(kanji), len(hanja))
+	return nil
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	return body, nil
+}
+
+// parseKengdic returns the first gloss of each word in kengdic, keyed by the
+// word. Its columns are named in a header row.
+func parseKengdic(tsv []byte) (map[string]string, error) {
+	sc := bufio.NewScanner(bytes.NewReader(tsv))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if !sc.Scan() {
+		return nil, fmt.Errorf("missing header: %w", sc.Err())
+	}
+	header := strings.Split(sc.Text(), "\t")
+	surface, gloss := slices.Index(header, "surface"), slices.Index(header, "gloss")
+	if surface < 0 || gloss < 0 {
+		return nil, fmt.Errorf("header %q has no surface or gloss column", sc.Text())
+	}
+
+	words := make(map[string]string)
+	for sc.Scan() {
+		fields := strings.Split(sc.Text(), "\t")
+		if len(fields) <= max(surface, gloss) {
+			continue
+		}
+		word := strings.TrimSpace(fields[surface])
+		// Glosses list their senses separated by semicolons; the first is
+		// the usual one.
+		first, _, _ := strings.Cut(fields[gloss], ";")
+		first = strings.TrimSpace(first)
+		if word == "" || first == "" {
+			continue
+		}
+		if _, ok := words[word]; !ok {
+			words[word] = first
+		}
+	}
+	return words, sc.Err()
+}
+
+// kanjidicCharacter is the part of a KANJIDIC2 <character> the breakdown uses.
+type kanjidicCharacter struct {
+	Literal  string `xml:"literal"`
+	Readings []struct {
+		Type  string `xml:"r_type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"reading_meaning>rmgroup>reading"`
+	Meanings []struct {
+		Lang  string `xml:"m_lang,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"reading_meaning>rmgroup>meaning"`
+}
+
+// parseKanjidic returns a kanji.tsv line per character, keyed by it.
+func parseKanjidic(gzipped []byte) (map[string]string, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(gzipped))
+	if err != nil {
+		return nil, err
+	}
+	dec := xml.NewDecoder(zr)
+
+	lines := make(map[string]string)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "character" {
+			continue
+		}
+		var c kanjidicCharacter
+		if err := dec.DecodeElement(&c, &start); err != nil {
+			return nil, err
+		}
+
+		var on, kun, meanings []string
+		for _, r := range c.Readings {
+			switch r.Type {
+			case "ja_on":
+				on = appendReading(on, r.Value)
+			case "ja_kun":
+				kun = appendReading(kun, r.Value)
+			}
+		}
+		for _, m := range c.Meanings {
+			// Meanings without a language are English.
+			if m.Lang == "" {
+				meanings = append(meanings, m.Value)
+			}
+		}
+		if len(on)+len(kun) == 0 {
+			continue
+		}
+		lines[c.Literal] = strings.Join([]string{
+			strings.Join(on, " "),
+			strings.Join(kun, " "),
+			strings.Join(meanings, "; "),
+		}, "\t")
+	}
+}
+
+// appendReading adds a KANJIDIC2 reading without its affix marks ("-おお")
+// and okurigana ("おお.きい"), unless it's already there.
+func appendReading(readings []string, reading string) []string {
+	reading = strings.Trim(reading, "-")
+	reading, _, _ = strings.Cut(reading, ".")
+	if reading == "" || slices.Contains(readings, reading) {
+		return readings
+	}
+	return append(readings, reading)
+}
+
+// parseUnihan returns the first kHangul reading of each character in
+// Unihan_Readings.txt, keyed by the character.
+func parseUnihan(zipped []byte) (map[string]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(zipped), int64(len(zipped)))
+	if err != nil {
+		return nil, err
+	}
+	f, err := zr.Open("Unihan_Readings.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	readings := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// U+9F8D	kHangul	룡:0E
+		fields := strings.Split(sc.Text(), "\t")
+		if len(fields) != 3 || fields[1] != "kHangul" {
+			continue
+		}
+		code, err := strconv.ParseInt(strings.TrimPrefix(fields[0], "U+"), 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parsing code point %q: %w", fields[0], err)
+		}
+		first, _, _ := strings.Cut(fields[2], " ")
+		eum, _, _ := strings.Cut(first, ":")
+		readings[string(rune(code))] = eum
+	}
+	return readings, sc.Err()
+}
+
+// writeList writes header and then a tab-separated line per word or
+// character, in code point order.
+func writeList(path, header string, lines map[string]string) error {
+	var b strings.Builder
+	b.WriteString(header)
+	for _, c := range slices.Sorted(maps.Keys(lines)) {
+		b.WriteString(c + "\t" + lines[c] + "\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,148 @@
+package dictionary
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+)
+
+// maxMeanings caps how many CC-CEDICT glosses or KANJIDIC meanings a glyph
+// keeps; the first few are the common senses.
+const maxMeanings = 3
+
+// Glyph is what the dictionary knows about one Han character of a name: its
+// reading in Chinese, Korean and Japanese, and a few short English meanings.
+// Readings the bundled lists don't cover are left empty.
+type Glyph struct {
+	Char string
+	// Pinyin is the Mandarin reading with tone marks.
+	Pinyin string
+	// Eum is the Korean reading of the character as Hanja, in Hangul.
+	Eum string
+	// On and Kun are the Japanese readings: on'yomi in katakana, kun'yomi
+	// in hiragana.
+	On  []string
+	Kun []string
+	// Meanings come from CC-CEDICT, or KANJIDIC for characters it lacks.
+	Meanings []string
+}
+
+type kanjiEntry struct {
+	on, kun, meanings []string
+}
+
+// Glyphs breaks name down a character at a time, one Glyph per Han character
+// in it. Readings are looked up under the character's traditional form too,
+// since the Hanja and kanji lists are keyed on those.
+func (d *Dictionary) Glyphs(name string) []Glyph {
+	var glyphs []Glyph
+	for _, r := range name {
+		if !unicode.Is(unicode.Han, r) {
+			continue
+		}
+		c := string(r)
+		g := Glyph{Char: c, Meanings: d.meanings[c]}
+		if pinyin := transliteration.TransliterateStyle(c, transliteration.StyleTones); pinyin != c {
+			g.Pinyin = pinyin
+		}
+
+		forms := []string{c}
+		if t, ok := d.traditional[c]; ok {
+			forms = append(forms, t)
+		}
+		for _, form := range forms {
+			if g.Eum == "" {
+				g.Eum = d.hanja[form]
+			}
+			k, ok := d.kanji[form]
+			if !ok || len(g.On)+len(g.Kun) > 0 {
+				continue
+			}
+			g.On, g.Kun = k.on, k.kun
+			if len(g.Meanings) == 0 {
+				g.Meanings = k.meanings
+			}
+		}
+		glyphs = append(glyphs, g)
+	}
+	return glyphs
+}
+
+// addCharacter records the first few glosses of a single-character CC-CEDICT
+// entry, and its traditional form when that differs. As with words, the first entry
+// for a character wins.
+func (d *Dictionary) addCharacter(traditional, simplified string, glosses []string) {
+	if traditional != simplified {
+		if _, ok := d.traditional[simplified]; !ok {
+			d.traditional[simplified] = traditional
+		}
+	}
+
+	meanings := glosses[:min(len(glosses), maxMeanings)]
+	for _, c := range []string{simplified, traditional} {
+		if _, ok := d.meanings[c]; !ok {
+			d.meanings[c] = meanings
+		}
+	}
+}
+
+// LoadReadings adds the Hanja and kanji readings Glyphs reports, from lists
+// in the format gen.go writes from Unihan and KANJIDIC2:
+//
+//	character<TAB>Korean reading
+//	character<TAB>on readings<TAB>kun readings<TAB>meaning; meaning
+func (d *Dictionary) LoadReadings(hanja, kanji io.Reader) error {
+	if err := d.loadHanja(hanja); err != nil {
+		return fmt.Errorf("loading Hanja list: %w", err)
+	}
+	if err := d.loadKanji(kanji); err != nil {
+		return fmt.Errorf("loading KANJIDIC2: %w", err)
+	}
+	return nil
+}
+
+func (d *Dictionary) loadHanja(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		char, eum, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		d.hanja[char] = strings.TrimSpace(eum)
+	}
+	return sc.Err()
+}
+
+func (d *Dictionary) loadKanji(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		var meanings []string
+		for _, m := range strings.Split(fields[3], ";") {
+			if m = strings.TrimSpace(m); m != "" && len(meanings) < maxMeanings {
+				meanings = append(meanings, m)
+			}
+		}
+		d.kanji[fields[0]] = kanjiEntry{
+			on:       strings.Fields(fields[1]),
+			kun:      strings.Fields(fields[2]),
+			meanings: meanings,
+		}
+	}
+	return sc.Err()
+}
@@ -1,6 +1,6 @@
 // envsetup provides a lightweight .env configuration wizard.
 // It runs automatically on first bot startup when no .env file exists,
-// collecting Discord, Riot, and LLM credentials.
+// collecting Discord, Riot, and (optionally) LLM credentials.
 package envsetup
 
 import (
@@ -137,16 +137,21 @@ func (m model) handleEnter() (tea.Model, tea.Cmd) {
 
 	case stepLLMProvider:
 		choice := strings.TrimSpace(strings.ToLower(m.input))
-		if choice != "1" && choice != "2" && choice != "anthropic" && choice != "google" {
-			m.err = fmt.Errorf("Please enter 1 for Anthropic or 2 for Google")
-			return m, nil
-		}
-		if choice == "1" || choice == "anthropic" {
+		switch choice {
+		case "1", "anthropic":
 			m.llmProvider = "anthropic"
-		} else {
+			m.step = stepLLMKey
+		case "2", "google":
 			m.llmProvider = "google"
+			m.step = stepLLMKey
+		case "3", "skip", "none":
+			// No key to collect; the bot translates with its offline dictionary.
+			m.llmProvider = "none"
+			m.step = stepWebsiteShare
+		default:
+			m.err = fmt.Errorf("Please enter 1 for Anthropic, 2 for Google, or 3 to skip")
+			return m, nil
 		}
-		m.step = stepLLMKey
 		m.input = ""
 
 	case stepLLMKey:
@@ -192,23 +197,23 @@ func (m model) handleEnter() (tea.Model, tea.Cmd) {
 }
 
 func (m model) writeEnvFile() error {
-	var llmModel string
-	var llmKeyName string
-	if m.llmProvider == "anthropic" {
-		llmModel = "claude-sonnet-4-20250514"
-		llmKeyName = "ANTHROPIC_API_KEY"
-	} else {
-		llmModel = "gemini-2.0-flash"
-		llmKeyName = "GOOGLE_API_KEY"
-	}
-
 	lines := []string{
 		"DATABASE_URL=./leagueofren.db",
 		"DISCORD_TOKEN=" + sanitizeValue(m.discordToken),
 		"RIOT_API_KEY=" + sanitizeValue(m.riotAPIKey),
 		"LLM_PROVIDER=" + sanitizeValue(m.llmProvider),
-		"LLM_MODEL=" + llmModel,
-		llmKeyName + "=" + sanitizeValue(m.llmAPIKey),
+	}
+	switch m.llmProvider {
+	case "anthropic":
+		lines = append(lines,
+			"LLM_MODEL=claude-sonnet-4-20250514",
+			"ANTHROPIC_API_KEY="+sanitizeValue(m.llmAPIKey),
+		)
+	case "google":
+		lines = append(lines,
+			"LLM_MODEL=gemini-2.0-flash",
+			"GOOGLE_API_KEY="+sanitizeValue(m.llmAPIKey),
+		)
 	}
 	if m.shareWebsite {
 		lines = append(lines, "WEBSITE_URL=https://leagueofren.com")
@@ -238,7 +243,7 @@ func (m model) View() string {
 		s.WriteString("You'll need:\n\n")
 		s.WriteString("  - A Discord bot token\n")
 		s.WriteString("  - A Riot Games API key\n")
-		s.WriteString("  - An LLM API key (Anthropic or Google)\n")
+		s.WriteString("  - An LLM API key (Anthropic or Google), optional\n")
 		s.WriteString("\n")
 		s.WriteString(dimStyle.Render("Press Enter to continue, Ctrl+C to exit"))
 
@@ -283,8 +288,11 @@ func (m model) View() string {
 		s.WriteString("Which LLM provider would you like to use?\n\n")
 		s.WriteString("  1. Anthropic (Claude)\n")
 		s.WriteString("  2. Google (Gemini)\n")
+		s.WriteString("  3. Skip for now\n")
 		s.WriteString("\n")
-		s.WriteString(labelStyle.Render("Enter 1 or 2:"))
+		s.WriteString(dimStyle.Render("Without an LLM, names get a rough word-by-word dictionary translation."))
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Enter 1, 2 or 3:"))
 		s.WriteString("\n")
 		s.WriteString("> " + inputStyle.Render(m.input))
 		if m.err != nil {
@@ -334,8 +342,12 @@ func (m model) View() string {
 		s.WriteString("  Database:     " + successStyle.Render("./leagueofren.db") + "\n")
 		s.WriteString("  Discord:      " + successStyle.Render(maskToken(m.discordToken)) + "\n")
 		s.WriteString("  Riot API:     " + successStyle.Render(maskToken(m.riotAPIKey)) + "\n")
-		s.WriteString("  LLM Provider: " + successStyle.Render(m.llmProvider) + "\n")
-		s.WriteString("  LLM API Key:  " + successStyle.Render(maskToken(m.llmAPIKey)) + "\n")
+		if m.llmProvider == "none" {
+			s.WriteString("  LLM Provider: " + successStyle.Render("none (offline dictionary)") + "\n")
+		} else {
+			s.WriteString("  LLM Provider: " + successStyle.Render(m.llmProvider) + "\n")
+			s.WriteString("  LLM API Key:  " + successStyle.Render(maskToken(m.llmAPIKey)) + "\n")
+		}
 		shareText := "No"
 		if m.shareWebsite {
 			shareText = "Yes (leagueofren.com)"
@@ -0,0 +1,150 @@
+package evalharness
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/jusunglee/leagueofren/internal/translation"
+)
+
+// Call is the outcome of one completion request.
+type Call struct {
+	Text         string        `json:"text"`
+	Err          string        `json:"error,omitempty"`
+	Latency      time.Duration `json:"latency_ns"`
+	InputTokens  int64         `json:"input_tokens"`
+	OutputTokens int64         `json:"output_tokens"`
+}
+
+// Caller sends one prompt to a model. It exists so recorded calls can be
+// replayed with their original latency and token counts.
+type Caller interface {
+	Call(ctx context.Context, system, prompt string) Call
+}
+
+type liveCaller struct {
+	client llm.Client
+}
+
+// Live calls client and times the request, taking token counts from the
+// usage the provider reports.
+func Live(client llm.Client) Caller {
+	return &liveCaller{client: client}
+}
+
+func (c *liveCaller) Call(ctx context.Context, system, prompt string) Call {
+	start := time.Now()
+	resp, err := c.client.Complete(ctx, system, prompt, llm.WithJSONSchema(translation.ResponseSchema))
+	call := Call{
+		Text:         resp.Text,
+		Latency:      time.Since(start),
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}
+	if err != nil {
+		call.Err = err.Error()
+	}
+	return call
+}
+
+// Cassette stores calls per target keyed by a hash of the prompt, so a run can
+// be recorded once against real providers and then replayed offline.
+type Cassette struct {
+	mu      sync.Mutex
+	Targets map[string]map[string]Call `json:"targets"`
+}
+
+func NewCassette() *Cassette {
+	return &Cassette{Targets: make(map[string]map[string]Call)}
+}
+
+// LoadCassette reads a cassette written by Save.
+func LoadCassette(r io.Reader) (*Cassette, error) {
+	c := NewCassette()
+	if err := json.NewDecoder(r).Decode(c); err != nil {
+		return nil, fmt.Errorf("decoding cassette: %w", err)
+	}
+	return c, nil
+}
+
+// Save writes the cassette as JSON.
+func (c *Cassette) Save(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// TargetNames returns the recorded targets in sorted order.
+func (c *Cassette) TargetNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Sorted(maps.Keys(c.Targets))
+}
+
+// Record returns a Caller that forwards to next and stores every call under target.
+func (c *Cassette) Record(target string, next Caller) Caller {
+	return &recordingCaller{cassette: c, target: target, next: next}
+}
+
+// Replay returns a Caller that answers from the calls recorded for target.
+// A prompt that was never recorded fails the call rather than reaching a model.
+func (c *Cassette) Replay(target string) Caller {
+	return &replayingCaller{cassette: c, target: target}
+}
+
+func (c *Cassette) put(target, key string, call Call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Targets[target] == nil {
+		c.Targets[target] = make(map[string]Call)
+	}
+	c.Targets[target][key] = call
+}
+
+func (c *Cassette) get(target, key string) (Call, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	call, ok := c.Targets[target][key]
+	return call, ok
+}
+
+type recordingCaller struct {
+	cassette *Cassette
+	target   string
+	next     Caller
+}
+
+func (r *recordingCaller) Call(ctx context.Context, system, prompt string) Call {
+	call := r.next.Call(ctx, system, prompt)
+	r.cassette.put(r.target, promptKey(system, prompt), call)
+	return call
+}
+
+type replayingCaller struct {
+	cassette *Cassette
+	target   string
+}
+
+func (r *replayingCaller) Call(_ context.Context, system, prompt string) Call {
+	call, ok := r.cassette.get(r.target, promptKey(system, prompt))
+	if !ok {
+		return Call{Err: "no recording for this prompt"}
+	}
+	return call
+}
+
+func promptKey(system, prompt string) string {
+	sum := sha256.Sum256([]byte(system + "\x00" + prompt))
+	return hex.EncodeToString(sum[:])
+}
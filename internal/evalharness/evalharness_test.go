@@ -0,0 +1,160 @@
+package evalharness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGolden(t *testing.T) {
+	cases, err := LoadGolden(strings.NewReader(`# comment
+name,reference,language
+大魔王,Great Demon King,chinese
+小鱼人#NA1,Little Fish Man|Fizz,chinese
+앨리스#KR31,,korean
+大魔王,duplicate,chinese
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []Case{
+		{Name: "大魔王", References: []string{"Great Demon King"}, Language: "chinese"},
+		{Name: "小鱼人", References: []string{"Little Fish Man", "Fizz"}, Language: "chinese"},
+		{Name: "앨리스", Language: "korean"},
+	}, cases)
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		got       string
+		refs      []string
+		wantExact bool
+		minSim    float64
+	}{
+		{"Great Demon King", []string{"Great Demon King"}, true, 1},
+		{"the great demon-king", []string{"Great Demon King"}, true, 1},
+		{"Faker (The GOAT)", []string{"Faker"}, true, 1},
+		{"Fizz", []string{"Little Fish Man", "Fizz"}, true, 1},
+		{"Great Demon Lord", []string{"Great Demon King"}, false, 0.7},
+		{"Banana", []string{"Great Demon King"}, false, 0},
+	}
+	for _, tt := range tests {
+		exact, sim := Match(tt.got, tt.refs)
+		assert.Equal(t, tt.wantExact, exact, tt.got)
+		assert.GreaterOrEqual(t, sim, tt.minSim, tt.got)
+	}
+}
+
+// scriptedLLM answers from a fixed name → translation map and returns broken
+// JSON for any batch containing "broken". Every call reports 100 input tokens
+// and 10 output tokens.
+type scriptedLLM struct {
+	answers map[string]string
+	calls   int
+}
+
+func (s *scriptedLLM) Complete(_ context.Context, _, prompt string, _ ...llm.Option) (llm.Response, error) {
+	s.calls++
+	var out []translation.Translation
+	for _, line := range strings.Split(prompt, "\n") {
+		name, ok := strings.CutPrefix(line, "- ")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "broken":
+			return llm.Response{Text: "not json", Usage: scriptedUsage}, nil
+		case "down":
+			return llm.Response{}, errors.New("provider down")
+		}
+		if ans, ok := s.answers[name]; ok {
+			out = append(out, translation.Translation{Original: name, Translated: ans})
+		}
+	}
+	b, err := json.Marshal(out)
+	return llm.Response{Text: string(b), Usage: scriptedUsage}, err
+}
+
+var scriptedUsage = llm.Usage{InputTokens: 100, OutputTokens: 10}
+
+func TestRunRecordAndReplay(t *testing.T) {
+	cases := []Case{
+		{Name: "大魔王", References: []string{"Great Demon King"}},
+		{Name: "小鱼人", References: []string{"Little Fish Man"}},
+		{Name: "broken", References: []string{"x"}},
+		{Name: "앨리스"},
+	}
+	client := &scriptedLLM{answers: map[string]string{"大魔王": "Great Demon King", "小鱼人": "Small Fish Person", "앨리스": "Alice"}}
+	variants := []Variant{{Name: "production", System: "sys"}}
+	ctx := context.Background()
+
+	cassette := NewCassette()
+	target := Target{Name: "anthropic/claude-haiku-4-5-20251001", Model: "claude-haiku-4-5-20251001"}
+	target.Caller = cassette.Record(target.Name, Live(client))
+	recorded := Run(ctx, cases, []Target{target}, variants, 2)
+	require.Len(t, recorded, 1)
+
+	s := recorded[0]
+	assert.Equal(t, 2, s.Calls)
+	assert.Equal(t, 1, s.ValidJSON, "the batch with \"broken\" fails to parse")
+	assert.Equal(t, 0.5, s.JSONValidRate())
+	assert.Equal(t, 3, s.Scored)
+	assert.Equal(t, 2, s.Answered)
+	assert.Equal(t, 1, s.Exact)
+	require.NotNil(t, s.CostUSD)
+	assert.Equal(t, int64(200), s.InputTokens)
+	assert.Equal(t, int64(20), s.OutputTokens)
+
+	var buf bytes.Buffer
+	require.NoError(t, cassette.Save(&buf))
+	loaded, err := LoadCassette(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []string{target.Name}, loaded.TargetNames())
+
+	target.Caller = loaded.Replay(target.Name)
+	replayed := Run(ctx, cases, []Target{target}, variants, 2)
+	assert.Equal(t, recorded, replayed)
+	assert.Equal(t, 2, client.calls, "replay must not reach the model")
+
+	// A prompt that was never recorded fails instead of calling out
+	changed := Run(ctx, cases, []Target{target}, []Variant{{Name: "new", System: "other"}}, 2)
+	assert.Equal(t, 2, changed[0].CallErrors)
+}
+
+func TestRunCountsCallErrors(t *testing.T) {
+	target := Target{Name: "google/unknown", Model: "unknown", Caller: Live(&scriptedLLM{})}
+	s := Run(context.Background(), []Case{{Name: "down", References: []string{"x"}}}, []Target{target}, []Variant{{Name: "v"}}, 10)[0]
+
+	assert.Equal(t, 1, s.CallErrors)
+	assert.Equal(t, "provider down", s.Results[0].Error)
+	assert.Nil(t, s.CostUSD, "no price for unknown models")
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	cost := 0.0123
+	r := Report{
+		GeneratedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Mode:        "replay",
+		Cases:       1,
+		BatchSize:   10,
+		Summaries: []Summary{{
+			Target: "anthropic/m", Variant: "production", Cases: 1, Scored: 1, Answered: 1, Calls: 1, ValidJSON: 1,
+			CostUSD: &cost,
+			Results: []CaseResult{{Name: "大魔王", References: []string{"Great Demon King"}, Got: "Big | Devil", Similarity: 0.2}},
+		}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteMarkdown(&buf))
+
+	md := buf.String()
+	assert.Contains(t, md, "| anthropic/m | production | 0% | 0% |")
+	assert.Contains(t, md, "$0.0123")
+	assert.Contains(t, md, `| 大魔王 | Great Demon King | Big \| Devil | 0.20 |`)
+}
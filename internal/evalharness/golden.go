@@ -0,0 +1,85 @@
+// Package evalharness scores translation prompts and models against a golden
+// set of summoner names, so a prompt or model change can be compared with the
+// current one before it reaches production.
+package evalharness
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Case is one golden-set name. References lists every accepted translation;
+// a case without references is still sent to the model and counts towards
+// JSON validity, latency and cost, but not towards match rates.
+type Case struct {
+	Name       string   `json:"name"`
+	References []string `json:"references,omitempty"`
+	Language   string   `json:"language,omitempty"`
+}
+
+// LoadGolden reads a golden set CSV with a header row of
+// name,reference,language. Alternative references are separated by "|", and
+// a #tag on the name is dropped because the bot only translates game names.
+func LoadGolden(r io.Reader) ([]Case, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.TrimSpace(strings.ToLower(h))] = i
+	}
+	nameCol, ok := cols["name"]
+	if !ok {
+		return nil, errors.New("golden set is missing a name column")
+	}
+
+	field := func(record []string, col string) string {
+		i, ok := cols[col]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var cases []Case
+	seen := make(map[string]bool)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading golden set: %w", err)
+		}
+		if nameCol >= len(record) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(strings.TrimSpace(record[nameCol]), "#")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		c := Case{Name: name, Language: field(record, "language")}
+		for _, ref := range strings.Split(field(record, "reference"), "|") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				c.References = append(c.References, ref)
+			}
+		}
+		cases = append(cases, c)
+	}
+
+	if len(cases) == 0 {
+		return nil, errors.New("golden set is empty")
+	}
+	return cases, nil
+}
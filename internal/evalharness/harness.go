@@ -0,0 +1,177 @@
+package evalharness
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/samber/lo"
+)
+
+// Target is a model under evaluation.
+type Target struct {
+	// Name identifies the target in reports and cassettes, e.g. "anthropic/claude-haiku-4-5".
+	Name   string
+	Model  string
+	Caller Caller
+}
+
+// Variant is a system prompt under evaluation.
+type Variant struct {
+	Name   string
+	System string
+}
+
+// CaseResult is how one target and variant handled one golden case.
+type CaseResult struct {
+	Name        string   `json:"name"`
+	References  []string `json:"references,omitempty"`
+	Got         string   `json:"got,omitempty"`
+	Explanation string   `json:"explanation,omitempty"`
+	Exact       bool     `json:"exact"`
+	Similarity  float64  `json:"similarity"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// Summary aggregates a target and variant's results.
+type Summary struct {
+	Target  string `json:"target"`
+	Variant string `json:"variant"`
+
+	Cases      int `json:"cases"`
+	Scored     int `json:"scored"`
+	Answered   int `json:"answered"`
+	Exact      int `json:"exact"`
+	Fuzzy      int `json:"fuzzy"`
+	Calls      int `json:"calls"`
+	ValidJSON  int `json:"valid_json"`
+	CallErrors int `json:"call_errors"`
+
+	MeanSimilarity float64       `json:"mean_similarity"`
+	LatencyP50     time.Duration `json:"latency_p50_ns"`
+	LatencyP95     time.Duration `json:"latency_p95_ns"`
+	InputTokens    int64         `json:"input_tokens"`
+	OutputTokens   int64         `json:"output_tokens"`
+	// CostUSD is nil when the model has no entry in Prices.
+	CostUSD *float64 `json:"cost_usd,omitempty"`
+
+	Results []CaseResult `json:"results"`
+}
+
+// ExactRate is the share of scored cases that matched a reference exactly.
+func (s Summary) ExactRate() float64 { return ratio(s.Exact, s.Scored) }
+
+// FuzzyRate is the share of scored cases at or above FuzzyThreshold.
+func (s Summary) FuzzyRate() float64 { return ratio(s.Fuzzy, s.Scored) }
+
+// JSONValidRate is the share of successful calls whose response parsed.
+func (s Summary) JSONValidRate() float64 { return ratio(s.ValidJSON, s.Calls-s.CallErrors) }
+
+// Run sends cases to every target under every variant in batches of batchSize
+// and scores the results. Targets and variants are evaluated one at a time so
+// latencies aren't skewed by concurrent requests.
+func Run(ctx context.Context, cases []Case, targets []Target, variants []Variant, batchSize int) []Summary {
+	var summaries []Summary
+	for _, target := range targets {
+		for _, variant := range variants {
+			summaries = append(summaries, runOne(ctx, cases, target, variant, batchSize))
+		}
+	}
+	return summaries
+}
+
+func runOne(ctx context.Context, cases []Case, target Target, variant Variant, batchSize int) Summary {
+	s := Summary{Target: target.Name, Variant: variant.Name, Cases: len(cases)}
+	var latencies []time.Duration
+	var similarity float64
+
+	for _, batch := range lo.Chunk(cases, max(batchSize, 1)) {
+		names := lo.Map(batch, func(c Case, _ int) string { return c.Name })
+		call := target.Caller.Call(ctx, variant.System, translation.UserPrompt(names))
+
+		s.Calls++
+		latencies = append(latencies, call.Latency)
+		s.InputTokens += call.InputTokens
+		s.OutputTokens += call.OutputTokens
+
+		var translations []translation.Translation
+		var batchErr string
+		switch {
+		case call.Err != "":
+			s.CallErrors++
+			batchErr = call.Err
+		case json.Unmarshal([]byte(llm.StripMarkdownCodeBlocks(call.Text)), &translations) != nil:
+			batchErr = "invalid JSON response"
+		default:
+			s.ValidJSON++
+		}
+		byName := lo.KeyBy(translations, func(t translation.Translation) string { return t.Original })
+
+		for _, c := range batch {
+			r := CaseResult{Name: c.Name, References: c.References, Error: batchErr}
+			if tr, ok := byName[c.Name]; ok {
+				s.Answered++
+				r.Got = tr.Translated
+				r.Explanation = tr.Explanation
+			} else if r.Error == "" {
+				r.Error = "missing from response"
+			}
+			if len(c.References) > 0 {
+				s.Scored++
+				r.Exact, r.Similarity = Match(r.Got, c.References)
+				if r.Got == "" {
+					r.Similarity = 0
+				}
+				similarity += r.Similarity
+				if r.Exact {
+					s.Exact++
+				}
+				if r.Similarity >= FuzzyThreshold {
+					s.Fuzzy++
+				}
+			}
+			s.Results = append(s.Results, r)
+		}
+	}
+
+	if s.Scored > 0 {
+		s.MeanSimilarity = similarity / float64(s.Scored)
+	}
+	s.LatencyP50 = percentile(latencies, 0.50)
+	s.LatencyP95 = percentile(latencies, 0.95)
+	usage := llm.Usage{InputTokens: s.InputTokens, OutputTokens: s.OutputTokens}
+	if micro, ok := llm.CostMicroUSD(target.Model, usage); ok {
+		cost := float64(micro) / 1e6
+		s.CostUSD = &cost
+	}
+	return s
+}
+
+// VariantName returns a report-friendly name for a prompt file path.
+func VariantName(path string) string {
+	name := path[strings.LastIndexAny(path, `/\`)+1:]
+	if i := strings.LastIndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func percentile(ds []time.Duration, p float64) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(ds)
+	slices.Sort(sorted)
+	return sorted[int(p*float64(len(sorted)-1)+0.5)]
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
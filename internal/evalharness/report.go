@@ -0,0 +1,81 @@
+package evalharness
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Report is the output of one harness run.
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Mode        string    `json:"mode"`
+	Cases       int       `json:"cases"`
+	BatchSize   int       `json:"batch_size"`
+	Summaries   []Summary `json:"summaries"`
+}
+
+// WriteJSON writes the full report, including per-case results.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes a comparison table followed by the cases where the
+// targets disagreed with the references.
+func (r Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Translation eval report\n\n")
+	fmt.Fprintf(&b, "Generated %s (%s mode), %d golden names, batches of %d. ",
+		r.GeneratedAt.Format(time.RFC3339), r.Mode, r.Cases, r.BatchSize)
+	fmt.Fprintf(&b, "Fuzzy match means similarity ≥ %.2f.\n\n", FuzzyThreshold)
+
+	b.WriteString("| Target | Variant | Exact | Fuzzy | Mean sim. | Answered | Valid JSON | p50 | p95 | Tokens in/out | Cost |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|---|---|---|\n")
+	for _, s := range r.Summaries {
+		cost := "n/a"
+		if s.CostUSD != nil {
+			cost = fmt.Sprintf("$%.4f", *s.CostUSD)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %.0f%% | %.0f%% | %.2f | %d/%d | %.0f%% | %s | %s | %d/%d | %s |\n",
+			s.Target, s.Variant,
+			100*s.ExactRate(), 100*s.FuzzyRate(), s.MeanSimilarity,
+			s.Answered, s.Cases, 100*s.JSONValidRate(),
+			s.LatencyP50.Round(time.Millisecond), s.LatencyP95.Round(time.Millisecond),
+			s.InputTokens, s.OutputTokens, cost)
+	}
+
+	for _, s := range r.Summaries {
+		var misses []CaseResult
+		for _, c := range s.Results {
+			if len(c.References) > 0 && !c.Exact {
+				misses = append(misses, c)
+			}
+		}
+		if len(misses) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s / %s misses\n\n", s.Target, s.Variant)
+		b.WriteString("| Name | Reference | Got | Similarity |\n|---|---|---|---|\n")
+		for _, c := range misses {
+			got := c.Got
+			if got == "" {
+				got = "_" + c.Error + "_"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %.2f |\n",
+				escapeCell(c.Name), escapeCell(strings.Join(c.References, " / ")), escapeCell(got), c.Similarity)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,77 @@
+package evalharness
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyThreshold is the minimum similarity for a translation to count as a
+// fuzzy match.
+const FuzzyThreshold = 0.8
+
+// Match scores got against a case's references, returning whether it matches
+// one exactly after normalization and its best similarity in [0, 1].
+func Match(got string, references []string) (exact bool, similarity float64) {
+	g := normalize(got)
+	for _, ref := range references {
+		r := normalize(ref)
+		if g == r {
+			return true, 1
+		}
+		similarity = max(similarity, Similarity(g, r))
+	}
+	return false, similarity
+}
+
+// Similarity is one minus the normalized Levenshtein distance between a and b.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// normalize lowercases s, drops punctuation and any trailing "(explanation)",
+// and collapses whitespace, so "The Great Demon-King" and "great demon king"
+// compare equal.
+func normalize(s string) string {
+	if i := strings.Index(s, " ("); i > 0 && strings.HasSuffix(s, ")") {
+		s = s[:i]
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	words := strings.Fields(b.String())
+	if len(words) > 0 && (words[0] == "the" || words[0] == "a" || words[0] == "an") {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -3,6 +3,7 @@ package google
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/jusunglee/leagueofren/internal/llm"
 	"google.golang.org/genai"
@@ -19,6 +20,47 @@ const (
 
 var DefaultModel Model = ModelGemma3_27B
 
+// capabilities are the request features a model supports.
+type capabilities struct {
+	systemInstruction bool
+	jsonMode          bool
+}
+
+// capabilities reports what m supports. Gemini models take native system
+// instructions and constrained JSON output; Gemma and anything unrecognized get
+// the lowest common denominator.
+func (m Model) capabilities() capabilities {
+	if strings.HasPrefix(string(m), "gemini-") {
+		return capabilities{systemInstruction: true, jsonMode: true}
+	}
+	return capabilities{}
+}
+
+// BlockedError is returned when Google refuses to answer a prompt, or stops
+// answering it, for safety or policy reasons.
+type BlockedError struct {
+	// Reason is the block or finish reason Google reported, e.g. "SAFETY".
+	Reason string
+	// Message is Google's explanation, when it gave one.
+	Message string
+}
+
+func (e *BlockedError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("google blocked the response (%s): %s", e.Reason, e.Message)
+	}
+	return fmt.Sprintf("google blocked the response (%s)", e.Reason)
+}
+
+// blockingFinishReasons are the finish reasons that mean the candidate was cut
+// off by a safety or policy filter rather than finishing on its own.
+var blockingFinishReasons = map[genai.FinishReason]bool{
+	genai.FinishReasonSafety:            true,
+	genai.FinishReasonBlocklist:         true,
+	genai.FinishReasonProhibitedContent: true,
+	genai.FinishReasonSPII:              true,
+}
+
 type Client struct {
 	client *genai.Client
 	model  Model
@@ -42,23 +84,65 @@ func NewClient(ctx context.Context, apiKey string, model Model) (*Client, error)
 	}, nil
 }
 
-func (c *Client) Complete(ctx context.Context, system, prompt string) (string, error) {
-	// Gemma doesn't support system instructions natively, prepend to user message
-	fullPrompt := system + "\n\n" + prompt
-
-	result, err := c.client.Models.GenerateContent(ctx, string(c.model),
-		[]*genai.Content{{Parts: []*genai.Part{{Text: fullPrompt}}}},
-		nil,
-	)
+func (c *Client) Complete(ctx context.Context, system, prompt string, opts ...llm.Option) (llm.Response, error) {
+	contents, config := buildRequest(c.model.capabilities(), system, prompt, llm.NewOptions(opts...))
+	result, err := c.client.Models.GenerateContent(ctx, string(c.model), contents, config)
 	if err != nil {
-		return "", fmt.Errorf("google API call failed: %w", err)
+		return llm.Response{}, fmt.Errorf("google API call failed: %w", err)
 	}
+	return parseResponse(result)
+}
 
-	if len(result.Candidates) == 0 || len(result.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from google")
+// buildRequest shapes a completion for what the model supports.
+func buildRequest(caps capabilities, system, prompt string, opts llm.Options) ([]*genai.Content, *genai.GenerateContentConfig) {
+	if !caps.systemInstruction {
+		// Gemma doesn't support system instructions natively, prepend to user message
+		return []*genai.Content{genai.NewContentFromText(system+"\n\n"+prompt, genai.RoleUser)}, nil
 	}
 
-	text := result.Candidates[0].Content.Parts[0].Text
+	config := &genai.GenerateContentConfig{
+		SystemInstruction: genai.NewContentFromText(system, genai.RoleUser),
+	}
+	if caps.jsonMode && opts.JSONSchema != nil {
+		config.ResponseMIMEType = "application/json"
+		config.ResponseJsonSchema = opts.JSONSchema
+	}
+	return []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)}, config
+}
+
+func parseResponse(result *genai.GenerateContentResponse) (llm.Response, error) {
+	var usage llm.Usage
+	if m := result.UsageMetadata; m != nil {
+		// Thinking tokens are billed as output.
+		usage = llm.Usage{
+			InputTokens:  int64(m.PromptTokenCount),
+			OutputTokens: int64(m.CandidatesTokenCount) + int64(m.ThoughtsTokenCount),
+		}
+	}
+
+	if f := result.PromptFeedback; f != nil && f.BlockReason != "" {
+		return llm.Response{Usage: usage}, &BlockedError{Reason: string(f.BlockReason), Message: f.BlockReasonMessage}
+	}
+	if len(result.Candidates) == 0 {
+		return llm.Response{Usage: usage}, fmt.Errorf("empty response from google")
+	}
+
+	candidate := result.Candidates[0]
+	if blockingFinishReasons[candidate.FinishReason] {
+		return llm.Response{Usage: usage}, &BlockedError{Reason: string(candidate.FinishReason), Message: candidate.FinishMessage}
+	}
+
+	var text strings.Builder
+	if candidate.Content != nil {
+		for _, part := range candidate.Content.Parts {
+			if !part.Thought {
+				text.WriteString(part.Text)
+			}
+		}
+	}
+	if text.Len() == 0 {
+		return llm.Response{Usage: usage}, fmt.Errorf("empty response from google")
+	}
 
-	return llm.StripMarkdownCodeBlocks(text), nil
+	return llm.Response{Text: llm.StripMarkdownCodeBlocks(text.String()), Usage: usage}, nil
 }
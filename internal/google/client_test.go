@@ -0,0 +1,108 @@
+package google
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+var schema = json.RawMessage(`{"type": "array"}`)
+
+func TestCapabilities(t *testing.T) {
+	assert.Equal(t, capabilities{}, ModelGemma3_27B.capabilities())
+	assert.Equal(t, capabilities{systemInstruction: true, jsonMode: true}, ModelGemini2Flash.capabilities())
+	assert.Equal(t, capabilities{systemInstruction: true, jsonMode: true}, ModelGemini2_5Pro.capabilities())
+}
+
+func TestBuildRequestGemmaPrependsSystemPrompt(t *testing.T) {
+	contents, config := buildRequest(ModelGemma3_27B.capabilities(), "sys", "prompt", llm.Options{JSONSchema: schema})
+
+	assert.Nil(t, config)
+	require.Len(t, contents, 1)
+	require.Len(t, contents[0].Parts, 1)
+	assert.Equal(t, "sys\n\nprompt", contents[0].Parts[0].Text)
+}
+
+func TestBuildRequestGeminiUsesSystemInstructionAndJSON(t *testing.T) {
+	contents, config := buildRequest(ModelGemini2Flash.capabilities(), "sys", "prompt", llm.Options{JSONSchema: schema})
+
+	require.Len(t, contents, 1)
+	assert.Equal(t, "prompt", contents[0].Parts[0].Text)
+	require.NotNil(t, config)
+	assert.Equal(t, "sys", config.SystemInstruction.Parts[0].Text)
+	assert.Equal(t, "application/json", config.ResponseMIMEType)
+	assert.Equal(t, schema, config.ResponseJsonSchema)
+}
+
+func TestBuildRequestGeminiWithoutSchemaIsPlainText(t *testing.T) {
+	_, config := buildRequest(ModelGemini2Flash.capabilities(), "sys", "prompt", llm.Options{})
+
+	require.NotNil(t, config)
+	assert.Empty(t, config.ResponseMIMEType)
+	assert.Nil(t, config.ResponseJsonSchema)
+}
+
+func TestParseResponseConcatenatesTextParts(t *testing.T) {
+	resp, err := parseResponse(&genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			FinishReason: genai.FinishReasonStop,
+			Content: &genai.Content{Parts: []*genai.Part{
+				{Text: "thinking about it", Thought: true},
+				{Text: `[{"original":"玩家",`},
+				{Text: `"translated":"Player"}]`},
+			}},
+		}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+			PromptTokenCount: 10, CandidatesTokenCount: 5, ThoughtsTokenCount: 3,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `[{"original":"玩家","translated":"Player"}]`, resp.Text)
+	assert.Equal(t, int64(10), resp.Usage.InputTokens)
+	assert.Equal(t, int64(8), resp.Usage.OutputTokens)
+}
+
+func TestParseResponseBlocked(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *genai.GenerateContentResponse
+		reason string
+	}{
+		{
+			name: "prompt blocked",
+			result: &genai.GenerateContentResponse{
+				PromptFeedback: &genai.GenerateContentResponsePromptFeedback{BlockReason: genai.BlockedReasonSafety},
+			},
+			reason: "SAFETY",
+		},
+		{
+			name: "candidate stopped by filter",
+			result: &genai.GenerateContentResponse{
+				Candidates: []*genai.Candidate{{
+					FinishReason:  genai.FinishReasonProhibitedContent,
+					FinishMessage: "nope",
+					Content:       &genai.Content{Parts: []*genai.Part{{Text: "[{"}}},
+				}},
+			},
+			reason: "PROHIBITED_CONTENT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseResponse(tt.result)
+			var blocked *BlockedError
+			require.True(t, errors.As(err, &blocked))
+			assert.Equal(t, tt.reason, blocked.Reason)
+		})
+	}
+}
+
+func TestParseResponseEmpty(t *testing.T) {
+	_, err := parseResponse(&genai.GenerateContentResponse{Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonStop}}})
+	assert.ErrorContains(t, err, "empty response")
+}
@@ -0,0 +1,8 @@
+package jobs
+
+// PromoteAlternativesArgs are the arguments for the periodic
+// promote_alternatives job, which replaces translations with the alternatives
+// that have led them for long enough.
+type PromoteAlternativesArgs struct{}
+
+func (PromoteAlternativesArgs) Kind() string { return "promote_alternatives" }
@@ -0,0 +1,8 @@
+package jobs
+
+// PruneStreamEventsArgs are the arguments for the periodic
+// prune_stream_events job, which deletes stream events too old for clients to
+// resume from.
+type PruneStreamEventsArgs struct{}
+
+func (PruneStreamEventsArgs) Kind() string { return "prune_stream_events" }
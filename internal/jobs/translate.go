@@ -9,6 +9,15 @@ import (
 type TranslateUsernameArgs struct {
 	Username string `json:"username"`
 	Region   string `json:"region"`
+	// Retranslate sends the name to the LLM even when a translation of it is
+	// cached, for moderators re-queueing a bad translation.
+	Retranslate bool `json:"retranslate,omitempty"`
+}
+
+// TranslateUsernameOutput is what a completed translate_username job records
+// as its output.
+type TranslateUsernameOutput struct {
+	TranslationID int64 `json:"translation_id"`
 }
 
 func (TranslateUsernameArgs) Kind() string { return "translate_username" }
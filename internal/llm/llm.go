@@ -2,11 +2,53 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 )
 
 type Client interface {
-	Complete(ctx context.Context, system, prompt string) (string, error)
+	Complete(ctx context.Context, system, prompt string, opts ...Option) (Response, error)
+}
+
+// Options are the per-request settings a caller can ask for. Providers apply
+// the ones their model supports and ignore the rest.
+type Options struct {
+	// JSONSchema constrains the response to JSON matching this JSON Schema.
+	// It's passed through verbatim, so properties keep their order.
+	JSONSchema json.RawMessage
+}
+
+type Option func(*Options)
+
+// WithJSONSchema asks for a response that is JSON matching schema.
+func WithJSONSchema(schema json.RawMessage) Option {
+	return func(o *Options) { o.JSONSchema = schema }
+}
+
+// NewOptions applies opts in order.
+func NewOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Response is a completion's text along with the tokens it was billed for.
+type Response struct {
+	Text  string
+	Usage Usage
+}
+
+// Usage is the token count a provider reported for one completion.
+type Usage struct {
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// Add returns the sum of two usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{InputTokens: u.InputTokens + o.InputTokens, OutputTokens: u.OutputTokens + o.OutputTokens}
 }
 
 // StripMarkdownCodeBlocks removes ```...``` wrappers from LLM responses
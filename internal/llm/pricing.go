@@ -0,0 +1,31 @@
+package llm
+
+// Price is a model's list price in USD per million tokens.
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// Prices are list prices for the models this project has used. Models that
+// aren't listed are treated as free by CostMicroUSD, so keep this up to date when
+// switching models or budgets won't be enforced.
+var Prices = map[string]Price{
+	"claude-sonnet-4-5-20250929": {Input: 3, Output: 15},
+	"claude-sonnet-4-20250514":   {Input: 3, Output: 15},
+	"claude-haiku-4-5-20251001":  {Input: 1, Output: 5},
+	"claude-opus-4-5-20251101":   {Input: 5, Output: 25},
+	"gemini-2.0-flash":           {Input: 0.10, Output: 0.40},
+	"gemini-2.5-pro":             {Input: 1.25, Output: 10},
+	"gemma-3-27b-it":             {Input: 0, Output: 0},
+}
+
+// CostMicroUSD returns what usage cost on model in millionths of a dollar, and
+// whether the model has a known price.
+func CostMicroUSD(model string, usage Usage) (int64, bool) {
+	price, ok := Prices[model]
+	if !ok {
+		return 0, false
+	}
+	// Prices are per million tokens, so tokens × price is already in micro-dollars.
+	return int64(float64(usage.InputTokens)*price.Input + float64(usage.OutputTokens)*price.Output + 0.5), true
+}
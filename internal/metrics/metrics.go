@@ -38,6 +38,16 @@ var (
 		Help:    "LLM translation call duration in seconds",
 		Buckets: []float64{0.5, 1, 2, 5, 10, 20, 30},
 	})
+
+	StreamConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "lor_stream_connections",
+		Help: "Open /api/v1/stream connections",
+	})
+
+	StreamEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lor_stream_events_total",
+		Help: "Stream events received from PostgreSQL by type",
+	}, []string{"type"})
 )
 
 // Worker metrics.
@@ -96,6 +106,20 @@ var (
 	}, []string{"command", "result"})
 )
 
+// LLM usage metrics, shared by the bot and the website's translate worker.
+// server is the Discord server a call was made for, empty when there wasn't one.
+var (
+	LLMTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lor_llm_tokens_total",
+		Help: "LLM tokens billed by provider, model, server, and direction (input or output)",
+	}, []string{"provider", "model", "server", "direction"})
+
+	LLMCostUSDTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lor_llm_cost_usd_total",
+		Help: "Estimated LLM spend in USD by provider, model, and server",
+	}, []string{"provider", "model", "server"})
+)
+
 // Database pool metrics (gauges updated periodically).
 var (
 	DBPoolTotalConns = promauto.NewGauge(prometheus.GaugeOpts{
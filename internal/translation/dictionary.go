@@ -0,0 +1,78 @@
+package translation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/jusunglee/leagueofren/internal/dictionary"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+)
+
+// QualityDictionary marks a translation produced from the offline dictionary
+// instead of an LLM: a word-by-word gloss plus romanization.
+const QualityDictionary = "dictionary"
+
+// WithDictionary sets the offline dictionary used when the translator has no
+// LLM client, and as a last resort when an LLM call fails.
+func WithDictionary(d *dictionary.Dictionary) Option {
+	return func(t *Translator) {
+		t.dict = d
+	}
+}
+
+// translateOffline glosses usernames from the dictionary. The results are
+// never cached, so the next request still goes to the LLM once one works.
+// Names with no Chinese or Korean characters are skipped.
+func (t *Translator) translateOffline(ctx context.Context, usernames []string) []Translation {
+	results := make([]Translation, 0, len(usernames))
+	for _, name := range usernames {
+		tr, ok := glossName(t.dict, name)
+		if !ok {
+			t.log.DebugContext(ctx, "no dictionary translation", "name", name)
+			continue
+		}
+		results = append(results, tr)
+	}
+	return results
+}
+
+// glossName renders name as its segment glosses joined by " · ", falling back
+// to romanization for segments the dictionary doesn't know, e.g.
+// "大魔王" → "great demon king (damowang)". A name with no known words is
+// just romanized.
+func glossName(d *dictionary.Dictionary, name string) (Translation, bool) {
+	romanized := transliteration.Transliterate(name)
+	if romanized == "" {
+		return Translation{}, false
+	}
+
+	var parts []string
+	glossed := false
+	for _, seg := range d.Segment(name) {
+		switch {
+		case seg.Gloss != "":
+			parts = append(parts, seg.Gloss)
+			glossed = true
+		case isCJK(seg.Text):
+			parts = append(parts, transliteration.Transliterate(seg.Text))
+		default:
+			parts = append(parts, seg.Text)
+		}
+	}
+
+	translated := romanized
+	if glossed {
+		translated = composeTranslation(Translation{Translated: strings.Join(parts, " · "), Explanation: romanized})
+	}
+	return Translation{Original: name, Translated: translated, Quality: QualityDictionary}, true
+}
+
+func isCJK(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hangul, r) {
+			return true
+		}
+	}
+	return false
+}
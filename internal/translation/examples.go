@@ -0,0 +1,220 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/samber/lo"
+)
+
+const (
+	// examplePoolSize is how many top-voted translations are kept per language.
+	examplePoolSize = 20
+	// examplesPerLanguage is how many of them go into a single prompt.
+	examplesPerLanguage = 3
+	// exampleMinUpvotes keeps barely-voted translations out of the prompt.
+	exampleMinUpvotes = 5
+	// examplePoolTTL is how long a language's pool is reused before it is
+	// reloaded, so newly popular translations start showing up.
+	examplePoolTTL = time.Hour
+)
+
+// BuiltinExamplesVersion is recorded on translations whose prompt only used the
+// built-in examples, because no community translations qualified.
+const BuiltinExamplesVersion = "builtin"
+
+// builtinExamples seed the prompt until the website has enough votes.
+var builtinExamples = []Translation{
+	{Original: "不知火舞", Translated: "Mai Shiranui", Explanation: "Fighting game character from Fatal Fury/KOF"},
+	{Original: "人人人", Translated: "Person Person Person"},
+}
+
+// examplePool serves few-shot examples drawn from highly upvoted public
+// translations. Each language's pool is cached in memory for examplePoolTTL and
+// successive prompts rotate through it, so no handful of names dominates.
+type examplePool struct {
+	repo db.Repository
+	log  *slog.Logger
+
+	mu     sync.Mutex
+	pools  map[string]languagePool
+	cursor atomic.Uint64
+}
+
+type languagePool struct {
+	examples []example
+	loadedAt time.Time
+}
+
+type example struct {
+	id int64
+	tr Translation
+}
+
+func newExamplePool(repo db.Repository, log *slog.Logger) *examplePool {
+	return &examplePool{
+		repo:  repo,
+		log:   log,
+		pools: make(map[string]languagePool),
+	}
+}
+
+// pick returns the few-shot examples for a batch of usernames along with the
+// version identifying them: the sorted public translation IDs, or
+// BuiltinExamplesVersion when no community examples were available.
+func (p *examplePool) pick(ctx context.Context, usernames []string) ([]Translation, string) {
+	languages := lo.Uniq(lo.Map(usernames, func(name string, _ int) string {
+		return detectLanguage(name)
+	}))
+	slices.Sort(languages)
+
+	offset := int(p.cursor.Add(1) - 1)
+
+	var picked []example
+	for _, lang := range languages {
+		pool := p.load(ctx, lang)
+		n := min(examplesPerLanguage, len(pool))
+		for i := range n {
+			picked = append(picked, pool[(offset*n+i)%len(pool)])
+		}
+	}
+
+	if len(picked) == 0 {
+		return builtinExamples, BuiltinExamplesVersion
+	}
+
+	ids := lo.Map(picked, func(e example, _ int) int64 { return e.id })
+	slices.Sort(ids)
+	version := strings.Join(lo.Map(ids, func(id int64, _ int) string {
+		return strconv.FormatInt(id, 10)
+	}), ",")
+
+	return lo.Map(picked, func(e example, _ int) Translation { return e.tr }), version
+}
+
+// load returns the cached pool for language, reloading it once it has expired.
+// If the reload fails the previous pool keeps being served. The lock isn't held
+// during the query, so a slow database doesn't hold up picks from fresh pools;
+// concurrent reloads of the same pool just replace each other.
+func (p *examplePool) load(ctx context.Context, language string) []example {
+	p.mu.Lock()
+	cached, ok := p.pools[language]
+	p.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < examplePoolTTL {
+		return cached.examples
+	}
+
+	rows, err := p.repo.ListTopVotedPublicTranslations(ctx, db.ListTopVotedPublicTranslationsParams{
+		Language:   language,
+		MinUpvotes: exampleMinUpvotes,
+		Limit:      examplePoolSize,
+	})
+	if err != nil {
+		p.log.WarnContext(ctx, "failed to load few-shot examples", "language", language, "error", err)
+		return cached.examples
+	}
+
+	examples := lo.Map(rows, func(row db.PublicTranslation, _ int) example {
+		return example{id: row.ID, tr: exampleFromPublic(row)}
+	})
+	p.mu.Lock()
+	p.pools[language] = languagePool{examples: examples, loadedAt: time.Now()}
+	p.mu.Unlock()
+	return examples
+}
+
+// exampleFromPublic converts a public translation into the shape the model is
+// asked to produce. Translations submitted through the website are stored
+// composed as "translated (explanation)", so they are split back apart.
+func exampleFromPublic(pt db.PublicTranslation) Translation {
+	gameName, _, _ := strings.Cut(pt.Username, "#")
+	tr := Translation{Original: gameName, Translated: pt.Translation}
+	if pt.Explanation.Valid {
+		tr.Explanation = pt.Explanation.String
+		return tr
+	}
+	if before, after, ok := strings.Cut(pt.Translation, " ("); ok && strings.HasSuffix(after, ")") {
+		tr.Translated = before
+		tr.Explanation = strings.TrimSuffix(after, ")")
+	}
+	return tr
+}
+
+// detectLanguage matches the language values stored on public translations.
+func detectLanguage(name string) string {
+	if script := transliteration.DetectScript(name); script != transliteration.ScriptLatin {
+		return script
+	}
+	return transliteration.ScriptChinese
+}
+
+// systemPromptTemplate is filled with the target language, the source
+// languages and the scripts' prompt hints from the transliteration registry,
+// and the example response.
+const systemPromptTemplate = `You are translating League of Legends summoner names from %[2]s to %[1]s.
+
+For each name, provide:
+1. The %[1]s translation or transliteration
+2. Brief context if it's a cultural reference, pun, pro player name, or gaming term
+%[3]s
+Respond ONLY with a JSON array, no other text. Example:
+%[4]s`
+
+// exampleLanguageNote follows the examples when translating into a language
+// other than English, since the examples themselves are English.
+const exampleLanguageNote = `
+
+The example only shows the response format. Write "translated" and "explanation" in %s.`
+
+// SystemPrompt returns the production system prompt with the built-in
+// examples, as used before any community translations qualify.
+func SystemPrompt() (string, error) {
+	return buildSystemPrompt(builtinExamples, DefaultLanguage)
+}
+
+// buildSystemPrompt renders the system prompt for the target language with
+// examples as the sample response. Only other targets get the note about the
+// examples' language, so that adding target languages left the English prompt
+// unchanged and didn't make English cache entries stale. Any other change to
+// the template needs a PromptVersion bump.
+func buildSystemPrompt(examples []Translation, target string) (string, error) {
+	b, err := json.MarshalIndent(examples, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding few-shot examples: %w", err)
+	}
+	name, ok := LanguageNames[target]
+	if !ok {
+		return "", fmt.Errorf("unsupported target language %q", target)
+	}
+	scripts := transliteration.Scripts()
+	labels := lo.Map(scripts, func(s transliteration.Script, _ int) string { return s.Label })
+	var hints strings.Builder
+	for _, s := range scripts {
+		if s.PromptHint != "" {
+			hints.WriteString("\n" + s.PromptHint + "\n")
+		}
+	}
+	prompt := fmt.Sprintf(systemPromptTemplate, name, listLanguages(labels), hints.String(), b)
+	if target != LanguageEnglish {
+		prompt += fmt.Sprintf(exampleLanguageNote, name)
+	}
+	return prompt, nil
+}
+
+// listLanguages joins labels as prose: "Korean, Chinese and Japanese".
+func listLanguages(labels []string) string {
+	if len(labels) < 2 {
+		return strings.Join(labels, "")
+	}
+	return strings.Join(labels[:len(labels)-1], ", ") + " and " + labels[len(labels)-1]
+}
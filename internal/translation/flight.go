@@ -0,0 +1,71 @@
+package translation
+
+import "sync"
+
+// flightGroup is a per-name singleflight. singleflight.Group collapses calls
+// with identical keys, but translations are requested in batches that only
+// partially overlap, so each name is tracked on its own: the first caller to
+// claim a name translates it, and everyone else asking for that name while the
+// call is running waits for its result instead of paying for another LLM call.
+// Names are tracked per target language, since each is a separate translation.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[flightKey]*flight
+}
+
+type flightKey struct {
+	target string
+	name   string
+}
+
+type flight struct {
+	done chan struct{}
+	tr   Translation
+	ok   bool
+	err  error
+}
+
+// claim returns the names the caller now owns and must pass to finish, and
+// the in-flight calls it should wait on for the rest.
+func (g *flightGroup) claim(target string, names []string) (owned []string, waiting map[string]*flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.calls == nil {
+		g.calls = make(map[flightKey]*flight)
+	}
+	waiting = make(map[string]*flight)
+	for _, name := range names {
+		key := flightKey{target: target, name: name}
+		if f, ok := g.calls[key]; ok {
+			waiting[name] = f
+			continue
+		}
+		g.calls[key] = &flight{done: make(chan struct{})}
+		owned = append(owned, name)
+	}
+	return owned, waiting
+}
+
+// finish publishes the outcome for owned names and wakes their waiters. A name
+// missing from results (the model skipped it) is reported as not found.
+func (g *flightGroup) finish(target string, owned []string, results []Translation, err error) {
+	byName := make(map[string]Translation, len(results))
+	for _, tr := range results {
+		byName[tr.Original] = tr
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, name := range owned {
+		key := flightKey{target: target, name: name}
+		f, ok := g.calls[key]
+		if !ok {
+			continue
+		}
+		delete(g.calls, key)
+		f.tr, f.ok = byName[name]
+		f.err = err
+		close(f.done)
+	}
+}
@@ -0,0 +1,35 @@
+package translation
+
+// Target languages, as ISO 639-1 codes. They're stored with each cached
+// translation, so a name is translated once per language it's requested in.
+const (
+	LanguageEnglish    = "en"
+	LanguageSpanish    = "es"
+	LanguagePortuguese = "pt"
+)
+
+// DefaultLanguage is used when a caller or Discord server hasn't picked one.
+const DefaultLanguage = LanguageEnglish
+
+// LanguageNames are the supported target languages, named the way the prompt
+// refers to them.
+var LanguageNames = map[string]string{
+	LanguageEnglish:    "English",
+	LanguageSpanish:    "Spanish",
+	LanguagePortuguese: "Portuguese",
+}
+
+// IsSupportedLanguage reports whether code is a target language the translator
+// can be asked for.
+func IsSupportedLanguage(code string) bool {
+	_, ok := LanguageNames[code]
+	return ok
+}
+
+// targetOrDefault maps an unset target language to DefaultLanguage.
+func targetOrDefault(target string) string {
+	if target == "" {
+		return DefaultLanguage
+	}
+	return target
+}
@@ -0,0 +1,526 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo implements the translation cache methods of db.Repository in memory.
+// The cache is keyed by cacheKey, like the (username, target_language) unique
+// constraint.
+type fakeRepo struct {
+	db.Repository
+
+	mu      sync.Mutex
+	cache   map[string]db.Translation
+	public  []db.PublicTranslation
+	loadsBy map[string]int
+	usage   []db.CreateLLMUsageParams
+}
+
+func newFakeRepo(entries ...db.Translation) *fakeRepo {
+	r := &fakeRepo{cache: make(map[string]db.Translation)}
+	for _, e := range entries {
+		if e.SeenCount == 0 {
+			e.SeenCount = 1
+		}
+		if e.TargetLanguage == "" {
+			e.TargetLanguage = DefaultLanguage
+		}
+		r.cache[cacheKey(e.Username, e.TargetLanguage)] = e
+	}
+	return r
+}
+
+func cacheKey(username, target string) string {
+	return target + "/" + username
+}
+
+func (r *fakeRepo) GetTranslations(_ context.Context, arg db.GetTranslationsParams) ([]db.Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []db.Translation
+	for _, u := range arg.Usernames {
+		if t, ok := r.cache[cacheKey(u, arg.TargetLanguage)]; ok {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) CreateTranslation(_ context.Context, arg db.CreateTranslationParams) (db.Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := cacheKey(arg.Username, arg.TargetLanguage)
+	t := r.cache[key]
+	if t.SeenCount == 0 {
+		t.SeenCount = 1
+	}
+	t.Username = arg.Username
+	t.Translation = arg.Translation
+	t.Provider = arg.Provider
+	t.Model = arg.Model
+	t.PromptVersion = arg.PromptVersion
+	t.ExamplesVersion = arg.ExamplesVersion
+	t.TargetLanguage = arg.TargetLanguage
+	r.cache[key] = t
+	return t, nil
+}
+
+func (r *fakeRepo) IncrementTranslationSeenCount(_ context.Context, arg db.IncrementTranslationSeenCountParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range arg.Usernames {
+		key := cacheKey(u, arg.TargetLanguage)
+		if t, ok := r.cache[key]; ok {
+			t.SeenCount++
+			r.cache[key] = t
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) ListTopVotedPublicTranslations(_ context.Context, arg db.ListTopVotedPublicTranslationsParams) ([]db.PublicTranslation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loadsBy == nil {
+		r.loadsBy = make(map[string]int)
+	}
+	r.loadsBy[arg.Language]++
+	var out []db.PublicTranslation
+	for _, pt := range r.public {
+		if pt.Language == arg.Language && pt.Upvotes >= arg.MinUpvotes && len(out) < int(arg.Limit) {
+			out = append(out, pt)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) CreateLLMUsage(_ context.Context, arg db.CreateLLMUsageParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usage = append(r.usage, arg)
+	return nil
+}
+
+func (r *fakeRepo) get(username string) db.Translation {
+	return r.getIn(username, DefaultLanguage)
+}
+
+func (r *fakeRepo) getIn(username, target string) db.Translation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cache[cacheKey(username, target)]
+}
+
+// fakeLLM answers every name with "<name>-<model>".
+type fakeLLM struct {
+	model string
+
+	mu      sync.Mutex
+	calls   [][]string
+	systems []string
+	options []llm.Options
+}
+
+func (f *fakeLLM) Complete(_ context.Context, system, prompt string, opts ...llm.Option) (llm.Response, error) {
+	var names []string
+	for _, line := range strings.Split(prompt, "\n") {
+		if name, ok := strings.CutPrefix(line, "- "); ok {
+			names = append(names, name)
+		}
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, names)
+	f.systems = append(f.systems, system)
+	f.options = append(f.options, llm.NewOptions(opts...))
+	f.mu.Unlock()
+
+	out := make([]Translation, len(names))
+	for i, n := range names {
+		out[i] = Translation{Original: n, Translated: n + "-" + f.model}
+	}
+	b, err := json.Marshal(out)
+	return llm.Response{Text: string(b), Usage: llm.Usage{InputTokens: 100, OutputTokens: 10 * int64(len(names))}}, err
+}
+
+func (f *fakeLLM) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func staleEntry(username string) db.Translation {
+	return db.Translation{Username: username, Translation: username + "-old", Provider: "test", Model: "old", PromptVersion: PromptVersion}
+}
+
+func TestTranslateUsernamesCachesWithVersion(t *testing.T) {
+	repo := newFakeRepo()
+	client := &fakeLLM{model: "new"}
+	tr := NewTranslator(client, repo, "test", "new")
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"玩家"}, DefaultLanguage)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "玩家-new", got[0].Translated)
+	assert.JSONEq(t, string(ResponseSchema), string(client.options[0].JSONSchema), "asks for the translations schema")
+
+	cached := repo.get("玩家")
+	assert.Equal(t, "new", cached.Model)
+	assert.Equal(t, PromptVersion, cached.PromptVersion)
+	assert.Equal(t, int64(1), cached.SeenCount)
+
+	// A second lookup is a cache hit and bumps the seen count
+	_, err = tr.TranslateUsernames(context.Background(), []string{"玩家"}, DefaultLanguage)
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.callCount())
+	assert.Equal(t, int64(2), repo.get("玩家").SeenCount)
+}
+
+func TestStalePolicyServe(t *testing.T) {
+	repo := newFakeRepo(staleEntry("玩家"))
+	client := &fakeLLM{model: "new"}
+	tr := NewTranslator(client, repo, "test", "new", WithStalePolicy(StalePolicyServe))
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"玩家"}, DefaultLanguage)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "玩家-old", got[0].Translated)
+	assert.Equal(t, 0, client.callCount())
+}
+
+func TestStalePolicyBypass(t *testing.T) {
+	repo := newFakeRepo(staleEntry("玩家"))
+	client := &fakeLLM{model: "new"}
+	tr := NewTranslator(client, repo, "test", "new", WithStalePolicy(StalePolicyBypass))
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"玩家"}, DefaultLanguage)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "玩家-new", got[0].Translated)
+	assert.Equal(t, "new", repo.get("玩家").Model)
+	assert.Equal(t, int64(2), repo.get("玩家").SeenCount)
+}
+
+func TestStalePolicyRefresh(t *testing.T) {
+	repo := newFakeRepo(staleEntry("玩家"))
+	client := &fakeLLM{model: "new"}
+	tr := NewTranslator(client, repo, "test", "new", WithStalePolicy(StalePolicyRefresh))
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"玩家"}, DefaultLanguage)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "玩家-old", got[0].Translated, "stale entry is served immediately")
+
+	assert.Eventually(t, func() bool {
+		return repo.get("玩家").Model == "new"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "玩家-new", repo.get("玩家").Translation)
+}
+
+func TestIsStale(t *testing.T) {
+	tr := NewTranslator(&fakeLLM{}, newFakeRepo(), "anthropic", "m1")
+
+	assert.False(t, tr.IsStale(db.Translation{Provider: "anthropic", Model: "m1", PromptVersion: PromptVersion}))
+	assert.True(t, tr.IsStale(db.Translation{Provider: "anthropic", Model: "m0", PromptVersion: PromptVersion}))
+	assert.True(t, tr.IsStale(db.Translation{Provider: "google", Model: "m1", PromptVersion: PromptVersion}))
+	assert.True(t, tr.IsStale(db.Translation{Provider: "anthropic", Model: "m1", PromptVersion: ""}))
+}
+
+func TestTranslateUsernamesBatches(t *testing.T) {
+	repo := newFakeRepo()
+	client := &fakeLLM{model: "m"}
+	tr := NewTranslator(client, repo, "test", "m", WithMaxBatchSize(2))
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"一", "二", "三", "二", "四", "五"}, DefaultLanguage)
+	require.NoError(t, err)
+	assert.Len(t, got, 5, "duplicates are translated once")
+	assert.Equal(t, 3, client.callCount())
+	for _, names := range client.calls {
+		assert.LessOrEqual(t, len(names), 2)
+	}
+}
+
+func TestTranslateUsernamesSharesCanonicalKey(t *testing.T) {
+	repo := newFakeRepo()
+	client := &fakeLLM{model: "m"}
+	tr := NewTranslator(client, repo, "test", "m")
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"페이커", "페\u200b이커", "ﾀﾞｲｽｹ"}, DefaultLanguage)
+	require.NoError(t, err)
+	require.Len(t, got, 3, "each name gets its own translation")
+	require.Len(t, client.calls, 1)
+	assert.Equal(t, []string{"페이커", "ダイスケ"}, client.calls[0], "the LLM sees each canonical key once")
+
+	byName := lo.SliceToMap(got, func(tr Translation) (string, string) { return tr.Original, tr.Translated })
+	assert.Equal(t, "페이커-m", byName["페이커"])
+	assert.Equal(t, "페이커-m", byName["페\u200b이커"])
+	assert.Equal(t, "ダイスケ-m", byName["ﾀﾞｲｽｹ"])
+	assert.Equal(t, "ダイスケ-m", repo.get("ダイスケ").Translation)
+}
+
+// blockingLLM holds every call until release is closed.
+type blockingLLM struct {
+	fakeLLM
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingLLM) Complete(ctx context.Context, system, prompt string, _ ...llm.Option) (llm.Response, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return b.fakeLLM.Complete(ctx, system, prompt)
+}
+
+func TestTranslateUsernamesCollapsesConcurrentRequests(t *testing.T) {
+	repo := newFakeRepo()
+	client := &blockingLLM{fakeLLM: fakeLLM{model: "m"}, started: make(chan struct{}, 4), release: make(chan struct{})}
+	tr := NewTranslator(client, repo, "test", "m")
+	ctx := context.Background()
+
+	first := make(chan []Translation)
+	go func() {
+		got, _ := tr.TranslateUsernames(ctx, []string{"玩家"}, DefaultLanguage)
+		first <- got
+	}()
+	<-client.started
+
+	// 玩家 is already in flight, so only 新人 should reach the LLM
+	second := make(chan []Translation)
+	go func() {
+		got, _ := tr.TranslateUsernames(ctx, []string{"玩家", "新人"}, DefaultLanguage)
+		second <- got
+	}()
+	<-client.started
+	close(client.release)
+
+	assert.Len(t, <-first, 1)
+	assert.Len(t, <-second, 2)
+	require.Equal(t, 2, client.callCount())
+	assert.ElementsMatch(t, [][]string{{"玩家"}, {"新人"}}, client.calls)
+}
+
+func publicEntry(id int64, username, translation, language string, upvotes int32) db.PublicTranslation {
+	return db.PublicTranslation{ID: id, Username: username, Translation: translation, Language: language, Upvotes: upvotes}
+}
+
+func TestTranslateUsesBuiltinExamplesWithoutVotes(t *testing.T) {
+	repo := newFakeRepo()
+	client := &fakeLLM{model: "m"}
+	tr := NewTranslator(client, repo, "test", "m")
+
+	_, err := tr.TranslateUsernames(context.Background(), []string{"玩家"}, DefaultLanguage)
+	require.NoError(t, err)
+
+	assert.Equal(t, BuiltinExamplesVersion, repo.get("玩家").ExamplesVersion)
+	assert.Contains(t, client.systems[0], "不知火舞")
+}
+
+func TestTranslateUsesTopVotedExamples(t *testing.T) {
+	repo := newFakeRepo()
+	repo.public = []db.PublicTranslation{
+		publicEntry(7, "페이커#KR1", "Faker (The GOAT of League of Legends)", "korean", 50),
+		publicEntry(3, "大魔王#NA1", "Great Demon King", "chinese", 40),
+		publicEntry(9, "小鱼人#NA1", "Little Fish Man", "chinese", 1),
+		publicEntry(12, "ゆきんこ#JP1", "Snow Child", "japanese", 30),
+	}
+	client := &fakeLLM{model: "m"}
+	tr := NewTranslator(client, repo, "test", "m")
+
+	_, err := tr.TranslateUsernames(context.Background(), []string{"玩家", "토르소"}, DefaultLanguage)
+	require.NoError(t, err)
+
+	assert.Equal(t, "3,7", repo.get("玩家").ExamplesVersion)
+	system := client.systems[0]
+	assert.Contains(t, system, `"original": "페이커"`)
+	assert.Contains(t, system, `"explanation": "The GOAT of League of Legends"`)
+	assert.Contains(t, system, "大魔王")
+	assert.NotContains(t, system, "小鱼人", "below the upvote threshold")
+	assert.NotContains(t, system, "不知火舞")
+	assert.NotContains(t, system, "ゆきんこ", "no Japanese names in the batch")
+
+	_, err = tr.TranslateUsernames(context.Background(), []string{"東京タワー"}, DefaultLanguage)
+	require.NoError(t, err)
+	assert.Equal(t, "12", repo.get("東京タワー").ExamplesVersion)
+}
+
+func TestExamplePoolRotatesAndCaches(t *testing.T) {
+	repo := newFakeRepo()
+	for i := range 5 {
+		repo.public = append(repo.public, publicEntry(int64(i+1), "玩家", "Player", "chinese", 10))
+	}
+	pool := newExamplePool(repo, slog.Default())
+	ctx := context.Background()
+
+	_, first := pool.pick(ctx, []string{"新人"})
+	_, second := pool.pick(ctx, []string{"新人"})
+
+	assert.Equal(t, "1,2,3", first)
+	assert.Equal(t, "1,4,5", second)
+	assert.Equal(t, 1, repo.loadsBy["chinese"], "pool should be loaded once and reused")
+}
+
+// failingLLM fails every call.
+type failingLLM struct{}
+
+func (failingLLM) Complete(context.Context, string, string, ...llm.Option) (llm.Response, error) {
+	return llm.Response{}, errors.New("provider unavailable")
+}
+
+// testDictionary returns a small dictionary rather than the bundled one, so
+// the glosses the tests expect don't change with the CC-CEDICT release.
+func testDictionary(t *testing.T) *dictionary.Dictionary {
+	t.Helper()
+	cedict := strings.NewReader(`大魔王 大魔王 [da4 mo2 wang2] /great demon king/
+暗黑 暗黑 [an4 hei1] /dark/
+破壞神 破坏神 [po4 huai4 shen2] /god of destruction/
+無 无 [wu2] /not to have/no/none/
+`)
+	korean := strings.NewReader("하늘\tsky\n바라기\tone who gazes at\n")
+	d, err := dictionary.New(cedict, korean)
+	require.NoError(t, err)
+	return d
+}
+
+func TestTranslateWithoutLLMUsesDictionary(t *testing.T) {
+	repo := newFakeRepo(staleEntry("大魔王"))
+	tr := NewTranslator(nil, repo, "none", "", WithDictionary(testDictionary(t)), WithStalePolicy(StalePolicyBypass))
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"大魔王", "하늘바라기", "Faker"}, DefaultLanguage)
+	require.NoError(t, err)
+
+	byName := lo.KeyBy(got, func(t Translation) string { return t.Original })
+	require.Len(t, got, 2, "latin names have nothing to gloss")
+	assert.Equal(t, "大魔王-old", byName["大魔王"].Translated, "cached LLM translation beats the dictionary")
+	assert.Equal(t, "sky · one who gazes at (haneulbaragi)", byName["하늘바라기"].Translated)
+	assert.Equal(t, QualityDictionary, byName["하늘바라기"].Quality)
+	assert.Empty(t, repo.get("하늘바라기").Username, "dictionary output is not cached")
+}
+
+func TestTranslateFallsBackToDictionaryWhenLLMFails(t *testing.T) {
+	repo := newFakeRepo()
+	tr := NewTranslator(failingLLM{}, repo, "test", "m", WithDictionary(testDictionary(t)))
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"暗黑破坏神", "无名"}, DefaultLanguage)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	byName := lo.KeyBy(got, func(t Translation) string { return t.Original })
+	// The romanization reads words from the bundled CC-CEDICT, which the
+	// test dictionary doesn't replace.
+	assert.Equal(t, "dark · god of destruction ("+transliteration.Transliterate("暗黑破坏神")+")", byName["暗黑破坏神"].Translated)
+	assert.Equal(t, "not to have · ming ("+transliteration.Transliterate("无名")+")", byName["无名"].Translated, "unknown characters are romanized")
+}
+
+func TestTranslateWithoutDictionaryReturnsLLMError(t *testing.T) {
+	tr := NewTranslator(failingLLM{}, newFakeRepo(), "test", "m")
+
+	_, err := tr.TranslateUsernames(context.Background(), []string{"玩家"}, DefaultLanguage)
+	assert.ErrorContains(t, err, "provider unavailable")
+}
+
+func TestTranslateRecordsUsagePerServer(t *testing.T) {
+	repo := newFakeRepo()
+	tr := NewTranslator(&fakeLLM{model: "claude-haiku-4-5-20251001"}, repo, "anthropic", "claude-haiku-4-5-20251001")
+
+	// One batch of two names: 100 input and 20 output tokens. 玩家 was asked for
+	// by two servers, 大魔王 by one.
+	ctx := WithRequesters(context.Background(), map[string][]string{
+		"玩家":  {"a", "b"},
+		"大魔王": {"a"},
+	})
+	_, err := tr.TranslateUsernames(ctx, []string{"玩家", "大魔王"}, DefaultLanguage)
+	require.NoError(t, err)
+
+	byServer := lo.KeyBy(repo.usage, func(u db.CreateLLMUsageParams) string { return u.ServerID })
+	require.Len(t, byServer, 2)
+	assert.Equal(t, int64(75), byServer["a"].InputTokens)
+	assert.Equal(t, int64(15), byServer["a"].OutputTokens)
+	assert.Equal(t, int64(25), byServer["b"].InputTokens)
+	assert.Equal(t, int64(5), byServer["b"].OutputTokens)
+	assert.Equal(t, "anthropic", byServer["a"].Provider)
+	// 75 input tokens at $1/M plus 15 output tokens at $5/M
+	assert.Equal(t, int64(150), byServer["a"].CostMicroUSD)
+}
+
+// blockedLLM refuses every call after billing its prompt, as a provider's
+// safety filter does.
+type blockedLLM struct{}
+
+func (blockedLLM) Complete(context.Context, string, string, ...llm.Option) (llm.Response, error) {
+	return llm.Response{Usage: llm.Usage{InputTokens: 100}}, errors.New("response blocked: SAFETY")
+}
+
+func TestTranslateRecordsUsageOfFailedCalls(t *testing.T) {
+	repo := newFakeRepo()
+	tr := NewTranslator(blockedLLM{}, repo, "google", "gemini-2.0-flash")
+
+	ctx := WithRequesters(context.Background(), map[string][]string{"玩家": {"a"}})
+	_, err := tr.TranslateUsernames(ctx, []string{"玩家"}, DefaultLanguage)
+	require.ErrorContains(t, err, "blocked")
+
+	require.Len(t, repo.usage, 1)
+	assert.Equal(t, "a", repo.usage[0].ServerID)
+	assert.Equal(t, int64(100), repo.usage[0].InputTokens)
+}
+
+func TestSplitUsageKeepsTotals(t *testing.T) {
+	usage := llm.Usage{InputTokens: 101, OutputTokens: 7}
+	shares := splitUsage([]string{"a", "b", "c"}, map[string][]string{"a": {"x", "y", "z"}}, usage)
+
+	var total llm.Usage
+	for _, s := range shares {
+		total = total.Add(s)
+	}
+	assert.Equal(t, usage, total)
+	assert.Contains(t, shares, "", "names without requesters are recorded against no server")
+}
+
+func TestCachedTranslationsSkipsLLM(t *testing.T) {
+	client := &fakeLLM{model: "m"}
+	tr := NewTranslator(client, newFakeRepo(db.Translation{Username: "玩家", Translation: "Player"}), "test", "m")
+
+	got, err := tr.CachedTranslations(context.Background(), []string{"玩家", "大魔王"}, DefaultLanguage)
+	require.NoError(t, err)
+	assert.Equal(t, []Translation{{Original: "玩家", Translated: "Player"}}, got)
+	assert.Zero(t, client.callCount())
+}
+
+func TestTranslateUsernamesCachesPerTargetLanguage(t *testing.T) {
+	repo := newFakeRepo(db.Translation{Username: "玩家", Translation: "Player", Provider: "test", Model: "new", PromptVersion: PromptVersion})
+	client := &fakeLLM{model: "new"}
+	tr := NewTranslator(client, repo, "test", "new")
+
+	got, err := tr.TranslateUsernames(context.Background(), []string{"玩家"}, LanguageSpanish)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "玩家-new", got[0].Translated, "the English entry isn't served for Spanish")
+	require.Equal(t, 1, client.callCount())
+	assert.Contains(t, client.systems[0], "Vietnamese to Spanish")
+
+	assert.Equal(t, LanguageSpanish, repo.getIn("玩家", LanguageSpanish).TargetLanguage)
+	assert.Equal(t, "Player", repo.get("玩家").Translation)
+
+	_, err = tr.TranslateUsernames(context.Background(), []string{"玩家"}, LanguageSpanish)
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.callCount())
+}
+
+func TestBuildSystemPromptRejectsUnknownLanguage(t *testing.T) {
+	_, err := buildSystemPrompt(builtinExamples, "xx")
+	assert.Error(t, err)
+}
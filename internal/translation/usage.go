@@ -0,0 +1,91 @@
+package translation
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/llm"
+	"github.com/jusunglee/leagueofren/internal/metrics"
+)
+
+type requestersKey struct{}
+
+// WithRequesters returns a context that attributes the LLM usage of names
+// translated under it to the Discord servers that asked for them, keyed by
+// username. Names with no requesters are recorded against no server.
+func WithRequesters(ctx context.Context, requesters map[string][]string) context.Context {
+	return context.WithValue(ctx, requestersKey{}, requesters)
+}
+
+func requestersFrom(ctx context.Context) map[string][]string {
+	requesters, _ := ctx.Value(requestersKey{}).(map[string][]string)
+	return requesters
+}
+
+// recordUsage splits one LLM call's usage across the servers that requested
+// its names and records each share in the usage table and metrics. Recording is
+// best effort: a failed write is logged rather than failing the translation.
+func (t *Translator) recordUsage(ctx context.Context, usernames []string, usage llm.Usage) {
+	for server, share := range splitUsage(usernames, requestersFrom(ctx), usage) {
+		// Unpriced models are recorded at zero cost; cmd/bot warns about them at startup.
+		cost, _ := llm.CostMicroUSD(t.model, share)
+
+		metrics.LLMTokensTotal.WithLabelValues(t.provider, t.model, server, "input").Add(float64(share.InputTokens))
+		metrics.LLMTokensTotal.WithLabelValues(t.provider, t.model, server, "output").Add(float64(share.OutputTokens))
+		metrics.LLMCostUSDTotal.WithLabelValues(t.provider, t.model, server).Add(float64(cost) / 1e6)
+
+		err := t.repo.CreateLLMUsage(ctx, db.CreateLLMUsageParams{
+			ServerID:     server,
+			Provider:     t.provider,
+			Model:        t.model,
+			InputTokens:  share.InputTokens,
+			OutputTokens: share.OutputTokens,
+			CostMicroUSD: cost,
+		})
+		if err != nil {
+			t.log.WarnContext(ctx, "failed to record LLM usage", "server_id", server, "error", err)
+		}
+	}
+}
+
+// splitUsage divides usage evenly across usernames, and each name's share
+// evenly across the servers that requested it. Shares are rounded to whole
+// tokens so that they always add back up to usage.
+func splitUsage(usernames []string, requesters map[string][]string, usage llm.Usage) map[string]llm.Usage {
+	weights := make(map[string]float64)
+	for _, name := range usernames {
+		servers := requesters[name]
+		if len(servers) == 0 {
+			servers = []string{""}
+		}
+		for _, server := range servers {
+			weights[server] += 1 / float64(len(usernames)*len(servers))
+		}
+	}
+	if len(weights) == 0 {
+		weights[""] = 1
+	}
+
+	servers := slices.Sorted(maps.Keys(weights))
+	shares := make(map[string]llm.Usage, len(servers))
+	var assigned llm.Usage
+	for i, server := range servers {
+		share := llm.Usage{
+			InputTokens:  int64(math.Round(float64(usage.InputTokens) * weights[server])),
+			OutputTokens: int64(math.Round(float64(usage.OutputTokens) * weights[server])),
+		}
+		// The last server absorbs rounding so nothing is lost or double-counted.
+		if i == len(servers)-1 {
+			share = llm.Usage{
+				InputTokens:  usage.InputTokens - assigned.InputTokens,
+				OutputTokens: usage.OutputTokens - assigned.OutputTokens,
+			}
+		}
+		assigned = assigned.Add(share)
+		shares[server] = share
+	}
+	return shares
+}
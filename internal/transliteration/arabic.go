@@ -0,0 +1,82 @@
+package transliteration
+
+import "strings"
+
+// A simple ASCII transliteration of Arabic script, close to what players type
+// in chat: emphatic consonants fold onto their plain counterparts and hamza
+// and ʿayn are written as an apostrophe. Persian and Urdu letters are
+// included.
+var (
+	arabicLetters = map[rune]string{
+		'ا': "a", 'أ': "a", 'إ': "i", 'آ': "aa", 'ٱ': "a", 'ء': "'", 'ؤ': "'", 'ئ': "'",
+		'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "h", 'خ': "kh",
+		'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s", 'ش': "sh",
+		'ص': "s", 'ض': "d", 'ط': "t", 'ظ': "z", 'ع': "'", 'غ': "gh",
+		'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m", 'ن': "n",
+		'ه': "h", 'ة': "a", 'و': "w", 'ي': "y", 'ى': "a",
+		'پ': "p", 'چ': "ch", 'ژ': "zh", 'گ': "g", 'ک': "k", 'ی': "y",
+		'ٹ': "t", 'ڈ': "d", 'ڑ': "r", 'ں': "n", 'ھ': "h", 'ے': "e",
+	}
+	arabicVowels = map[rune]string{
+		'ً': "an", // fathatan
+		'ٌ': "un", // dammatan
+		'ٍ': "in", // kasratan
+		'َ': "a",  // fatha
+		'ُ': "u",  // damma
+		'ِ': "i",  // kasra
+		'ْ': "",   // sukun
+	}
+)
+
+const (
+	shadda  = 'ّ'
+	tatweel = 'ـ'
+)
+
+// romanizeArabic transliterates text. Vowel marks are written when present,
+// but most names leave them out and come out as consonants only. و and ي are
+// read as the long vowels u and i after a consonant, and as w and y anywhere
+// else.
+func romanizeArabic(text string) string {
+	var b strings.Builder
+	var last string
+	afterConsonant := false
+	for _, r := range text {
+		switch {
+		case r == tatweel:
+			continue
+		case r == shadda:
+			b.WriteString(last)
+			continue
+		case r >= '٠' && r <= '٩':
+			b.WriteRune('0' + r - '٠')
+			afterConsonant = false
+			continue
+		case r >= '۰' && r <= '۹':
+			b.WriteRune('0' + r - '۰')
+			afterConsonant = false
+			continue
+		}
+		if v, ok := arabicVowels[r]; ok {
+			b.WriteString(v)
+			afterConsonant = v == ""
+			continue
+		}
+		latin, ok := arabicLetters[r]
+		if !ok {
+			b.WriteRune(r)
+			afterConsonant = false
+			continue
+		}
+		switch {
+		case r == 'و' && afterConsonant:
+			latin = "u"
+		case (r == 'ي' || r == 'ی') && afterConsonant:
+			latin = "i"
+		}
+		b.WriteString(latin)
+		last = latin
+		afterConsonant = !strings.ContainsAny(latin[len(latin)-1:], "aeiou")
+	}
+	return b.String()
+}
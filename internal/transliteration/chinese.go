@@ -1,32 +1,235 @@
 package transliteration
 
+//go:generate go run gen.go
+
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"io"
 	"strings"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/jusunglee/leagueofren/internal/cedict"
 	"github.com/mozillazg/go-pinyin"
 )
 
 var pinyinArgs pinyin.Args
 
+// bundledPhrases is phrases.tsv.gz, written by gen.go from go-pinyin's phrase
+// dictionary (phrase-pinyin-data).
+//
+//go:embed phrases.tsv.gz
+var bundledPhrases []byte
+
+// phrases returns the table of words romanizeChinese reads, built on first
+// use. Tests replace it with one built from a fixture.
+var phrases = sync.OnceValue(loadPhrases)
+
 func init() {
 	pinyinArgs = pinyin.NewArgs()
-	pinyinArgs.Style = pinyin.Normal // no tone marks
+	pinyinArgs.Style = pinyin.Tone3
+}
+
+// phraseTable maps a word, in simplified and traditional form, to its pinyin
+// syllables with tone numbers (chang2, cheng2). Neutral tone has none.
+type phraseTable struct {
+	words  map[string][]string
+	maxLen int
+}
+
+// add records the reading of word unless it already has one or isn't a
+// phrase.
+func (p *phraseTable) add(word string, syllables []string) {
+	if !isPhrase(word, len(syllables)) {
+		return
+	}
+	if _, ok := p.words[word]; !ok {
+		p.words[word] = syllables
+		p.maxLen = max(p.maxLen, utf8.RuneCountInString(word))
+	}
+}
+
+// loadPhrases builds the phrase table from the bundled phrase dictionary and
+// then the words of the bundled CC-CEDICT it doesn't have. Characters with
+// several readings (长, 行, 乐, 重, ...) are read per character by go-pinyin,
+// which picks the most common reading regardless of context; the phrases fix
+// those readings and double as the word list that output is segmented with.
+func loadPhrases() *phraseTable {
+	p := &phraseTable{words: make(map[string][]string)}
+
+	zr, err := gzip.NewReader(bytes.NewReader(bundledPhrases))
+	if err != nil {
+		panic("transliteration: opening bundled phrases: " + err.Error())
+	}
+	if err := p.readPhrases(zr); err != nil {
+		panic("transliteration: reading bundled phrases: " + err.Error())
+	}
+
+	r, err := cedict.Open()
+	if err != nil {
+		panic("transliteration: opening bundled CC-CEDICT: " + err.Error())
+	}
+	defer r.Close()
+	if err := p.readCEDICT(r); err != nil {
+		panic("transliteration: reading bundled CC-CEDICT: " + err.Error())
+	}
+	return p
+}
+
+// readPhrases reads lines of the form
+//
+//	word<TAB>chang2 cheng2
+func (p *phraseTable) readPhrases(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, reading, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		p.add(word, strings.Fields(reading))
+	}
+	return sc.Err()
+}
+
+// readCEDICT reads the multi-character words of a CC-CEDICT file.
+func (p *phraseTable) readCEDICT(r io.Reader) error {
+	return cedict.Parse(r, func(e cedict.Entry) {
+		// CC-CEDICT writes ü as u: and the neutral tone as 5; go-pinyin uses v
+		// and no number. Match go-pinyin so both sources read the same.
+		syllables := strings.Fields(strings.ToLower(strings.ReplaceAll(e.Pinyin, "u:", "v")))
+		for i, s := range syllables {
+			syllables[i] = strings.TrimSuffix(s, "5")
+		}
+		p.add(e.Simplified, syllables)
+		p.add(e.Traditional, syllables)
+	})
 }
 
-func romanizeChinese(text string) string {
+// isPhrase reports whether word is a run of Han characters, more than one,
+// read with one syllable each. Single characters are left to go-pinyin, and
+// entries with letters or erhua (哪兒 [na3 r5]) don't line up with their reading.
+func isPhrase(word string, syllables int) bool {
+	n := 0
+	for _, r := range word {
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+		n++
+	}
+	return n > 1 && n == syllables
+}
+
+// romanizeChinese writes the Han characters in text as pinyin (or Zhuyin),
+// a word at a time: characters that make up a known phrase are read together,
+// which also settles the reading of characters like 长 and 行 that change
+// with the word they're in, and words are separated by spaces. Anything that
+// isn't Han is written through unchanged.
+func romanizeChinese(text string, style Style) string {
 	var b strings.Builder
-	for _, r := range text {
-		if unicode.Is(unicode.Han, r) {
-			py := pinyin.SinglePinyin(r, pinyinArgs)
-			if len(py) > 0 {
-				b.WriteString(py[0])
-			} else {
-				b.WriteRune(r)
+	afterWord := false
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !unicode.Is(unicode.Han, runes[i]) {
+			if afterWord && !unicode.IsSpace(runes[i]) {
+				b.WriteByte(' ')
 			}
-		} else {
-			b.WriteRune(r)
+			afterWord = false
+			b.WriteRune(runes[i])
+			i++
+			continue
 		}
+
+		word, n := nextWord(runes[i:])
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), " ") {
+			b.WriteByte(' ')
+		}
+		b.WriteString(formatWord(word, style))
+		afterWord = true
+		i += n
 	}
 	return b.String()
 }
+
+// nextWord reads the word at the start of runes, preferring the longest
+// phrase. With no phrase to match, the first character is read on its own.
+// It returns the word's syllables and how many runes it used.
+func nextWord(runes []rune) ([]string, int) {
+	p := phrases()
+	for n := min(p.maxLen, len(runes)); n > 1; n-- {
+		if syllables, ok := p.words[string(runes[:n])]; ok {
+			return syllables, n
+		}
+	}
+	if py := pinyin.SinglePinyin(runes[0], pinyinArgs); len(py) > 0 {
+		return py[:1], 1
+	}
+	return []string{string(runes[0])}, 1
+}
+
+// formatWord writes a word's syllables in style. Pinyin syllables of a word
+// are run together; Zhuyin is written a syllable at a time.
+func formatWord(syllables []string, style Style) string {
+	out := make([]string, len(syllables))
+	for i, s := range syllables {
+		base, tone := splitTone(s)
+		switch style {
+		case StyleTones:
+			out[i] = markTone(base, tone)
+		case StyleZhuyin:
+			out[i] = toZhuyin(base, tone)
+		default:
+			out[i] = base
+		}
+	}
+	if style == StyleZhuyin {
+		return strings.Join(out, " ")
+	}
+	return strings.Join(out, "")
+}
+
+// splitTone separates a numbered syllable (zhang3) into its letters and tone.
+// Syllables without a number are neutral tone, 5.
+func splitTone(s string) (string, int) {
+	if n := len(s); n > 0 && s[n-1] >= '1' && s[n-1] <= '5' {
+		return s[:n-1], int(s[n-1] - '0')
+	}
+	return s, 5
+}
+
+var toneMarks = map[byte][4]string{
+	'a': {"ā", "á", "ǎ", "à"},
+	'e': {"ē", "é", "ě", "è"},
+	'i': {"ī", "í", "ǐ", "ì"},
+	'o': {"ō", "ó", "ǒ", "ò"},
+	'u': {"ū", "ú", "ǔ", "ù"},
+	'v': {"ǖ", "ǘ", "ǚ", "ǜ"},
+}
+
+// markTone writes a syllable with its tone mark. The mark goes on a or e if
+// there is one, on the o of ou, and otherwise on the last vowel. v is written
+// as ü.
+func markTone(base string, tone int) string {
+	at := -1
+	switch {
+	case strings.IndexByte(base, 'a') >= 0:
+		at = strings.IndexByte(base, 'a')
+	case strings.IndexByte(base, 'e') >= 0:
+		at = strings.IndexByte(base, 'e')
+	case strings.Contains(base, "ou"):
+		at = strings.Index(base, "ou")
+	default:
+		at = strings.LastIndexAny(base, "iouv")
+	}
+	if at >= 0 && tone >= 1 && tone <= 4 {
+		base = base[:at] + toneMarks[base[at]][tone-1] + base[at+1:]
+	}
+	return strings.ReplaceAll(base, "v", "ü")
+}
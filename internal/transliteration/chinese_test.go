@@ -0,0 +1,53 @@
+package transliteration
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain reads Chinese words from testdata rather than the bundled phrases,
+// so the romanizations the tests expect don't change with them.
+func TestMain(m *testing.M) {
+	f, err := os.Open("testdata/phrases.tsv")
+	if err != nil {
+		panic(err)
+	}
+	fixture := &phraseTable{words: make(map[string][]string)}
+	if err := fixture.readPhrases(f); err != nil {
+		panic(err)
+	}
+	f.Close()
+	phrases = func() *phraseTable { return fixture }
+
+	os.Exit(m.Run())
+}
+
+func TestBundledPhrases(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the bundled phrases are the development subset until make dictionaries")
+	}
+	bundled := loadPhrases()
+	if len(bundled.words) < 100_000 {
+		t.Fatalf("phrases.tsv.gz has %d phrases, the development subset; run make dictionaries", len(bundled.words))
+	}
+
+	fixture := phrases
+	phrases = func() *phraseTable { return bundled }
+	defer func() { phrases = fixture }()
+
+	// None of these are in the fixture, and go-pinyin reads 重, 乐 and 便 on
+	// their own as zhong, le and bian.
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"重复", "chongfu"},
+		{"乐器", "yueqi"},
+		{"便宜", "pianyi"},
+	}
+	for _, tt := range tests {
+		if got := Transliterate(tt.input); got != tt.want {
+			t.Errorf("Transliterate(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
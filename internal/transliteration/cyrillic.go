@@ -0,0 +1,40 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ISO 9:1995 transliteration of Cyrillic: one Latin letter per Cyrillic
+// letter, using diacritics rather than digraphs so it can be reversed.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "ë",
+	'ж': "ž", 'з': "z", 'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'ш': "š", 'щ': "ŝ", 'ъ': "ʺ",
+	'ы': "y", 'ь': "ʹ", 'э': "è", 'ю': "û", 'я': "â",
+	// Ukrainian, Belarusian, Serbian, Macedonian and Kazakh letters.
+	'ґ': "g̀", 'є': "ê", 'і': "ì", 'ї': "ï", 'ў': "ǔ", 'ђ': "đ", 'ѓ': "ǵ",
+	'ѕ': "ẑ", 'ј': "ǰ", 'љ': "l̂", 'њ': "n̂", 'ћ': "ć", 'ќ': "ḱ", 'џ': "d̂",
+	'ә': "a̋", 'ғ': "ġ", 'қ': "ķ", 'ң': "ṇ", 'ө': "ô", 'ұ': "ù", 'ү': "u̇",
+	'һ': "ḥ",
+}
+
+// romanizeCyrillic transliterates text with ISO 9, keeping capitalization.
+// Characters it has no mapping for are written through.
+func romanizeCyrillic(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		latin, ok := cyrillic[unicode.ToLower(r)]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		if unicode.IsUpper(r) {
+			first := []rune(latin)
+			latin = string(unicode.ToUpper(first[0])) + string(first[1:])
+		}
+		b.WriteString(latin)
+	}
+	return b.String()
+}
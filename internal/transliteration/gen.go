@@ -0,0 +1,145 @@
+//go:build ignore
+
+// gen downloads the phrase dictionary go-pinyin's phrase support is built on
+// (phrase-pinyin-data) into phrases.tsv.gz. Run it with go generate (or make
+// dictionaries) to update the bundled phrases.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	phrasesURL = "https://raw.githubusercontent.com/mozillazg/phrase-pinyin-data/master/large_pinyin.txt"
+
+	// minPhrases guards against replacing the phrases with a truncated
+	// download; large_pinyin.txt has had several hundred thousand for years.
+	minPhrases = 100_000
+)
+
+const header = `# Pinyin of Chinese phrases from phrase-pinyin-data, written by gen.go.
+# phrase-pinyin-data is licensed under the MIT License.
+# Source: https://github.com/mozillazg/phrase-pinyin-data
+#
+# One entry per line: phrase<TAB>syllables with tone numbers, ü as v and no
+# number for the neutral tone (chang2 cheng2).
+`
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	resp, err := http.Get(phrasesURL)
+	if err != nil {
+		return fmt.Errorf("downloading phrase-pinyin-data: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading phrase-pinyin-data: %s", resp.Status)
+	}
+
+	phrases := make(map[string]string)
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		// 长城: cháng chéng # comment
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		word, reading, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		word = strings.TrimSpace(word)
+		syllables, ok := numbered(strings.Fields(reading))
+		if !ok || !isPhrase(word, len(syllables)) {
+			continue
+		}
+		phrases[word] = strings.Join(syllables, " ")
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("downloading phrase-pinyin-data: %w", err)
+	}
+	if len(phrases) < minPhrases {
+		return fmt.Errorf("phrase-pinyin-data download has only %d phrases", len(phrases))
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	fmt.Fprint(zw, header)
+	for _, word := range slices.Sorted(maps.Keys(phrases)) {
+		fmt.Fprintf(zw, "%s\t%s\n", word, phrases[word])
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile("phrases.tsv.gz", buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	log.Printf("wrote phrases.tsv.gz (%d phrases)", len(phrases))
+	return nil
+}
+
+// toneMarks maps each tone-marked letter to its base letter and tone.
+var toneMarks = map[rune]struct {
+	base rune
+	tone int
+}{
+	'ā': {'a', 1}, 'á': {'a', 2}, 'ǎ': {'a', 3}, 'à': {'a', 4},
+	'ē': {'e', 1}, 'é': {'e', 2}, 'ě': {'e', 3}, 'è': {'e', 4},
+	'ī': {'i', 1}, 'í': {'i', 2}, 'ǐ': {'i', 3}, 'ì': {'i', 4},
+	'ō': {'o', 1}, 'ó': {'o', 2}, 'ǒ': {'o', 3}, 'ò': {'o', 4},
+	'ū': {'u', 1}, 'ú': {'u', 2}, 'ǔ': {'u', 3}, 'ù': {'u', 4},
+	'ǖ': {'v', 1}, 'ǘ': {'v', 2}, 'ǚ': {'v', 3}, 'ǜ': {'v', 4},
+	'ü': {'v', 0},
+	'ń': {'n', 2}, 'ň': {'n', 3}, 'ǹ': {'n', 4}, 'ḿ': {'m', 2},
+}
+
+// numbered rewrites tone-marked syllables (cháng) the way go-pinyin's Tone3
+// style writes them (chang2). It reports false for a syllable with anything
+// but lowercase pinyin letters in it.
+func numbered(syllables []string) ([]string, bool) {
+	out := make([]string, len(syllables))
+	for i, s := range syllables {
+		var b strings.Builder
+		tone := 0
+		for _, r := range s {
+			if m, ok := toneMarks[r]; ok {
+				b.WriteRune(m.base)
+				tone = max(tone, m.tone)
+				continue
+			}
+			if r < 'a' || r > 'z' {
+				return nil, false
+			}
+			b.WriteRune(r)
+		}
+		if tone > 0 {
+			fmt.Fprint(&b, tone)
+		}
+		out[i] = b.String()
+	}
+	return out, true
+}
+
+// isPhrase matches the check the transliteration package loads phrases with.
+func isPhrase(word string, syllables int) bool {
+	n := 0
+	for _, r := range word {
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+		n++
+	}
+	return n > 1 && n == syllables
+}
@@ -0,0 +1,162 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Modified Hepburn romanization of hiragana. Katakana is folded onto hiragana
+// before lookup. Long vowels are written out (おう → ou, ー repeats the vowel)
+// rather than with macrons, to keep the output ASCII like the other scripts.
+var (
+	kana = map[rune]string{
+		'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+		'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+		'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+		'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+		'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+		'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+		'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+		'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+		'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+		'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+		'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+		'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+		'や': "ya", 'ゆ': "yu", 'よ': "yo",
+		'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+		'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o",
+		'ゔ': "vu",
+		'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+		'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa", 'ゕ': "ka", 'ゖ': "ke",
+	}
+
+	// kanaDigraphs are syllables written with a small kana, such as きゃ. The
+	// yōon (kya, sha, ...) are generated in init; the rest are the extended
+	// combinations mostly seen in katakana loanwords.
+	kanaDigraphs = map[string]string{
+		"しぇ": "she", "ちぇ": "che", "じぇ": "je",
+		"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+		"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+		"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+		"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+		"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+		"いぇ": "ye",
+	}
+)
+
+func init() {
+	small := map[rune]string{'ゃ': "a", 'ゅ': "u", 'ょ': "o"}
+	for _, r := range "きしちにひみりぎじぢびぴ" {
+		stem := strings.TrimSuffix(kana[r], "i")
+		for s, vowel := range small {
+			switch stem {
+			case "sh", "ch", "j":
+				kanaDigraphs[string([]rune{r, s})] = stem + vowel
+			default:
+				kanaDigraphs[string([]rune{r, s})] = stem + "y" + vowel
+			}
+		}
+	}
+}
+
+const (
+	sokuon    = 'っ'
+	hatsuon   = 'ん'
+	choonpu   = 'ー'
+	kanaShift = 'ァ' - 'ぁ'
+)
+
+// isKana reports whether r is hiragana, katakana, the katakana long-vowel mark
+// or a voicing mark. Unicode files the marks under the Common and Inherited
+// scripts, but they only ever follow kana.
+func isKana(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Hiragana, unicode.Katakana), r == choonpu:
+		return true
+	case r >= '\u3099' && r <= '\u309C', r == '\uFF9E', r == '\uFF9F':
+		return true
+	}
+	return false
+}
+
+// toHiragana folds full-width katakana onto the matching hiragana.
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - kanaShift
+	}
+	return r
+}
+
+// romanizeJapanese romanizes the kana in text. Kanji are left as they are:
+// their reading depends on the word, which needs a dictionary this package
+// doesn't carry, and a pinyin reading would be wrong.
+func romanizeJapanese(text string) string {
+	// NFKC joins half-width katakana and their separate voicing marks.
+	runes := []rune(norm.NFKC.String(text))
+	for i, r := range runes {
+		runes[i] = toHiragana(r)
+	}
+
+	var b strings.Builder
+	var lastVowel byte
+	geminate := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		var syllable string
+		if i+1 < len(runes) {
+			if s, ok := kanaDigraphs[string(runes[i:i+2])]; ok {
+				syllable = s
+				i++
+			}
+		}
+		if syllable == "" {
+			switch r {
+			case sokuon:
+				geminate = true
+				continue
+			case hatsuon:
+				syllable = "n"
+				if i+1 < len(runes) && startsWithVowelOrY(kana[runes[i+1]]) {
+					syllable = "n'"
+				}
+			case choonpu:
+				if lastVowel != 0 {
+					b.WriteByte(lastVowel)
+				}
+				continue
+			default:
+				s, ok := kana[r]
+				if !ok {
+					geminate = false
+					lastVowel = 0
+					b.WriteRune(r)
+					continue
+				}
+				syllable = s
+			}
+		}
+
+		if geminate {
+			geminate = false
+			switch {
+			case strings.HasPrefix(syllable, "ch"):
+				b.WriteByte('t')
+			case !startsWithVowelOrY(syllable) && syllable != "n" && syllable != "n'":
+				b.WriteByte(syllable[0])
+			}
+		}
+		b.WriteString(syllable)
+		lastVowel = syllable[len(syllable)-1]
+		if lastVowel == 'n' || lastVowel == '\'' {
+			lastVowel = 0
+		}
+	}
+	return b.String()
+}
+
+func startsWithVowelOrY(s string) bool {
+	return s != "" && strings.ContainsRune("aiueoy", rune(s[0]))
+}
@@ -2,7 +2,8 @@ package transliteration
 
 import (
 	"strings"
-	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
@@ -12,6 +13,36 @@ const (
 	jungN      = 21
 )
 
+// Jamo indices used by the sound-change rules, in Unicode syllable order.
+const (
+	choG  = 0  // ㄱ
+	choKK = 1  // ㄲ
+	choN  = 2  // ㄴ
+	choD  = 3  // ㄷ
+	choR  = 5  // ㄹ
+	choM  = 6  // ㅁ
+	choB  = 7  // ㅂ
+	choS  = 9  // ㅅ
+	choSS = 10 // ㅆ
+	choNg = 11 // ㅇ, silent as an initial
+	choJ  = 12 // ㅈ
+	choCh = 14 // ㅊ
+	choK  = 15 // ㅋ
+	choT  = 16 // ㅌ
+	choP  = 17 // ㅍ
+
+	jungI = 20 // ㅣ
+
+	jongNieun = 4  // ㄴ
+	jongNH    = 6  // ㄶ
+	jongD     = 7  // ㄷ
+	jongL     = 8  // ㄹ
+	jongLH    = 15 // ㅀ
+	jongB     = 17 // ㅂ
+	jongNg    = 21 // ㅇ
+	jongHie   = 27 // ㅎ
+)
+
 // Revised Romanization of Korean
 var (
 	choseong = []string{
@@ -23,30 +54,184 @@ var (
 		"wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu",
 		"eu", "ui", "i",
 	}
-	jongseong = []string{
-		"", "g", "kk", "gs", "n", "nj", "nh", "d", "l", "lg",
-		"lm", "lb", "ls", "lt", "lp", "lh", "m", "b", "bs",
-		"s", "ss", "ng", "j", "ch", "k", "t", "p", "h",
+	// finalSound is how each final is pronounced, and so written, before a
+	// consonant or at the end of a word: every final neutralizes to one of
+	// k, n, t, l, m, p or ng, and clusters keep one of their consonants.
+	finalSound = []string{
+		"", "k", "k", "k", "n", "n", "n", "t", "l", "k",
+		"m", "l", "l", "l", "p", "l", "m", "p", "p", "t",
+		"t", "ng", "t", "t", "k", "t", "p", "t",
+	}
+	// liaison says what happens to each final before a syllable starting with
+	// silent ㅇ: the final's last consonant moves over to become the next
+	// initial, leaving the rest (if any) behind. ㅎ is silent here, and a
+	// final ㅇ stays put.
+	liaison = [jongN]struct{ kept, moved int }{
+		{0, choNg}, {0, choG}, {0, choKK}, {1, choS}, {0, choN},
+		{jongNieun, choJ}, {jongNieun, choNg}, {0, choD}, {0, choR}, {jongL, choG},
+		{jongL, choM}, {jongL, choB}, {jongL, choS}, {jongL, choT}, {jongL, choP},
+		{0, choR}, {0, choM}, {0, choB}, {jongB, choS}, {0, choS},
+		{0, choSS}, {jongNg, choNg}, {0, choJ}, {0, choCh}, {0, choK},
+		{0, choT}, {0, choP}, {0, choNg},
+	}
+	// compatJamo romanizes standalone jamo (ㄱ, ㅋ, ㅏ, ...) from the Hangul
+	// Compatibility Jamo block, in block order. Names like ㅋㅋㅋ use them.
+	compatJamo = []string{
+		"g", "kk", "gs", "n", "nj", "nh", "d", "tt", "r", "lg",
+		"lm", "lb", "ls", "lt", "lp", "lh", "m", "b", "pp", "bs",
+		"s", "ss", "ng", "j", "jj", "ch", "k", "t", "p", "h",
+		"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa",
+		"wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui",
+		"i",
 	}
 )
 
+const (
+	compatJamoBase = 0x3131
+	choseongBase   = 0x1100
+	jungseongBase  = 0x1161
+	jongseongBase  = 0x11A8
+)
+
+type syllable struct{ cho, jung, jong int }
+
+// romanizeKorean applies Revised Romanization, including the sound changes
+// it transcribes across syllable boundaries: liaison (꿈을 kkumeul),
+// palatalization (같이 gachi), aspiration with ㅎ (좋고 joko), nasalization
+// (백마 baengma, 종로 jongno) and ㄹ-assimilation (신라 silla). Following
+// the rules for nouns, ㅎ after ㄱ, ㄷ or ㅂ is written rather than merged
+// (묵호 mukho), and tensification isn't shown.
 func romanizeKorean(text string) string {
 	var b strings.Builder
-	for _, r := range text {
+	var run []syllable
+	for _, r := range norm.NFC.String(text) {
 		if r >= hangulBase && r <= hangulEnd {
 			code := int(r) - hangulBase
-			jong := code % jongN
-			jung := (code / jongN) % jungN
-			cho := code / (jongN * jungN)
-			b.WriteString(choseong[cho])
-			b.WriteString(jungseong[jung])
-			b.WriteString(jongseong[jong])
-		} else if unicode.Is(unicode.Hangul, r) {
-			// Jamo or compatibility jamo — skip gracefully
-			b.WriteRune(r)
-		} else {
-			b.WriteRune(r)
+			run = append(run, syllable{
+				cho:  code / (jongN * jungN),
+				jung: (code / jongN) % jungN,
+				jong: code % jongN,
+			})
+			continue
 		}
+		writeSyllables(&b, run)
+		run = run[:0]
+		b.WriteString(romanizeJamo(r))
 	}
+	writeSyllables(&b, run)
 	return b.String()
 }
+
+// writeSyllables romanizes a run of adjacent syllables, resolving each
+// boundary between them with soundChange.
+func writeSyllables(b *strings.Builder, run []syllable) {
+	prevFinal := ""
+	cho := 0
+	for i, s := range run {
+		if i == 0 {
+			cho = s.cho
+		}
+		final := finalSound[s.jong]
+		nextCho := 0
+		if i+1 < len(run) {
+			final, nextCho = soundChange(s.jong, run[i+1].cho, run[i+1].jung)
+		}
+
+		if cho == choR && prevFinal == "l" {
+			b.WriteString("l")
+		} else {
+			b.WriteString(choseong[cho])
+		}
+		b.WriteString(jungseong[s.jung])
+		b.WriteString(final)
+
+		prevFinal = final
+		cho = nextCho
+	}
+}
+
+// soundChange resolves the boundary between a syllable ending in jong and one
+// starting with cho and vowel jung. It returns how the final is written and
+// which initial the next syllable is written with.
+func soundChange(jong, cho, jung int) (string, int) {
+	if cho == choNg {
+		l := liaison[jong]
+		moved := l.moved
+		if jung == jungI {
+			switch moved {
+			case choD:
+				moved = choJ
+			case choT:
+				moved = choCh
+			}
+		}
+		return finalSound[l.kept], moved
+	}
+
+	// A final ㅎ aspirates a following ㄱ, ㄷ or ㅈ and otherwise acts like ㄷ,
+	// except before ㄴ where it becomes ㄴ itself.
+	if jong == jongHie || jong == jongNH || jong == jongLH {
+		kept := map[int]int{jongHie: 0, jongNH: jongNieun, jongLH: jongL}[jong]
+		switch cho {
+		case choG:
+			return finalSound[kept], choK
+		case choD:
+			return finalSound[kept], choT
+		case choJ:
+			return finalSound[kept], choCh
+		case choS:
+			return finalSound[kept], cho
+		case choN:
+			if jong == jongHie {
+				return "n", cho
+			}
+			jong = kept
+		default:
+			if jong == jongHie {
+				jong = jongD
+			} else {
+				jong = kept
+			}
+		}
+	}
+
+	final := finalSound[jong]
+	switch {
+	case cho == choR && (final == "n" || final == "l"):
+		final = "l"
+	case cho == choR && final != "":
+		cho = choN
+	case cho == choN && final == "l":
+		cho = choR
+	}
+	if cho == choN || cho == choM {
+		switch final {
+		case "k":
+			final = "ng"
+		case "t":
+			final = "n"
+		case "p":
+			final = "m"
+		}
+	}
+	return final, cho
+}
+
+// romanizeJamo romanizes a standalone jamo, or returns r unchanged if it isn't
+// one.
+func romanizeJamo(r rune) string {
+	switch {
+	case r >= compatJamoBase && int(r-compatJamoBase) < len(compatJamo):
+		return compatJamo[r-compatJamoBase]
+	case r >= choseongBase && int(r-choseongBase) < len(choseong):
+		if r-choseongBase == choNg {
+			return "ng"
+		}
+		return choseong[r-choseongBase]
+	case r >= jungseongBase && int(r-jungseongBase) < len(jungseong):
+		return jungseong[r-jungseongBase]
+	case r >= jongseongBase && int(r-jongseongBase) < jongN-1:
+		return finalSound[r-jongseongBase+1]
+	}
+	return string(r)
+}
@@ -0,0 +1,166 @@
+package transliteration
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Names arrive in forms that look identical but compare differently:
+// fullwidth Latin (ＦＡＫＥＲ), halfwidth kana, jamo from either Hangul jamo
+// block, zero-width characters and Hangul fillers that pad a name invisibly,
+// and Cyrillic or Greek letters standing in for Latin ones (Fаker with a
+// Cyrillic а). CanonicalKey folds them all to one spelling.
+
+// CanonicalKey returns the form of name used to cache and deduplicate its
+// translation; the original is what gets displayed. The key is the name under
+// NFKC, with invisible characters removed and standalone jamo written in the
+// Hangul Compatibility Jamo block, and with lookalike letters read as Latin
+// when every other letter of the name is Latin. Names in a single foreign
+// script keep their letters, so Cyrillic ТОР stays Cyrillic.
+func CanonicalKey(name string) string {
+	name = normalize(name)
+	if !readsAsLatin([]rune(name)) {
+		return name
+	}
+	return strings.Map(foldLookalike, name)
+}
+
+// readsAsLatin reports whether runes have a Latin letter and every other
+// letter in them has a Latin lookalike.
+func readsAsLatin(runes []rune) bool {
+	hasLatin := false
+	for _, r := range runes {
+		switch {
+		case !unicode.IsLetter(r):
+		case unicode.Is(unicode.Latin, r):
+			hasLatin = true
+		default:
+			if _, ok := latinLookalikes[r]; !ok {
+				return false
+			}
+		}
+	}
+	return hasLatin
+}
+
+// foldLookalike returns the Latin letter r can't be told apart from, or r.
+func foldLookalike(r rune) rune {
+	if l, ok := latinLookalikes[r]; ok {
+		return l
+	}
+	return r
+}
+
+// foldLatinWords reads lookalike letters as Latin in the words of runes that
+// read as Latin, so that "Fаker" with a Cyrillic а is one Latin word even when
+// the rest of the name isn't Latin and CanonicalKey keeps it. Words here are
+// runs of Latin, Cyrillic and Greek letters.
+func foldLatinWords(runes []rune) []rune {
+	out := slices.Clone(runes)
+	for start := 0; start < len(out); {
+		end := start + 1
+		for end < len(out) && isAlphabetic(out[start]) && isAlphabetic(out[end]) {
+			end++
+		}
+		if word := out[start:end]; readsAsLatin(word) {
+			for i, r := range word {
+				word[i] = foldLookalike(r)
+			}
+		}
+		start = end
+	}
+	return out
+}
+
+// normalize applies NFKC to name and drops its invisible characters. Hangul
+// compatibility jamo are left alone rather than decomposed to conjoining jamo,
+// which would make ㅎㅏㄴ render as 한, and conjoining jamo that NFKC leaves
+// standalone are written as compatibility jamo, so ㅋㅋ reads the same
+// however it was typed.
+func normalize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if isInvisible(r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	var b strings.Builder
+	start := 0
+	for i, r := range name {
+		if !isCompatJamo(r) {
+			continue
+		}
+		b.WriteString(norm.NFKC.String(name[start:i]))
+		b.WriteRune(r)
+		start = i + len(string(r))
+	}
+	b.WriteString(norm.NFKC.String(name[start:]))
+
+	return strings.Map(func(r rune) rune {
+		if isInvisible(r) {
+			// NFKC turns the halfwidth filler into a conjoining one.
+			return -1
+		}
+		return compatJamoFor(r)
+	}, b.String())
+}
+
+// isInvisible reports whether r renders as nothing: format characters like
+// zero-width spaces and joiners, variation selectors, the combining grapheme
+// joiner, and the Hangul fillers players use for blank-looking names.
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u034F', '\u115F', '\u1160', '\u3164', '\uFFA0':
+		return true
+	}
+	return unicode.Is(unicode.Cf, r) || unicode.Is(unicode.Variation_Selector, r)
+}
+
+func isCompatJamo(r rune) bool { return r >= 0x3131 && r <= 0x318E }
+
+// Conjoining jamo in the Hangul Jamo block, as compatibility jamo: leading
+// consonants, vowels and trailing consonants, each in block order.
+const (
+	compatLeading  = "ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ"
+	compatTrailing = "ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ"
+	vowelsBase     = 0x314F // ㅏ
+)
+
+// compatJamoFor returns the compatibility jamo for a modern conjoining jamo,
+// or r itself.
+func compatJamoFor(r rune) rune {
+	switch {
+	case r >= choseongBase && r < choseongBase+19:
+		return []rune(compatLeading)[r-choseongBase]
+	case r >= jungseongBase && r < jungseongBase+21:
+		return vowelsBase + r - jungseongBase
+	case r >= jongseongBase && r < jongseongBase+27:
+		return []rune(compatTrailing)[r-jongseongBase]
+	}
+	return r
+}
+
+// isAlphabetic reports whether r is a Latin, Cyrillic or Greek letter, or a
+// mark on one.
+func isAlphabetic(r rune) bool {
+	return unicode.In(r, unicode.Latin, unicode.Cyrillic, unicode.Greek) || unicode.Is(unicode.M, r)
+}
+
+// latinLookalikes maps Cyrillic and Greek letters to the Latin letters they
+// can't be told apart from in most fonts, after Unicode's confusables data.
+var latinLookalikes = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'е': 'e', 'к': 'k', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x', 'ѕ': 's',
+	'і': 'i', 'ј': 'j', 'ԁ': 'd', 'һ': 'h', 'ԛ': 'q', 'ԝ': 'w', 'ӏ': 'l', 'ү': 'y',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P', 'С': 'C',
+	'Т': 'T', 'У': 'Y', 'Х': 'X', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J', 'Ԁ': 'D', 'Һ': 'H', 'Ԛ': 'Q',
+	'Ԝ': 'W', 'Ӏ': 'I', 'Ү': 'Y',
+	// Greek
+	'α': 'a', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M', 'Ν': 'N',
+	'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
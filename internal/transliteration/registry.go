@@ -0,0 +1,198 @@
+package transliteration
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/rangetable"
+)
+
+// Script is a writing system whose names the bot translates.
+type Script struct {
+	// Name identifies the script, and is the language stored on public
+	// translations ("korean").
+	Name string
+	// Label is the script's language as named in the translation prompt.
+	Label string
+	// Ranges are the characters that mark a name as written in the script.
+	Ranges *unicode.RangeTable
+	// Claims are characters shared with other scripts that belong to this one
+	// in names that have any of its Ranges, like kanji next to kana.
+	Claims *unicode.RangeTable
+	// ClaimWords limits Claims to the words that have one of the script's
+	// Ranges, rather than the whole name: Vietnamese claims the Latin letters
+	// of "ơi" but not those of "Faker" in "Faker ơi".
+	ClaimWords bool
+	// OptIn scripts are left out of DefaultScripts, so that servers only get
+	// them translated once they choose them with /config.
+	OptIn bool
+	// Romanize writes a run of the script's characters in Latin letters.
+	Romanize func(text string, style Style) string
+	// PromptHint tells the model what to watch for in names in the script.
+	PromptHint string
+}
+
+var (
+	registryMu sync.RWMutex
+	// registry is in detection order: a name is classified as the first
+	// script any of its characters belongs to.
+	registry = []Script{
+		{
+			Name:     ScriptKorean,
+			Label:    "Korean",
+			Ranges:   unicode.Hangul,
+			Romanize: func(text string, _ Style) string { return romanizeKorean(text) },
+		},
+		{
+			Name:     ScriptJapanese,
+			Label:    "Japanese",
+			Ranges:   rangetable.Merge(unicode.Hiragana, unicode.Katakana, rangetable.New([]rune(japaneseExtras)...)),
+			Claims:   unicode.Han,
+			Romanize: func(text string, _ Style) string { return romanizeJapanese(text) },
+			PromptHint: "Japanese names often mix kanji with hiragana or katakana. Katakana usually spells out a foreign word, " +
+				"so translate it back to that word rather than romanizing it, and read kanji with their Japanese meaning, " +
+				"not the Chinese one. Anime, manga and Japanese pro player references are common.",
+		},
+		{
+			Name:     ScriptChinese,
+			Label:    "Chinese",
+			Ranges:   unicode.Han,
+			Romanize: romanizeChinese,
+		},
+		{
+			Name:     ScriptThai,
+			Label:    "Thai",
+			Ranges:   unicode.Thai,
+			OptIn:    true,
+			Romanize: func(text string, _ Style) string { return romanizeThai(text) },
+			PromptHint: "Thai is written without spaces between words, so split the name into words before translating. " +
+				"Players often use Thai nicknames (ไอ้, น้อง) and onomatopoeic laughter like 555.",
+		},
+		{
+			Name:     ScriptCyrillic,
+			Label:    "Russian",
+			Ranges:   unicode.Cyrillic,
+			OptIn:    true,
+			Romanize: func(text string, _ Style) string { return romanizeCyrillic(text) },
+			PromptHint: "Cyrillic names are usually Russian, but may be Ukrainian, Bulgarian, Serbian or Kazakh. " +
+				"Watch for transliterated English words and gaming slang.",
+		},
+		{
+			Name:     ScriptArabic,
+			Label:    "Arabic",
+			Ranges:   unicode.Arabic,
+			OptIn:    true,
+			Romanize: func(text string, _ Style) string { return romanizeArabic(text) },
+			PromptHint: "Arabic-script names may be Arabic, Persian or Urdu. They are usually written without vowel marks, " +
+				"so consider each plausible reading and pick the one that makes sense as a name.",
+		},
+		{
+			Name:       ScriptVietnamese,
+			Label:      "Vietnamese",
+			Ranges:     vietnameseLetters,
+			Claims:     unicode.Latin,
+			ClaimWords: true,
+			OptIn:      true,
+			Romanize:   func(text string, _ Style) string { return romanizeVietnamese(text) },
+			PromptHint: "Vietnamese is written in Latin letters with diacritics; players often drop some of them, " +
+				"so read the name with the tones it most likely has.",
+		},
+	}
+)
+
+// japaneseExtras are the characters besides kana and kanji that only appear in
+// Japanese: the long-vowel mark, voicing marks and japaneseKanji.
+const japaneseExtras = "ー゙゚゛゜ﾞﾟ" + japaneseKanji
+
+// Register adds a script to the end of the registry, after the built-in ones.
+// It panics if a script with the same name is already registered, like
+// database/sql.Register.
+func Register(s Script) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, existing := range registry {
+		if existing.Name == s.Name {
+			panic(fmt.Sprintf("transliteration: script %q registered twice", s.Name))
+		}
+	}
+	registry = append(registry, s)
+}
+
+// Scripts returns the registered scripts in detection order.
+func Scripts() []Script {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Script(nil), registry...)
+}
+
+// DefaultScripts returns the names of the scripts translated in servers that
+// haven't chosen any: the registered scripts that aren't OptIn.
+func DefaultScripts() []string {
+	var names []string
+	for _, s := range Scripts() {
+		if !s.OptIn {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// Lookup returns the registered script called name.
+func Lookup(name string) (Script, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, s := range registry {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Script{}, false
+}
+
+// IsForeign reports whether r belongs to any registered script: the
+// characters whose names get translated.
+func IsForeign(r rune) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, s := range registry {
+		if unicode.Is(s.Ranges, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsScript reports whether text has characters from any of the scripts
+// named in names, attributing shared characters as Segments does: kanji in a
+// name with kana count as Japanese, not Chinese. An empty names means every
+// registered script. Text is compared by its CanonicalKey, so fullwidth Latin
+// and Latin spelled with lookalike letters don't count.
+func ContainsScript(text string, names []string) bool {
+	text = CanonicalKey(text)
+	if len(names) == 0 {
+		return strings.ContainsFunc(text, IsForeign)
+	}
+	return slices.ContainsFunc(runeScripts([]rune(text), Scripts()), func(s Script) bool {
+		return slices.Contains(names, s.Name)
+	})
+}
+
+// ParseScripts parses a comma-separated list of script names, as stored in a
+// server's config. It returns an error naming the first unregistered script.
+func ParseScripts(list string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown script %q", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
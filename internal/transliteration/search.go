@@ -0,0 +1,27 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SearchKey returns the plain romanization of username's gameName folded to
+// lowercase letters and digits, without accents or spaces, for finding a name
+// by how it sounds: 페이커#KR1 is "peikeo" and 大魔王Faker "damowangfaker".
+// Searches are keyed the same way, so typing "Dà Mó Wáng" or "대마왕" works
+// as well as "damowang".
+func SearchKey(username string) string {
+	return foldSearch(JoinSegments(Segments(CanonicalKey(username), StylePlain)))
+}
+
+func foldSearch(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return norm.NFC.String(b.String())
+}
@@ -0,0 +1,133 @@
+package transliteration
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Segment is a run of a name written in one script, together with its
+// romanization. Latin runs hold everything that isn't Hangul, Han or kana
+// (letters, digits, spaces, punctuation) and are romanized as themselves.
+type Segment struct {
+	Text      string
+	Script    string
+	Romanized string
+}
+
+// Segments splits the gameName part of username into script runs and
+// romanizes each with its own script's romanizer, so "大魔王Faker" or
+// "김치ラーメン" aren't read as a single language. Cyrillic and Greek letters
+// that look Latin stay in the Latin run of a word that is otherwise Latin
+// ("Fаker"), as CanonicalKey reads them. Characters a script claims go to it
+// when the name has any of its own characters: Han is read as Japanese when
+// the name has kana or a Japanese-only kanji anywhere in it, and as Chinese
+// otherwise. The #tag is stripped.
+func Segments(username string, style Style) []Segment {
+	gameName := username
+	if idx := strings.IndexByte(username, '#'); idx >= 0 {
+		gameName = username[:idx]
+	}
+
+	runes := []rune(gameName)
+	folded := foldLatinWords(runes)
+	var segments []Segment
+	var romanizers []func(string, Style) string
+	for i, script := range runeScripts(folded, Scripts()) {
+		if n := len(segments); n > 0 && segments[n-1].Script == script.Name {
+			segments[n-1].Text += string(runes[i])
+			segments[n-1].Romanized += string(folded[i])
+			continue
+		}
+		segments = append(segments, Segment{Text: string(runes[i]), Script: script.Name, Romanized: string(folded[i])})
+		romanizers = append(romanizers, script.Romanize)
+	}
+
+	// Latin runs are romanized as themselves, with lookalike letters read as
+	// the Latin ones they stand in for; other scripts never have those folded.
+	for i, s := range segments {
+		if romanizers[i] != nil {
+			segments[i].Romanized = romanizers[i](s.Text, style)
+		}
+	}
+	return segments
+}
+
+// JoinSegments flattens segments into one string, putting a space between two
+// runs that would otherwise run together ("damowang Faker").
+func JoinSegments(segments []Segment) string {
+	var b strings.Builder
+	for i, s := range segments {
+		if i > 0 && wordEdge(b.String(), true) && wordEdge(s.Romanized, false) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s.Romanized)
+	}
+	return b.String()
+}
+
+// wordEdge reports whether s ends (or starts) with a letter or digit.
+func wordEdge(s string, end bool) bool {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return false
+	}
+	r := runes[0]
+	if end {
+		r = runes[len(runes)-1]
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// runeScripts returns the script each rune belongs to, giving precedence to
+// the claims of scripts present in the name, or for ClaimWords scripts in the
+// rune's word. Characters in no script are ScriptLatin, with no romanizer.
+func runeScripts(runes []rune, scripts []Script) []Script {
+	inName := claiming(runes, scripts, false)
+	out := make([]Script, 0, len(runes))
+	for start := 0; start < len(runes); {
+		end := start + 1
+		if isWordRune(runes[start]) {
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+		}
+		present := slices.Concat(inName, claiming(runes[start:end], scripts, true))
+		for _, r := range runes[start:end] {
+			out = append(out, runeScript(r, scripts, present))
+		}
+		start = end
+	}
+	return out
+}
+
+// isWordRune reports whether r is part of a word: a letter or a mark on one.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.Is(unicode.M, r)
+}
+
+// claiming returns the scripts with claims, and with ClaimWords set as given,
+// that have characters in runes.
+func claiming(runes []rune, scripts []Script, claimWords bool) []Script {
+	var present []Script
+	for _, s := range scripts {
+		if s.Claims != nil && s.ClaimWords == claimWords && slices.ContainsFunc(runes, func(r rune) bool { return unicode.Is(s.Ranges, r) }) {
+			present = append(present, s)
+		}
+	}
+	return present
+}
+
+func runeScript(r rune, scripts, present []Script) Script {
+	for _, s := range present {
+		if unicode.Is(s.Claims, r) {
+			return s
+		}
+	}
+	for _, s := range scripts {
+		if unicode.Is(s.Ranges, r) {
+			return s
+		}
+	}
+	return Script{Name: ScriptLatin}
+}
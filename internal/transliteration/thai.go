@@ -0,0 +1,263 @@
+package transliteration
+
+import (
+	"slices"
+	"strings"
+)
+
+// Royal Thai General System of Transcription (RTGS). Thai is written without
+// spaces and leaves some vowels unwritten, so a real transcription needs a
+// dictionary; this reads one syllable at a time by the spelling rules, which
+// gets common names right and the rest close enough to pronounce.
+var (
+	// thaiConsonants holds each consonant's sound as a syllable's initial and
+	// as its final.
+	thaiConsonants = map[rune]struct{ initial, final string }{
+		'ก': {"k", "k"}, 'ข': {"kh", "k"}, 'ฃ': {"kh", "k"}, 'ค': {"kh", "k"}, 'ฅ': {"kh", "k"}, 'ฆ': {"kh", "k"},
+		'ง': {"ng", "ng"}, 'จ': {"ch", "t"}, 'ฉ': {"ch", "t"}, 'ช': {"ch", "t"}, 'ซ': {"s", "t"}, 'ฌ': {"ch", "t"},
+		'ญ': {"y", "n"}, 'ฎ': {"d", "t"}, 'ฏ': {"t", "t"}, 'ฐ': {"th", "t"}, 'ฑ': {"th", "t"}, 'ฒ': {"th", "t"},
+		'ณ': {"n", "n"}, 'ด': {"d", "t"}, 'ต': {"t", "t"}, 'ถ': {"th", "t"}, 'ท': {"th", "t"}, 'ธ': {"th", "t"},
+		'น': {"n", "n"}, 'บ': {"b", "p"}, 'ป': {"p", "p"}, 'ผ': {"ph", "p"}, 'ฝ': {"f", "p"}, 'พ': {"ph", "p"},
+		'ฟ': {"f", "p"}, 'ภ': {"ph", "p"}, 'ม': {"m", "m"}, 'ย': {"y", "i"}, 'ร': {"r", "n"}, 'ล': {"l", "n"},
+		'ว': {"w", "o"}, 'ศ': {"s", "t"}, 'ษ': {"s", "t"}, 'ส': {"s", "t"}, 'ห': {"h", ""}, 'ฬ': {"l", "n"},
+		'อ': {"", ""}, 'ฮ': {"h", ""},
+	}
+	// thaiVowels are the vowel signs written after (or above or below) a
+	// syllable's initial, read on their own.
+	thaiVowels = map[rune]string{
+		'ะ': "a", 'ั': "a", 'า': "a", 'ำ': "am", 'ิ': "i", 'ี': "i",
+		'ึ': "ue", 'ื': "ue", 'ุ': "u", 'ู': "u", '็': "o",
+	}
+)
+
+const (
+	thaiRepeat    = 'ๆ' // repeats the previous word
+	thaiSilencer  = '์' // thanthakhat: the consonant under it isn't pronounced
+	thaiNikhahit  = 'ํ'
+	thaiPhinthu   = 'ฺ'
+	thaiDigitZero = '๐'
+)
+
+func isThaiConsonant(r rune) bool {
+	_, ok := thaiConsonants[r]
+	return ok
+}
+
+func isThaiLeadingVowel(r rune) bool { return r >= 'เ' && r <= 'ไ' }
+
+func isThaiToneMark(r rune) bool { return r >= '่' && r <= '๋' }
+
+// romanizeThai transcribes text with RTGS, without tones (RTGS doesn't mark
+// them). Anything that isn't Thai is written through.
+func romanizeThai(text string) string {
+	t := thaiReader{runes: []rune(text)}
+	var b strings.Builder
+	var last string
+	for t.i < len(t.runes) {
+		r := t.runes[t.i]
+		switch {
+		case r == thaiRepeat:
+			b.WriteString(last)
+			t.i++
+		case r >= thaiDigitZero && r <= thaiDigitZero+9:
+			b.WriteRune('0' + r - thaiDigitZero)
+			t.i++
+		case r == 'ฤ':
+			last = "rue"
+			b.WriteString(last)
+			t.i++
+		case r == 'ฦ':
+			last = "lue"
+			b.WriteString(last)
+			t.i++
+		case isThaiConsonant(r) && t.peek(1) == thaiSilencer:
+			t.i += 2
+		case isThaiConsonant(r) && isThaiConsonant(t.peek(1)) && t.peek(2) == thaiSilencer:
+			// จันทร์: the silencer can cover the consonant before it too.
+			t.i += 3
+		case isThaiConsonant(r) || isThaiLeadingVowel(r):
+			last = t.syllable()
+			b.WriteString(last)
+		case isThaiToneMark(r), r == thaiSilencer, r == thaiPhinthu, r == 'ๅ':
+			t.i++
+		default:
+			if v, ok := thaiVowels[r]; ok {
+				b.WriteString(v)
+			} else {
+				b.WriteRune(r)
+			}
+			t.i++
+		}
+	}
+	return b.String()
+}
+
+type thaiReader struct {
+	runes []rune
+	i     int
+}
+
+// peek returns the rune n places after the current one, skipping tone marks,
+// or 0 past the end.
+func (t *thaiReader) peek(n int) rune {
+	j := t.i
+	for n > 0 {
+		j++
+		for j < len(t.runes) && isThaiToneMark(t.runes[j]) {
+			j++
+		}
+		n--
+	}
+	if j >= len(t.runes) {
+		return 0
+	}
+	return t.runes[j]
+}
+
+// next moves past the current rune and any tone marks after it.
+func (t *thaiReader) next() {
+	t.i++
+	for t.i < len(t.runes) && isThaiToneMark(t.runes[t.i]) {
+		t.i++
+	}
+}
+
+func (t *thaiReader) at() rune {
+	if t.i >= len(t.runes) {
+		return 0
+	}
+	return t.runes[t.i]
+}
+
+// followedByVowel reports whether the consonant n places ahead starts a
+// syllable of its own: the rune after it is a vowel sign.
+func (t *thaiReader) followedByVowel(n int) bool {
+	_, ok := thaiVowels[t.peek(n+1)]
+	return ok || t.peek(n+1) == thaiNikhahit
+}
+
+// syllable reads one syllable starting at a leading vowel or consonant:
+// an optional leading vowel, the initial (possibly a cluster), vowel signs,
+// and a final consonant.
+func (t *thaiReader) syllable() string {
+	var lead rune
+	if isThaiLeadingVowel(t.at()) {
+		lead = t.at()
+		t.next()
+	}
+	c := t.at()
+	if !isThaiConsonant(c) {
+		return t.vowel(lead, nil)
+	}
+
+	// ห before a sonorant and อ before ย only set the tone; the sonorant is
+	// the initial.
+	if n := t.peek(1); (c == 'ห' && strings.ContainsRune("งญนมยรลว", n)) || (c == 'อ' && n == 'ย') {
+		t.next()
+		c = n
+	}
+	initial := thaiConsonants[c].initial
+	t.next()
+
+	// Clusters: kr, kl, kw, khr, khl, khw, pr, pl, phr, phl, tr.
+	if n := t.at(); (n == 'ร' || n == 'ล' || n == 'ว') && strings.ContainsRune("กขคปพต", c) &&
+		(n != 'ว' || strings.ContainsRune("กขค", c)) && (n != 'ล' || c != 'ต') &&
+		(lead != 0 || t.followedByVowel(0)) {
+		initial += thaiConsonants[n].initial
+		t.next()
+	}
+
+	var signs []rune
+	for {
+		r := t.at()
+		if _, ok := thaiVowels[r]; ok || r == thaiNikhahit {
+			signs = append(signs, r)
+			t.next()
+			continue
+		}
+		break
+	}
+
+	vowel := t.vowel(lead, signs)
+	final := ""
+	switch {
+	case vowel == "ai":
+		// ไทย: a ย after ai is silent.
+		if t.at() == 'ย' && !t.followedByVowel(0) {
+			t.next()
+		}
+	case vowel == "am", vowel == "ao", slices.Contains(signs, 'ะ'):
+		// These vowels end the syllable.
+	default:
+		if n := t.at(); isThaiConsonant(n) && !t.followedByVowel(0) {
+			t.next()
+			if t.at() == thaiSilencer {
+				t.i++
+			} else {
+				final = thaiConsonants[n].final
+			}
+		}
+	}
+	if vowel == "" {
+		// No written vowel: an inherent o before a final, a otherwise.
+		vowel = "a"
+		if final != "" {
+			vowel = "o"
+		}
+	}
+	return initial + vowel + final
+}
+
+// vowel works out the vowel of a syllable from its leading vowel and signs,
+// taking อ, ว or ย after the initial when they're part of the vowel.
+func (t *thaiReader) vowel(lead rune, signs []rune) string {
+	has := func(r rune) bool { return slices.Contains(signs, r) }
+	n := t.at()
+	switch lead {
+	case 'เ':
+		switch {
+		case has('ี') && n == 'ย':
+			t.next()
+			return "ia"
+		case has('ื') && n == 'อ':
+			t.next()
+			return "uea"
+		case has('ิ'):
+			return "oe"
+		case has('า') && has('ะ'):
+			return "o"
+		case has('า'):
+			return "ao"
+		case len(signs) == 0 && n == 'อ' && !t.followedByVowel(0):
+			t.next()
+			return "oe"
+		}
+		return "e"
+	case 'แ':
+		return "ae"
+	case 'โ':
+		return "o"
+	case 'ใ', 'ไ':
+		return "ai"
+	}
+
+	switch {
+	case len(signs) == 0 && n == 'อ' && !t.followedByVowel(0):
+		t.next()
+		return "o"
+	case len(signs) == 0 && n == 'ว' && isThaiConsonant(t.peek(1)) && !t.followedByVowel(1):
+		t.next()
+		return "ua"
+	case has('ั') && n == 'ว':
+		t.next()
+		return "ua"
+	case has('ื') && n == 'อ':
+		t.next()
+		return "ue"
+	case has(thaiNikhahit):
+		return "am"
+	case len(signs) > 0:
+		return thaiVowels[signs[0]]
+	}
+	return ""
+}
@@ -5,36 +5,80 @@ import (
 	"unicode"
 )
 
+// Names of the built-in scripts, as returned by DetectScript. The values match
+// the language stored on public translations.
+const (
+	ScriptKorean     = "korean"
+	ScriptJapanese   = "japanese"
+	ScriptChinese    = "chinese"
+	ScriptThai       = "thai"
+	ScriptCyrillic   = "cyrillic"
+	ScriptArabic     = "arabic"
+	ScriptVietnamese = "vietnamese"
+	ScriptLatin      = "latin"
+)
+
+// japaneseKanji are characters written only in Japanese: the iteration marks,
+// kokuji (characters coined in Japan) and common shinjitai that differ from
+// both simplified and traditional Chinese. They let a kanji-only name be told
+// apart from Chinese.
+const japaneseKanji = "々〆畑峠込働辻枠栃凪榊桜沢駅図読売歳広浜薬楽絵険"
+
+// Style selects how Chinese is romanized. Other scripts have a single
+// romanization and ignore it.
+type Style string
+
+const (
+	// StylePlain is pinyin without tones, ü written as v: "changcheng".
+	StylePlain Style = "plain"
+	// StyleTones is pinyin with tone marks: "chángchéng".
+	StyleTones Style = "tones"
+	// StyleZhuyin is Zhuyin (Bopomofo), as used in Taiwan.
+	StyleZhuyin Style = "zhuyin"
+)
+
+// ParseStyle parses a style name. The empty string is StylePlain.
+func ParseStyle(s string) (Style, bool) {
+	switch Style(s) {
+	case "", StylePlain:
+		return StylePlain, true
+	case StyleTones, StyleZhuyin:
+		return Style(s), true
+	}
+	return "", false
+}
+
 // Transliterate converts a username (gameName#tag) to its romanized form.
 // Only the gameName part is transliterated; the #tag is stripped.
 // Returns empty string for Latin-only names.
 func Transliterate(username string) string {
-	gameName := username
-	if idx := strings.IndexByte(username, '#'); idx >= 0 {
-		gameName = username[:idx]
-	}
-
-	script := detectScript(gameName)
-	switch script {
-	case "korean":
-		return romanizeKorean(gameName)
-	case "chinese":
-		return romanizeChinese(gameName)
-	default:
-		return ""
-	}
+	return TransliterateStyle(username, StylePlain)
 }
 
-func detectScript(text string) string {
-	for _, r := range text {
-		if unicode.Is(unicode.Hangul, r) {
-			return "korean"
+// TransliterateStyle is Transliterate with Chinese written in style. Names
+// mixing scripts are romanized a run at a time; see Segments.
+func TransliterateStyle(username string, style Style) string {
+	segments := Segments(username, style)
+	for _, s := range segments {
+		if s.Script != ScriptLatin {
+			return JoinSegments(segments)
 		}
 	}
-	for _, r := range text {
-		if unicode.Is(unicode.Han, r) {
-			return "chinese"
+	return ""
+}
+
+// DetectScript classifies text by the registered script it's written in:
+// the first script, in registry order, that any of its characters belongs to.
+// Any Hangul makes it Korean; any kana, or a kanji only used in Japanese,
+// makes it Japanese; other Han characters are taken to be Chinese. Text in
+// none of the scripts is ScriptLatin, as is text that only looks foreign
+// until its CanonicalKey is taken, like fullwidth Latin.
+func DetectScript(text string) string {
+	text = CanonicalKey(text)
+	for _, s := range Scripts() {
+		if strings.ContainsFunc(text, func(r rune) bool { return unicode.Is(s.Ranges, r) }) {
+			return s.Name
 		}
 	}
-	return "latin"
+	return ScriptLatin
 }
@@ -1,6 +1,10 @@
 package transliteration
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"unicode"
+)
 
 func TestTransliterateKorean(t *testing.T) {
 	tests := []struct {
@@ -20,6 +24,65 @@ func TestTransliterateKorean(t *testing.T) {
 	}
 }
 
+// Examples from the National Institute of Korean Language's Revised
+// Romanization rules, plus a few names.
+func TestRomanizeKoreanSoundChanges(t *testing.T) {
+	tests := []struct {
+		rule  string
+		input string
+		want  string
+	}{
+		{"plain", "한글", "hangeul"},
+		{"plain", "부산", "busan"},
+		{"final neutralization", "닭", "dak"},
+		{"final neutralization", "값", "gap"},
+		{"liaison", "꿈을꾸다", "kkumeulkkuda"},
+		{"liaison", "설악", "seorak"},
+		{"liaison with cluster", "없어", "eopseo"},
+		{"liaison with cluster", "읽어", "ilgeo"},
+		{"liaison with cluster", "앉아", "anja"},
+		{"silent ㅎ", "좋아", "joa"},
+		{"silent ㅎ", "싫어", "sireo"},
+		{"final ㅇ stays", "종이", "jongi"},
+		{"palatalization", "해돋이", "haedoji"},
+		{"palatalization", "같이", "gachi"},
+		{"palatalization", "굳이", "guji"},
+		{"aspiration", "좋고", "joko"},
+		{"aspiration", "놓다", "nota"},
+		{"aspiration", "낳지", "nachi"},
+		{"aspiration", "많다", "manta"},
+		{"ㅎ kept in nouns", "묵호", "mukho"},
+		{"ㅎ kept in nouns", "집현전", "jiphyeonjeon"},
+		{"ㅎ after sonorant", "전화", "jeonhwa"},
+		{"nasalization", "백마", "baengma"},
+		{"ㄹ-assimilation", "대관령", "daegwallyeong"},
+		{"nasalization", "종로", "jongno"},
+		{"nasalization", "왕십리", "wangsimni"},
+		{"nasalization", "독립", "dongnip"},
+		{"nasalization", "입니다", "imnida"},
+		{"nasalization", "놓는", "nonneun"},
+		{"nasalization", "않는", "anneun"},
+		{"ㄹ-assimilation", "신라", "silla"},
+		{"ㄹ-assimilation", "한라산", "hallasan"},
+		{"ㄹ-assimilation", "별내", "byeollae"},
+		{"ㄹ-assimilation", "설날", "seollal"},
+		{"ㄹ-assimilation", "뚫는", "ttulleun"},
+		{"ㄹ-assimilation", "울릉", "ulleung"},
+		{"no change across spaces", "신 라", "sin ra"},
+		{"standalone jamo", "ㅋㅋㅋ", "kkk"},
+		{"standalone jamo", "ㅠㅠ", "yuyu"},
+		{"mixed jamo", "페이커ㅋㅋ", "peikeokk"},
+		{"conjoining jamo", "\u1112\u1161\u11AB", "han"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule+"/"+tt.input, func(t *testing.T) {
+			if got := romanizeKorean(tt.input); got != tt.want {
+				t.Errorf("romanizeKorean(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestTransliterateChinese(t *testing.T) {
 	tests := []struct {
 		input string
@@ -27,7 +90,14 @@ func TestTransliterateChinese(t *testing.T) {
 	}{
 		{"不知火舞#CN1", "buzhihuowu"},
 		{"大魔王#TW1", "damowang"},
-		{"人人人#NA1", "renrenren"},
+		{"人人人#NA1", "ren ren ren"},
+		{"我爱长城#CN1", "wo ai changcheng"},
+		{"長城#TW1", "changcheng"},
+		{"银行#CN1", "yinhang"},
+		{"行者#CN1", "xingzhe"},
+		{"队长#CN1", "duizhang"},
+		{"绿#CN1", "lv"},
+		{"Faker的长剑#CN1", "Faker de changjian"},
 	}
 	for _, tt := range tests {
 		got := Transliterate(tt.input)
@@ -37,6 +107,46 @@ func TestTransliterateChinese(t *testing.T) {
 	}
 }
 
+func TestTransliterateChineseStyles(t *testing.T) {
+	tests := []struct {
+		input string
+		style Style
+		want  string
+	}{
+		{"长城", StyleTones, "chángchéng"},
+		{"银行", StyleTones, "yínháng"},
+		{"觉得", StyleTones, "juéde"},
+		{"绿", StyleTones, "lǜ"},
+		{"小鱼人", StyleTones, "xiǎoyúrén"},
+		{"后来", StyleTones, "hòu lái"},
+		{"長城", StyleZhuyin, "ㄔㄤˊ ㄔㄥˊ"},
+		{"覺得", StyleZhuyin, "ㄐㄩㄝˊ ˙ㄉㄜ"},
+		{"知", StyleZhuyin, "ㄓ"},
+		{"雪", StyleZhuyin, "ㄒㄩㄝˇ"},
+		{"有", StyleZhuyin, "ㄧㄡˇ"},
+		{"王", StyleZhuyin, "ㄨㄤˊ"},
+		{"对", StyleZhuyin, "ㄉㄨㄟˋ"},
+		{"中", StyleZhuyin, "ㄓㄨㄥ"},
+		{"大魔王", StylePlain, "damowang"},
+	}
+	for _, tt := range tests {
+		if got := TransliterateStyle(tt.input, tt.style); got != tt.want {
+			t.Errorf("TransliterateStyle(%q, %q) = %q, want %q", tt.input, tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, s := range []string{"", "plain", "tones", "zhuyin"} {
+		if _, ok := ParseStyle(s); !ok {
+			t.Errorf("ParseStyle(%q) not ok", s)
+		}
+	}
+	if _, ok := ParseStyle("wade-giles"); ok {
+		t.Error("ParseStyle accepted an unknown style")
+	}
+}
+
 func TestTransliterateLatin(t *testing.T) {
 	got := Transliterate("Faker#NA1")
 	if got != "" {
@@ -50,3 +160,318 @@ func TestTransliterateNoTag(t *testing.T) {
 		t.Errorf("Transliterate(%q) = %q, want %q", "페이커", got, "peikeo")
 	}
 }
+
+func TestTransliterateJapanese(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"さくら#JP1", "sakura"},
+		{"しんかい#JP1", "shinkai"},
+		{"きっちゃ#JP1", "kitcha"},
+		{"ちゃっと#JP1", "chatto"},
+		{"きんようび#JP1", "kin'youbi"},
+		{"コーヒー#JP1", "koohii"},
+		{"フェイカー#JP1", "feikaa"},
+		{"ｶﾞﾝﾀﾞﾑ#JP1", "gandamu"},
+		{"ティーモ#JP1", "tiimo"},
+		{"山田たろう#JP1", "山田tarou"},
+	}
+	for _, tt := range tests {
+		got := Transliterate(tt.input)
+		if got != tt.want {
+			t.Errorf("Transliterate(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Segment
+		flat  string
+	}{
+		{
+			input: "大魔王Faker#NA1",
+			want: []Segment{
+				{Text: "大魔王", Script: ScriptChinese, Romanized: "damowang"},
+				{Text: "Faker", Script: ScriptLatin, Romanized: "Faker"},
+			},
+			flat: "damowang Faker",
+		},
+		{
+			input: "김치ラーメン#KR1",
+			want: []Segment{
+				{Text: "김치", Script: ScriptKorean, Romanized: "gimchi"},
+				{Text: "ラーメン", Script: ScriptJapanese, Romanized: "raamen"},
+			},
+			flat: "gimchi raamen",
+		},
+		{
+			input: "山田たろう2",
+			want: []Segment{
+				{Text: "山田たろう", Script: ScriptJapanese, Romanized: "山田tarou"},
+				{Text: "2", Script: ScriptLatin, Romanized: "2"},
+			},
+			flat: "山田tarou 2",
+		},
+		{
+			input: "T1 페이커",
+			want: []Segment{
+				{Text: "T1 ", Script: ScriptLatin, Romanized: "T1 "},
+				{Text: "페이커", Script: ScriptKorean, Romanized: "peikeo"},
+			},
+			flat: "T1 peikeo",
+		},
+		{
+			input: "ｶﾞﾝﾀﾞﾑ_長城",
+			want: []Segment{
+				{Text: "ｶﾞﾝﾀﾞﾑ", Script: ScriptJapanese, Romanized: "gandamu"},
+				{Text: "_", Script: ScriptLatin, Romanized: "_"},
+				{Text: "長城", Script: ScriptJapanese, Romanized: "長城"},
+			},
+			flat: "gandamu_長城",
+		},
+		{
+			// The а is Cyrillic.
+			input: "Fаker#NA1",
+			want: []Segment{
+				{Text: "Fаker", Script: ScriptLatin, Romanized: "Faker"},
+			},
+			flat: "Faker",
+		},
+		{
+			input: "大魔王Fаker",
+			want: []Segment{
+				{Text: "大魔王", Script: ScriptChinese, Romanized: "damowang"},
+				{Text: "Fаker", Script: ScriptLatin, Romanized: "Faker"},
+			},
+			flat: "damowang Faker",
+		},
+		{
+			input: "ТОР Fаker",
+			want: []Segment{
+				{Text: "ТОР", Script: ScriptCyrillic, Romanized: "TOR"},
+				{Text: " Fаker", Script: ScriptLatin, Romanized: " Faker"},
+			},
+			flat: "TOR Faker",
+		},
+	}
+	for _, tt := range tests {
+		got := Segments(tt.input, StylePlain)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Segments(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+		if flat := JoinSegments(got); flat != tt.flat {
+			t.Errorf("JoinSegments(Segments(%q)) = %q, want %q", tt.input, flat, tt.flat)
+		}
+	}
+}
+
+func TestTransliterateMixedScripts(t *testing.T) {
+	if got := Transliterate("大魔王Faker#NA1"); got != "damowang Faker" {
+		t.Errorf("Transliterate = %q, want %q", got, "damowang Faker")
+	}
+	if got := Transliterate("Faker 123#NA1"); got != "" {
+		t.Errorf("Transliterate of a Latin name = %q, want empty", got)
+	}
+}
+
+func TestDetectScript(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"페이커", ScriptKorean},
+		{"大魔王", ScriptChinese},
+		{"さくら", ScriptJapanese},
+		{"東京タワー", ScriptJapanese},
+		{"佐々木", ScriptJapanese},
+		{"Faker", ScriptLatin},
+	}
+	for _, tt := range tests {
+		if got := DetectScript(tt.input); got != tt.want {
+			t.Errorf("DetectScript(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTransliterateRegisteredScripts(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"สวัสดี#TH2", "sawatdi"},
+		{"ประเทศไทย#TH2", "prathetthai"},
+		{"สมชาย#TH2", "somchai"},
+		{"เมือง#TH2", "mueang"},
+		{"ขอบคุณ#TH2", "khopkhun"},
+		{"มากๆ#TH2", "makmak"},
+		{"Охотник#RU1", "Ohotnik"},
+		{"Щука#RU1", "Ŝuka"},
+		{"Їжак#RU1", "Ïžak"},
+		{"محمد#TR1", "mhmd"},
+		{"نور#TR1", "nur"},
+		{"Nguyễn Văn Đức#VN2", "Nguyen Van Duc"},
+	}
+	for _, tt := range tests {
+		if got := Transliterate(tt.input); got != tt.want {
+			t.Errorf("Transliterate(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDetectRegisteredScripts(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"สมชาย", ScriptThai},
+		{"Охотник", ScriptCyrillic},
+		{"محمد", ScriptArabic},
+		{"Nguyễn", ScriptVietnamese},
+		{"Nguyen", ScriptLatin},
+	}
+	for _, tt := range tests {
+		if got := DetectScript(tt.input); got != tt.want {
+			t.Errorf("DetectScript(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestContainsScript(t *testing.T) {
+	if !ContainsScript("Faker페이커", nil) {
+		t.Error("ContainsScript with no names should match any registered script")
+	}
+	if ContainsScript("Faker", nil) {
+		t.Error("ContainsScript matched a Latin name")
+	}
+	if ContainsScript("東京タワー", []string{ScriptChinese}) {
+		t.Error("kanji next to kana should count as Japanese, not Chinese")
+	}
+	if !ContainsScript("大魔王", []string{ScriptChinese, ScriptThai}) {
+		t.Error("ContainsScript missed a Chinese name")
+	}
+	if ContainsScript("ＦＡＫＥＲ", nil) {
+		t.Error("ContainsScript matched fullwidth Latin")
+	}
+	if ContainsScript("Fаker", nil) {
+		t.Error("ContainsScript matched Latin spelled with a Cyrillic lookalike")
+	}
+	if !ContainsScript("Faker ơi", []string{ScriptVietnamese}) {
+		t.Error("ContainsScript missed a Vietnamese word")
+	}
+}
+
+func TestSegmentsVietnameseClaimsOnlyItsWords(t *testing.T) {
+	want := []Segment{
+		{Text: "Faker ", Script: ScriptLatin, Romanized: "Faker "},
+		{Text: "ơi", Script: ScriptVietnamese, Romanized: "oi"},
+	}
+	if got := Segments("Faker ơi#VN2", StylePlain); !reflect.DeepEqual(got, want) {
+		t.Errorf("Segments(%q) = %+v, want %+v", "Faker ơi#VN2", got, want)
+	}
+}
+
+func TestDefaultScripts(t *testing.T) {
+	want := []string{ScriptKorean, ScriptJapanese, ScriptChinese}
+	if got := DefaultScripts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DefaultScripts() = %v, want %v", got, want)
+	}
+}
+
+func TestCanonicalKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"fullwidth Latin", "ＦＡＫＥＲ", "FAKER"},
+		{"fullwidth digits and tag", "Ｔ１＃ＫＲ１", "T1#KR1"},
+		{"halfwidth katakana", "ﾀﾞｲｽｹ", "ダイスケ"},
+		{"zero-width space", "페\u200b이커", "페이커"},
+		{"zero-width joiner and BOM", "\ufeff大\u200d魔王", "大魔王"},
+		{"Hangul filler", "\u3164Faker\u3164", "Faker"},
+		{"compatibility jamo kept", "ㅋㅋㅋ", "ㅋㅋㅋ"},
+		{"compatibility jamo not composed", "ㅎㅏㄴ", "ㅎㅏㄴ"},
+		{"conjoining jamo as compatibility jamo", "\u110f\u110f\u110f", "ㅋㅋㅋ"},
+		{"halfwidth jamo", "\uffbb\uffbb", "ㅋㅋ"},
+		{"conjoining jamo composed", "\u1112\u1161\u11ab", "한"},
+		{"Cyrillic lookalike in Latin", "Fаker", "Faker"},
+		{"Greek lookalike in Latin", "ΤΟΡ Lane", "TOP Lane"},
+		{"Cyrillic name kept", "ТОР", "ТОР"},
+		{"mixed Cyrillic kept", "Fаker Охотник", "Fаker Охотник"},
+		{"lookalike next to Han kept", "大魔王а", "大魔王а"},
+		{"already canonical", "Faker#KR1", "Faker#KR1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CanonicalKey(tt.input)
+			if got != tt.want {
+				t.Errorf("CanonicalKey(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if again := CanonicalKey(got); again != got {
+				t.Errorf("CanonicalKey isn't idempotent: %q became %q", got, again)
+			}
+		})
+	}
+}
+
+func TestSearchKey(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"페이커#KR1", "peikeo"},
+		{"大魔王Faker#NA1", "damowangfaker"},
+		{"Dà Mó Wáng", "damowang"},
+		{"ＦＡＫＥＲ", "faker"},
+		{"Hide on bush", "hideonbush"},
+		{"peikeo", "peikeo"},
+	}
+	for _, tt := range tests {
+		if got := SearchKey(tt.input); got != tt.want {
+			t.Errorf("SearchKey(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDetectScriptCanonical(t *testing.T) {
+	if got := DetectScript("ＦＡＫＥＲ"); got != ScriptLatin {
+		t.Errorf("DetectScript(fullwidth Latin) = %q, want %q", got, ScriptLatin)
+	}
+	if got := DetectScript("ㅋㅋㅋ"); got != ScriptKorean {
+		t.Errorf("DetectScript(compatibility jamo) = %q, want %q", got, ScriptKorean)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = registry[:len(registry)-1]
+		registryMu.Unlock()
+	})
+
+	Register(Script{
+		Name:     "greek",
+		Label:    "Greek",
+		Ranges:   unicode.Greek,
+		Romanize: func(text string, _ Style) string { return "greek:" + text },
+	})
+	if got := Transliterate("Αλφα#EUW"); got != "greek:Αλφα" {
+		t.Errorf("Transliterate with a registered script = %q", got)
+	}
+	if _, err := ParseScripts("korean, greek"); err != nil {
+		t.Errorf("ParseScripts: %v", err)
+	}
+	if _, err := ParseScripts("korean,klingon"); err == nil {
+		t.Error("ParseScripts accepted an unregistered script")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a script twice didn't panic")
+		}
+	}()
+	Register(Script{Name: ScriptKorean})
+}
@@ -0,0 +1,41 @@
+package transliteration
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// vietnameseLetters are the Latin letters only Vietnamese uses: ă, đ, ơ, ư,
+// ĩ, ũ and the Latin Extended Additional block of vowels with tone marks.
+var vietnameseLetters = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0102, Hi: 0x0103, Stride: 1}, // Ă ă
+		{Lo: 0x0110, Hi: 0x0111, Stride: 1}, // Đ đ
+		{Lo: 0x0128, Hi: 0x0129, Stride: 1}, // Ĩ ĩ
+		{Lo: 0x0168, Hi: 0x0169, Stride: 1}, // Ũ ũ
+		{Lo: 0x01A0, Hi: 0x01A1, Stride: 1}, // Ơ ơ
+		{Lo: 0x01AF, Hi: 0x01B0, Stride: 1}, // Ư ư
+		{Lo: 0x1EA0, Hi: 0x1EF9, Stride: 1}, // Ạ ... ỹ
+	},
+}
+
+// romanizeVietnamese drops Vietnamese diacritics, which is how the language
+// is commonly typed without a Vietnamese keyboard: "Nguyễn" becomes "Nguyen".
+func romanizeVietnamese(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == 'đ':
+			b.WriteByte('d')
+		case r == 'Đ':
+			b.WriteByte('D')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
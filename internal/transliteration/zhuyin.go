@@ -0,0 +1,92 @@
+package transliteration
+
+import "strings"
+
+// Zhuyin (Bopomofo), the phonetic script taught in Taiwan. A pinyin syllable
+// maps onto it as initial + rime once pinyin's spelling conventions are
+// undone: y and w stand in for a leading i, u or ü, ü is written u after j, q
+// and x, and iou, uei and uen drop their middle vowel after an initial.
+var (
+	zhuyinInitials = map[string]string{
+		"b": "ㄅ", "p": "ㄆ", "m": "ㄇ", "f": "ㄈ",
+		"d": "ㄉ", "t": "ㄊ", "n": "ㄋ", "l": "ㄌ",
+		"g": "ㄍ", "k": "ㄎ", "h": "ㄏ",
+		"j": "ㄐ", "q": "ㄑ", "x": "ㄒ",
+		"zh": "ㄓ", "ch": "ㄔ", "sh": "ㄕ", "r": "ㄖ",
+		"z": "ㄗ", "c": "ㄘ", "s": "ㄙ",
+	}
+	zhuyinRimes = map[string]string{
+		"":  "",
+		"a": "ㄚ", "o": "ㄛ", "e": "ㄜ", "ai": "ㄞ", "ei": "ㄟ", "ao": "ㄠ", "ou": "ㄡ",
+		"an": "ㄢ", "en": "ㄣ", "ang": "ㄤ", "eng": "ㄥ", "er": "ㄦ", "ong": "ㄨㄥ",
+		"i": "ㄧ", "ia": "ㄧㄚ", "io": "ㄧㄛ", "ie": "ㄧㄝ", "iai": "ㄧㄞ", "iao": "ㄧㄠ", "iou": "ㄧㄡ",
+		"ian": "ㄧㄢ", "in": "ㄧㄣ", "iang": "ㄧㄤ", "ing": "ㄧㄥ", "iong": "ㄩㄥ",
+		"u": "ㄨ", "ua": "ㄨㄚ", "uo": "ㄨㄛ", "uai": "ㄨㄞ", "uei": "ㄨㄟ",
+		"uan": "ㄨㄢ", "uen": "ㄨㄣ", "uang": "ㄨㄤ", "ueng": "ㄨㄥ",
+		"v": "ㄩ", "ve": "ㄩㄝ", "van": "ㄩㄢ", "vn": "ㄩㄣ",
+	}
+	zhuyinTones = [...]string{"", "ˊ", "ˇ", "ˋ"}
+)
+
+// toZhuyin writes a pinyin syllable, without its tone number, in Zhuyin with
+// its tone. The first tone is unmarked and the neutral tone is a dot written
+// before the syllable. Syllables with no Zhuyin spelling are returned as
+// pinyin.
+func toZhuyin(base string, tone int) string {
+	s := base
+	switch {
+	case strings.HasPrefix(s, "yu"):
+		s = "v" + s[2:]
+	case strings.HasPrefix(s, "yi"), strings.HasPrefix(s, "wu"):
+		s = s[1:]
+	case strings.HasPrefix(s, "y"):
+		s = "i" + s[1:]
+	case strings.HasPrefix(s, "w"):
+		s = "u" + s[1:]
+	}
+
+	initial := ""
+	for _, n := range []int{2, 1} {
+		if len(s) >= n {
+			if _, ok := zhuyinInitials[s[:n]]; ok {
+				initial = s[:n]
+				break
+			}
+		}
+	}
+	rime := s[len(initial):]
+
+	switch initial {
+	case "j", "q", "x":
+		if strings.HasPrefix(rime, "u") {
+			rime = "v" + rime[1:]
+		}
+	case "zh", "ch", "sh", "r", "z", "c", "s":
+		// The i in zhi, chi, ... is only there to make a syllable; Zhuyin
+		// writes the initial alone.
+		if rime == "i" {
+			rime = ""
+		}
+	}
+	switch rime {
+	case "iu":
+		rime = "iou"
+	case "ui":
+		rime = "uei"
+	case "un":
+		rime = "uen"
+	}
+
+	z, ok := zhuyinRimes[rime]
+	if !ok || (initial == "" && rime == "") {
+		return markTone(base, tone)
+	}
+	z = zhuyinInitials[initial] + z
+	switch {
+	case tone == 5:
+		return "˙" + z
+	case tone >= 1 && tone <= 4:
+		return z + zhuyinTones[tone-1]
+	}
+	return z
+}
@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/jobs"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// AdminHandler serves the moderation endpoints under /api/v1/admin. Every
+// change made through it is written to the admin audit log, in the same
+// transaction as the change where there is one.
+type AdminHandler struct {
+	repo        db.Repository
+	log         *slog.Logger
+	riverClient *river.Client[pgx.Tx]
+	banSecret   []byte
+}
+
+func NewAdminHandler(repo db.Repository, log *slog.Logger, riverClient *river.Client[pgx.Tx], banSecret []byte) *AdminHandler {
+	return &AdminHandler{repo: repo, log: log, riverClient: riverClient, banSecret: banSecret}
+}
+
+type updateTranslationRequest struct {
+	Translation *string `json:"translation,omitempty"`
+	Explanation *string `json:"explanation,omitempty"`
+}
+
+// UpdateTranslation edits a public translation's text or explanation; fields
+// left out of the body are kept.
+func (h *AdminHandler) UpdateTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 10*1024) // 10KB
+	var req updateTranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Translation == nil && req.Explanation == nil {
+		writeError(w, http.StatusBadRequest, "translation or explanation is required")
+		return
+	}
+	if req.Translation != nil && *req.Translation == "" {
+		writeError(w, http.StatusBadRequest, "translation must not be empty")
+		return
+	}
+
+	params := db.UpdatePublicTranslationParams{ID: id}
+	if req.Translation != nil {
+		params.Translation = sql.NullString{String: *req.Translation, Valid: true}
+	}
+	if req.Explanation != nil {
+		params.Explanation = sql.NullString{String: *req.Explanation, Valid: true}
+	}
+
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		before, err := txRepo.GetPublicTranslation(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if _, err := txRepo.UpdatePublicTranslation(r.Context(), params); err != nil {
+			return fmt.Errorf("updating translation: %w", err)
+		}
+		return recordAudit(r, txRepo, "translation.edit", id, map[string]any{
+			"before": updateTranslationRequest{Translation: &before.Translation, Explanation: nullStringPtr(before.Explanation)},
+			"after":  req,
+		})
+	})
+	h.writeResult(w, r, err, "translation not found", "updating translation", id)
+}
+
+// DeleteTranslation removes a public translation along with its votes and
+// feedback. The audit entry keeps its name and text.
+func (h *AdminHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		before, err := txRepo.GetPublicTranslation(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if _, err := txRepo.DeletePublicTranslation(r.Context(), id); err != nil {
+			return fmt.Errorf("deleting translation: %w", err)
+		}
+		return recordAudit(r, txRepo, "translation.delete", id, map[string]any{
+			"username":    before.Username,
+			"translation": before.Translation,
+		})
+	})
+	h.writeResult(w, r, err, "translation not found", "deleting translation", id)
+}
+
+// HideTranslation takes a translation off the site without deleting it, so
+// resubmitting the name doesn't bring it back.
+func (h *AdminHandler) HideTranslation(w http.ResponseWriter, r *http.Request) {
+	h.setHidden(w, r, true)
+}
+
+func (h *AdminHandler) UnhideTranslation(w http.ResponseWriter, r *http.Request) {
+	h.setHidden(w, r, false)
+}
+
+func (h *AdminHandler) setHidden(w http.ResponseWriter, r *http.Request, hidden bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	action := "translation.unhide"
+	if hidden {
+		action = "translation.hide"
+	}
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		n, err := txRepo.SetPublicTranslationHidden(r.Context(), db.SetPublicTranslationHiddenParams{ID: id, Hidden: hidden})
+		if err != nil {
+			return fmt.Errorf("setting hidden: %w", err)
+		}
+		if n == 0 {
+			return db.ErrNoRows
+		}
+		return recordAudit(r, txRepo, action, id, nil)
+	})
+	h.writeResult(w, r, err, "translation not found", "hiding translation", id)
+}
+
+type requeueResponse struct {
+	Status string `json:"status"`
+	JobID  int64  `json:"job_id"`
+}
+
+// RequeueTranslation enqueues a fresh translation of a public translation's
+// name, bypassing the translation cache. If one is already queued, its job is
+// returned instead.
+func (h *AdminHandler) RequeueTranslation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var res *rivertype.JobInsertResult
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		tx, err := pgxTx(txRepo)
+		if err != nil {
+			return err
+		}
+		t, err := txRepo.GetPublicTranslation(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		res, err = h.riverClient.InsertTx(r.Context(), tx, jobs.TranslateUsernameArgs{
+			Username:    t.Username,
+			Region:      t.Region,
+			Retranslate: true,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("enqueuing translation job: %w", err)
+		}
+		if res.UniqueSkippedAsDuplicate {
+			return nil
+		}
+		return recordAudit(r, txRepo, "translation.requeue", id, map[string]any{"job_id": res.Job.ID})
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			writeError(w, http.StatusNotFound, "translation not found")
+			return
+		}
+		h.log.ErrorContext(r.Context(), "requeueing translation", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if res.UniqueSkippedAsDuplicate {
+		writeJSON(w, http.StatusOK, requeueResponse{Status: "already_queued", JobID: res.Job.ID})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, requeueResponse{Status: "queued", JobID: res.Job.ID})
+}
+
+// RetryJob re-runs a translation job that failed or was discarded, by its
+// River job ID.
+func (h *AdminHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var job *rivertype.JobRow
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		tx, err := pgxTx(txRepo)
+		if err != nil {
+			return err
+		}
+		if job, err = h.riverClient.JobRetryTx(r.Context(), tx, id); err != nil {
+			return err
+		}
+		return recordAudit(r, txRepo, "job.retry", id, map[string]any{"kind": job.Kind})
+	})
+	if err != nil {
+		if errors.Is(err, river.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		h.log.ErrorContext(r.Context(), "retrying job", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusAccepted, requeueResponse{Status: string(job.State), JobID: job.ID})
+}
+
+type createBanRequest struct {
+	IP        string `json:"ip"`
+	VisitorID string `json:"visitor_id"`
+	Reason    string `json:"reason"`
+}
+
+type banResponse struct {
+	ID        int64  `json:"id"`
+	IPKey     string `json:"ip_key,omitempty"`
+	VisitorID string `json:"visitor_id,omitempty"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"created_at"`
+}
+
+func toBanResponse(b db.VoteBan) banResponse {
+	return banResponse{
+		ID:        b.ID,
+		IPKey:     b.IpKey.String,
+		VisitorID: b.VisitorID.String,
+		Reason:    b.Reason,
+		CreatedAt: b.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (h *AdminHandler) ListBans(w http.ResponseWriter, r *http.Request) {
+	bans, err := h.repo.ListVoteBans(r.Context())
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "listing vote bans", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	data := make([]banResponse, len(bans))
+	for i, b := range bans {
+		data[i] = toBanResponse(b)
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Data []banResponse `json:"data"`
+	}{Data: data})
+}
+
+// CreateBan bars a voter from voting, by exactly one of ip or visitor_id. The
+// IP address itself isn't stored: the ban holds its ipKey, which admitVoter
+// computes for every vote.
+func (h *AdminHandler) CreateBan(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 10*1024) // 10KB
+	var req createBanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if (req.IP == "") == (req.VisitorID == "") {
+		writeError(w, http.StatusBadRequest, "exactly one of ip or visitor_id is required")
+		return
+	}
+	var key string
+	if req.IP != "" {
+		if _, err := netip.ParseAddr(req.IP); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid ip")
+			return
+		}
+		key = ipKey(h.banSecret, req.IP)
+	}
+	if len(req.Reason) > 500 {
+		writeError(w, http.StatusBadRequest, "reason must be 500 characters or fewer")
+		return
+	}
+
+	var ban db.VoteBan
+	err := h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		var err error
+		ban, err = txRepo.CreateVoteBan(r.Context(), db.CreateVoteBanParams{
+			IpKey:     sql.NullString{String: key, Valid: key != ""},
+			VisitorID: sql.NullString{String: req.VisitorID, Valid: req.VisitorID != ""},
+			Reason:    req.Reason,
+		})
+		if err != nil {
+			return fmt.Errorf("creating vote ban: %w", err)
+		}
+		return recordAudit(r, txRepo, "ban.create", ban.ID, toBanResponse(ban))
+	})
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "creating vote ban", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toBanResponse(ban))
+}
+
+func (h *AdminHandler) DeleteBan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		n, err := txRepo.DeleteVoteBan(r.Context(), id)
+		if err != nil {
+			return fmt.Errorf("deleting vote ban: %w", err)
+		}
+		if n == 0 {
+			return db.ErrNoRows
+		}
+		return recordAudit(r, txRepo, "ban.delete", id, nil)
+	})
+	h.writeResult(w, r, err, "ban not found", "deleting vote ban", id)
+}
+
+type auditEntryResponse struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	TargetID  *int64          `json:"target_id,omitempty"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// ListAudit returns the admin audit log, newest first.
+func (h *AdminHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 25
+	}
+	offset := (page - 1) * limit
+
+	total, err := h.repo.CountAdminAuditLog(r.Context())
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "counting audit log", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	entries, err := h.repo.ListAdminAuditLog(r.Context(), db.ListAdminAuditLogParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "listing audit log", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	data := make([]auditEntryResponse, len(entries))
+	for i, e := range entries {
+		data[i] = auditEntryResponse{
+			ID:        e.ID,
+			Actor:     e.Actor,
+			Action:    e.Action,
+			Details:   e.Details,
+			CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		}
+		if e.TargetID.Valid {
+			data[i].TargetID = &e.TargetID.Int64
+		}
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Data       []auditEntryResponse `json:"data"`
+		Pagination paginationMeta       `json:"pagination"`
+	}{
+		Data: data,
+		Pagination: paginationMeta{
+			Page:  page,
+			Limit: limit,
+			Total: total,
+		},
+	})
+}
+
+// writeResult answers an admin change with 204, or with 404 when what it
+// changed doesn't exist.
+func (h *AdminHandler) writeResult(w http.ResponseWriter, r *http.Request, err error, notFound, action string, id int64) {
+	if err != nil {
+		if db.IsNoRows(err) {
+			writeError(w, http.StatusNotFound, notFound)
+			return
+		}
+		h.log.ErrorContext(r.Context(), action, "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pgxTx returns the transaction a repository passed to a WithTx callback runs
+// in, for River to insert or retry jobs in the same transaction as the audit
+// entry.
+func pgxTx(txRepo db.Repository) (pgx.Tx, error) {
+	txer, ok := txRepo.(interface{ Tx() pgx.Tx })
+	if !ok || txer.Tx() == nil {
+		return nil, errors.New("repository isn't running in a Postgres transaction")
+	}
+	return txer.Tx(), nil
+}
+
+// recordAudit writes an audit log entry for an admin action on targetID, with
+// details marshalled to JSON when non-nil.
+func recordAudit(r *http.Request, repo db.Repository, action string, targetID int64, details any) error {
+	var raw []byte
+	if details != nil {
+		var err error
+		if raw, err = json.Marshal(details); err != nil {
+			return fmt.Errorf("marshalling audit details: %w", err)
+		}
+	}
+	err := repo.CreateAdminAuditLog(r.Context(), db.CreateAdminAuditLogParams{
+		Actor:    adminActor(r),
+		Action:   action,
+		TargetID: sql.NullInt64{Int64: targetID, Valid: true},
+		Details:  raw,
+	})
+	if err != nil {
+		return fmt.Errorf("recording audit entry: %w", err)
+	}
+	return nil
+}
+
+// adminActor names who made an admin request: the basic auth user, or
+// "api-key" for requests authenticated by key.
+func adminActor(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+	if r.Header.Get("X-API-Key") != "" {
+		return "api-key"
+	}
+	return "unknown"
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/metrics"
+	"github.com/samber/lo"
+)
+
+// AlternativeHandler serves visitors' suggested replacements for a
+// translation and the votes on them. Voting works as on translations: one vote
+// per visitor cookie, counted against the same per-IP limit. The promotion of
+// alternatives that lead for long enough happens in web.PromoteWorker.
+type AlternativeHandler struct {
+	repo          db.Repository
+	log           *slog.Logger
+	maxVotesPerIP int
+	banSecret     []byte
+}
+
+func NewAlternativeHandler(repo db.Repository, log *slog.Logger, maxVotesPerIP int, banSecret []byte) *AlternativeHandler {
+	return &AlternativeHandler{repo: repo, log: log, maxVotesPerIP: maxVotesPerIP, banSecret: banSecret}
+}
+
+type createAlternativeRequest struct {
+	Translation string `json:"translation"`
+	Explanation string `json:"explanation"`
+}
+
+type alternativeResponse struct {
+	ID            int64   `json:"id"`
+	TranslationID int64   `json:"translation_id"`
+	Translation   string  `json:"translation"`
+	Explanation   *string `json:"explanation,omitempty"`
+	Upvotes       int32   `json:"upvotes"`
+	Downvotes     int32   `json:"downvotes"`
+	LeadingSince  string  `json:"leading_since,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+type revisionResponse struct {
+	ID            int64   `json:"id"`
+	Translation   string  `json:"translation"`
+	Explanation   *string `json:"explanation,omitempty"`
+	AlternativeID *int64  `json:"alternative_id,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+func toAlternativeResponse(a db.TranslationAlternative) alternativeResponse {
+	resp := alternativeResponse{
+		ID:            a.ID,
+		TranslationID: a.TranslationID,
+		Translation:   a.Translation,
+		Upvotes:       a.Upvotes,
+		Downvotes:     a.Downvotes,
+		CreatedAt:     a.CreatedAt.Format(time.RFC3339),
+	}
+	if a.Explanation.Valid {
+		resp.Explanation = &a.Explanation.String
+	}
+	if a.LeadingSince.Valid {
+		resp.LeadingSince = a.LeadingSince.Time.Format(time.RFC3339)
+	}
+	return resp
+}
+
+// List returns the translation's alternatives that haven't been promoted, best
+// voted first.
+func (h *AlternativeHandler) List(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.visibleTranslation(w, r)
+	if !ok {
+		return
+	}
+
+	alternatives, err := h.repo.ListTranslationAlternatives(r.Context(), t.ID)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "listing alternatives", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Data []alternativeResponse `json:"data"`
+	}{Data: lo.Map(alternatives, func(a db.TranslationAlternative, _ int) alternativeResponse {
+		return toAlternativeResponse(a)
+	})})
+}
+
+// Create suggests an alternative translation. Each visitor gets one
+// suggestion per translation, and banned voters get none.
+func (h *AlternativeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.visibleTranslation(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 10*1024) // 10KB
+	var req createAlternativeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	req.Translation = strings.TrimSpace(req.Translation)
+	req.Explanation = strings.TrimSpace(req.Explanation)
+	if req.Translation == "" {
+		writeError(w, http.StatusBadRequest, "translation is required")
+		return
+	}
+	if len(req.Translation) > 200 {
+		writeError(w, http.StatusBadRequest, "translation must be 200 characters or fewer")
+		return
+	}
+	if len(req.Explanation) > 1000 {
+		writeError(w, http.StatusBadRequest, "explanation must be 1000 characters or fewer")
+		return
+	}
+	if req.Translation == t.Translation {
+		writeError(w, http.StatusBadRequest, "alternative must differ from the current translation")
+		return
+	}
+
+	ipHash, visitorID, ok := admitVoter(w, r, h.repo, h.log, h.maxVotesPerIP, h.banSecret)
+	if !ok {
+		return
+	}
+
+	alt, err := h.repo.CreateTranslationAlternative(r.Context(), db.CreateTranslationAlternativeParams{
+		TranslationID: t.ID,
+		Translation:   req.Translation,
+		Explanation:   sql.NullString{String: req.Explanation, Valid: req.Explanation != ""},
+		IpHash:        ipHash,
+		VisitorID:     visitorID,
+	})
+	if db.IsNoRows(err) {
+		writeError(w, http.StatusConflict, "you already suggested an alternative for this translation")
+		return
+	}
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "creating alternative", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "alternative suggested", "translationID", t.ID, "alternativeID", alt.ID)
+	writeJSON(w, http.StatusCreated, toAlternativeResponse(alt))
+}
+
+// Vote casts, switches or (voting the same way again) withdraws the visitor's
+// vote on an alternative, like VoteHandler.Vote does for translations.
+func (h *AlternativeHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	alternativeID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1024) // 1KB
+	var req voteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Vote != 1 && req.Vote != -1 {
+		writeError(w, http.StatusBadRequest, "vote must be 1 or -1")
+		return
+	}
+
+	alt, err := h.repo.GetTranslationAlternative(r.Context(), alternativeID)
+	if err != nil {
+		if db.IsNoRows(err) {
+			writeError(w, http.StatusNotFound, "alternative not found")
+			return
+		}
+		h.log.ErrorContext(r.Context(), "getting alternative", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if alt.PromotedAt.Valid {
+		writeError(w, http.StatusConflict, "alternative is already the translation")
+		return
+	}
+	// Alternatives of a hidden translation are off the site along with it.
+	t, err := h.repo.GetPublicTranslation(r.Context(), alt.TranslationID)
+	if err != nil && !db.IsNoRows(err) {
+		h.log.ErrorContext(r.Context(), "getting translation", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if err != nil || t.Hidden {
+		writeError(w, http.StatusNotFound, "alternative not found")
+		return
+	}
+
+	ipHash, visitorID, ok := admitVoter(w, r, h.repo, h.log, h.maxVotesPerIP, h.banSecret)
+	if !ok {
+		return
+	}
+
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		existing, err := txRepo.GetAlternativeVote(r.Context(), db.GetAlternativeVoteParams{
+			AlternativeID: alternativeID,
+			VisitorID:     visitorID,
+		})
+		if err != nil && !db.IsNoRows(err) {
+			return fmt.Errorf("getting existing vote: %w", err)
+		}
+
+		adjust := db.AdjustTranslationAlternativeVotesParams{ID: alternativeID}
+		count := func(vote int16, n int32) {
+			if vote == 1 {
+				adjust.Upvotes += n
+			} else {
+				adjust.Downvotes += n
+			}
+		}
+		voted := err == nil
+		if voted && existing.Vote == req.Vote {
+			if _, err := txRepo.DeleteAlternativeVote(r.Context(), db.DeleteAlternativeVoteParams{
+				AlternativeID: alternativeID,
+				VisitorID:     visitorID,
+			}); err != nil {
+				return fmt.Errorf("deleting vote: %w", err)
+			}
+			count(req.Vote, -1)
+		} else {
+			if _, err := txRepo.UpsertAlternativeVote(r.Context(), db.UpsertAlternativeVoteParams{
+				AlternativeID: alternativeID,
+				IpHash:        ipHash,
+				VisitorID:     visitorID,
+				Vote:          req.Vote,
+			}); err != nil {
+				return fmt.Errorf("upserting vote: %w", err)
+			}
+			count(req.Vote, 1)
+			if voted {
+				count(existing.Vote, -1)
+			}
+		}
+
+		alt, err = txRepo.AdjustTranslationAlternativeVotes(r.Context(), adjust)
+		return err
+	})
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "processing alternative vote", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if req.Vote == 1 {
+		metrics.VotesTotal.WithLabelValues("up").Inc()
+	} else {
+		metrics.VotesTotal.WithLabelValues("down").Inc()
+	}
+
+	writeJSON(w, http.StatusOK, voteResponse{
+		Upvotes:   alt.Upvotes,
+		Downvotes: alt.Downvotes,
+	})
+}
+
+// Revisions returns the earlier versions of a translation, newest first.
+func (h *AlternativeHandler) Revisions(w http.ResponseWriter, r *http.Request) {
+	t, ok := h.visibleTranslation(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.repo.ListTranslationRevisions(r.Context(), t.ID)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "listing revisions", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	data := make([]revisionResponse, len(revisions))
+	for i, rev := range revisions {
+		data[i] = revisionResponse{
+			ID:          rev.ID,
+			Translation: rev.Translation,
+			CreatedAt:   rev.CreatedAt.Format(time.RFC3339),
+		}
+		if rev.Explanation.Valid {
+			data[i].Explanation = &rev.Explanation.String
+		}
+		if rev.AlternativeID.Valid {
+			data[i].AlternativeID = &rev.AlternativeID.Int64
+		}
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Data []revisionResponse `json:"data"`
+	}{Data: data})
+}
+
+// visibleTranslation loads the translation named by the {id} path value,
+// writing a 404 for missing and hidden ones.
+func (h *AlternativeHandler) visibleTranslation(w http.ResponseWriter, r *http.Request) (db.PublicTranslation, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return db.PublicTranslation{}, false
+	}
+
+	t, err := h.repo.GetPublicTranslation(r.Context(), id)
+	if err == nil && t.Hidden {
+		err = db.ErrNoRows
+	}
+	if err != nil {
+		if db.IsNoRows(err) {
+			writeError(w, http.StatusNotFound, "translation not found")
+			return db.PublicTranslation{}, false
+		}
+		h.log.ErrorContext(r.Context(), "getting translation", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return db.PublicTranslation{}, false
+	}
+	return t, true
+}
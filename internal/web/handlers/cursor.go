@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// listCursor marks where a page of a translation list ended, so the next
+// page resumes after that row however many translations arrive in between.
+// The newest-first list is keyed by creation time and the others by the score
+// they rank by, with the ID breaking ties.
+type listCursor struct {
+	Sort      string    `json:"s"`
+	ID        int64     `json:"i"`
+	Score     float64   `json:"k,omitempty"`
+	CreatedAt time.Time `json:"t,omitzero"`
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encode returns c as the opaque token clients pass back as ?cursor=.
+func (c listCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor parses a token from encode, which must have come from a list
+// in sort.
+func decodeCursor(token, sort string) (listCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return listCursor{}, errInvalidCursor
+	}
+	var c listCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort || c.ID <= 0 {
+		return listCursor{}, errInvalidCursor
+	}
+	return c, nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -38,6 +39,7 @@ type adminFeedbackRow struct {
 	Username      string `json:"username"`
 	Translation   string `json:"translation"`
 	CreatedAt     string `json:"created_at"`
+	ResolvedAt    string `json:"resolved_at,omitempty"`
 }
 
 func (h *FeedbackHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -84,9 +86,21 @@ func (h *FeedbackHandler) Create(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// List returns open feedback, newest first, or resolved feedback with
+// ?status=resolved.
 func (h *FeedbackHandler) List(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 
+	var resolved bool
+	switch q.Get("status") {
+	case "", "open":
+	case "resolved":
+		resolved = true
+	default:
+		writeError(w, http.StatusBadRequest, "status must be open or resolved")
+		return
+	}
+
 	page, _ := strconv.Atoi(q.Get("page"))
 	if page < 1 {
 		page = 1
@@ -97,7 +111,7 @@ func (h *FeedbackHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 	offset := (page - 1) * limit
 
-	total, err := h.repo.CountPublicFeedback(r.Context())
+	total, err := h.repo.CountPublicFeedback(r.Context(), resolved)
 	if err != nil {
 		h.log.ErrorContext(r.Context(), "counting feedback", "error", err)
 		writeError(w, http.StatusInternalServerError, "internal error")
@@ -105,8 +119,9 @@ func (h *FeedbackHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rows, err := h.repo.ListPublicFeedback(r.Context(), db.ListPublicFeedbackParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
+		Resolved: resolved,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
 	})
 	if err != nil {
 		h.log.ErrorContext(r.Context(), "listing feedback", "error", err)
@@ -124,6 +139,9 @@ func (h *FeedbackHandler) List(w http.ResponseWriter, r *http.Request) {
 			Translation:   row.Translation,
 			CreatedAt:     row.CreatedAt.Format(time.RFC3339),
 		}
+		if row.ResolvedAt.Valid {
+			data[i].ResolvedAt = row.ResolvedAt.Time.Format(time.RFC3339)
+		}
 	}
 
 	writeJSON(w, http.StatusOK, struct {
@@ -138,3 +156,35 @@ func (h *FeedbackHandler) List(w http.ResponseWriter, r *http.Request) {
 		},
 	})
 }
+
+// Resolve marks feedback as dealt with, taking it off the open list.
+// Resolving it again is a no-op.
+func (h *FeedbackHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	err = h.repo.WithTx(r.Context(), func(txRepo db.Repository) error {
+		n, err := txRepo.ResolvePublicFeedback(r.Context(), id)
+		if err != nil {
+			return fmt.Errorf("resolving feedback: %w", err)
+		}
+		if n == 0 {
+			return db.ErrNoRows
+		}
+		return recordAudit(r, txRepo, "feedback.resolve", id, nil)
+	})
+	if err != nil {
+		if db.IsNoRows(err) {
+			writeError(w, http.StatusNotFound, "feedback not found")
+			return
+		}
+		h.log.ErrorContext(r.Context(), "resolving feedback", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+)
+
+// feedSize is how many of the newest translations a feed carries.
+const feedSize = 50
+
+// FeedHandler serves the newest translations as Atom and JSON Feed documents
+// for feed readers and RSS bots. Both take the region and language filters of
+// the translation listing.
+type FeedHandler struct {
+	repo    db.Repository
+	log     *slog.Logger
+	siteURL string
+}
+
+func NewFeedHandler(repo db.Repository, log *slog.Logger, siteURL string) *FeedHandler {
+	return &FeedHandler{repo: repo, log: log, siteURL: strings.TrimSuffix(siteURL, "/")}
+}
+
+// feedEntry is a translation as both feed formats present it.
+type feedEntry struct {
+	id           int64
+	username     string
+	romanization string
+	translation  string
+	explanation  string
+	rank         string
+	region       string
+	language     string
+	created      time.Time
+}
+
+func (e feedEntry) title() string {
+	return e.username + ": " + e.translation
+}
+
+// html lists the entry's fields for readers that only show the content.
+func (e feedEntry) html() string {
+	var b strings.Builder
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "<p><strong>%s:</strong> %s</p>", name, html.EscapeString(value))
+		}
+	}
+	field("Name", e.username)
+	field("Romanization", e.romanization)
+	field("Translation", e.translation)
+	field("Explanation", e.explanation)
+	field("Rank", e.rank)
+	field("Region", e.region)
+	return b.String()
+}
+
+// entries loads the newest visible translations matching the request's
+// filters, newest first.
+func (h *FeedHandler) entries(r *http.Request) ([]feedEntry, error) {
+	q := r.URL.Query()
+	translations, err := h.repo.ListPublicTranslationsNew(r.Context(), db.ListPublicTranslationsNewParams{
+		Region:   q.Get("region"),
+		Language: q.Get("language"),
+		Limit:    feedSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]feedEntry, len(translations))
+	for i, t := range translations {
+		entries[i] = feedEntry{
+			id:           t.ID,
+			username:     t.Username,
+			romanization: transliteration.TransliterateStyle(t.Username, transliteration.StyleTones),
+			translation:  t.Translation,
+			explanation:  t.Explanation.String,
+			rank:         t.Rank.String,
+			region:       t.Region,
+			language:     t.Language,
+			created:      t.CreatedAt,
+		}
+	}
+	return entries, nil
+}
+
+// feedTitle names the feed after its filters.
+func feedTitle(r *http.Request) string {
+	q := r.URL.Query()
+	var filters []string
+	for _, f := range []string{q.Get("region"), q.Get("language")} {
+		if f != "" {
+			filters = append(filters, f)
+		}
+	}
+	if len(filters) == 0 {
+		return "League of Ren translations"
+	}
+	return "League of Ren translations (" + strings.Join(filters, ", ") + ")"
+}
+
+// feedUpdated is when the newest entry was created. An empty feed reports the
+// Unix epoch, so that its ETag stays stable too.
+func feedUpdated(entries []feedEntry) time.Time {
+	if len(entries) == 0 {
+		return time.Unix(0, 0).UTC()
+	}
+	return entries[0].created
+}
+
+// entryURL is the website page showing translation id, which also serves as
+// the entry's ID.
+func (h *FeedHandler) entryURL(id int64) string {
+	return h.siteURL + "/?translation=" + strconv.FormatInt(id, 10)
+}
+
+// apiURL is where the translation's full data is in the website's API.
+func (h *FeedHandler) apiURL(id int64) string {
+	return h.siteURL + "/api/v1/translations/" + strconv.FormatInt(id, 10)
+}
+
+// feedFilters returns the request's region and language filters, the only
+// parameters the feeds take, so that other parameters or their order don't
+// change the feed's ID.
+func feedFilters(r *http.Request) url.Values {
+	q := r.URL.Query()
+	filters := url.Values{}
+	for _, key := range []string{"region", "language"} {
+		if v := q.Get(key); v != "" {
+			filters.Set(key, v)
+		}
+	}
+	return filters
+}
+
+// withQuery appends the encoded query to u, if there is one.
+func withQuery(u string, query url.Values) string {
+	if len(query) == 0 {
+		return u
+	}
+	return u + "?" + query.Encode()
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Link       atomLink       `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Atom serves the feed at /feeds/translations.atom.
+func (h *FeedHandler) Atom(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.entries(r)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "listing feed translations", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	filters := feedFilters(r)
+	feedURL := withQuery(h.siteURL+r.URL.Path, filters)
+	feed := atomFeed{
+		ID:      feedURL,
+		Title:   feedTitle(r),
+		Updated: feedUpdated(entries).Format(time.RFC3339),
+		Author:  atomAuthor{Name: "League of Ren"},
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: feedURL},
+			{Rel: "alternate", Type: "text/html", Href: withQuery(h.siteURL+"/", filters)},
+		},
+	}
+	for _, e := range entries {
+		created := e.created.Format(time.RFC3339)
+		entry := atomEntry{
+			ID:        h.entryURL(e.id),
+			Title:     e.title(),
+			Published: created,
+			Updated:   created,
+			Link:      atomLink{Rel: "alternate", Type: "text/html", Href: h.entryURL(e.id)},
+			Content:   atomText{Type: "html", Body: e.html()},
+		}
+		for _, term := range []string{e.region, e.language} {
+			if term != "" {
+				entry.Categories = append(entry.Categories, atomCategory{Term: term})
+			}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(feed); err != nil {
+		h.log.ErrorContext(r.Context(), "encoding atom feed", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeFeed(w, r, "application/atom+xml; charset=utf-8", buf.Bytes())
+}
+
+// jsonFeed is a JSON Feed 1.1 document (https://www.jsonfeed.org/version/1.1/).
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	DatePublished string   `json:"date_published"`
+	Tags          []string `json:"tags,omitempty"`
+	// Translation carries the entry's fields for bots, as a JSON Feed
+	// extension.
+	Translation feedTranslation `json:"_translation"`
+}
+
+type feedTranslation struct {
+	About        string `json:"about"`
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	Romanization string `json:"romanization,omitempty"`
+	Translation  string `json:"translation"`
+	Explanation  string `json:"explanation,omitempty"`
+	Rank         string `json:"rank,omitempty"`
+	Region       string `json:"region"`
+	Language     string `json:"language"`
+}
+
+// JSON serves the feed at /feeds/translations.json.
+func (h *FeedHandler) JSON(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.entries(r)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "listing feed translations", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	filters := feedFilters(r)
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feedTitle(r),
+		HomePageURL: withQuery(h.siteURL+"/", filters),
+		FeedURL:     withQuery(h.siteURL+r.URL.Path, filters),
+		Items:       make([]jsonFeedItem, len(entries)),
+	}
+	for i, e := range entries {
+		feed.Items[i] = jsonFeedItem{
+			ID:            h.entryURL(e.id),
+			URL:           h.entryURL(e.id),
+			Title:         e.title(),
+			ContentHTML:   e.html(),
+			DatePublished: e.created.Format(time.RFC3339),
+			Translation: feedTranslation{
+				About:        h.apiURL(e.id),
+				ID:           e.id,
+				Username:     e.username,
+				Romanization: e.romanization,
+				Translation:  e.translation,
+				Explanation:  e.explanation,
+				Rank:         e.rank,
+				Region:       e.region,
+				Language:     e.language,
+			},
+		}
+		for _, tag := range []string{e.region, e.language} {
+			if tag != "" {
+				feed.Items[i].Tags = append(feed.Items[i].Tags, tag)
+			}
+		}
+	}
+
+	body, err := json.Marshal(feed)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "encoding json feed", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeFeed(w, r, "application/feed+json; charset=utf-8", body)
+}
+
+// writeFeed writes body with an ETag of its hash, or a 304 when the client's
+// If-None-Match already has it. Votes don't appear in the feeds, so the tag
+// only changes with new, edited or removed translations.
+func writeFeed(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// etagMatches reports whether an If-None-Match header names etag, comparing
+// weakly as RFC 9110 asks.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
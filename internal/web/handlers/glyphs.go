@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/samber/lo"
+)
+
+// GlyphHandler serves the per-character breakdown of a translated name, from
+// the offline dictionary rather than the LLM.
+type GlyphHandler struct {
+	repo db.Repository
+	log  *slog.Logger
+	dict *dictionary.Dictionary
+}
+
+func NewGlyphHandler(repo db.Repository, log *slog.Logger, dict *dictionary.Dictionary) *GlyphHandler {
+	return &GlyphHandler{repo: repo, log: log, dict: dict}
+}
+
+type glyphResponse struct {
+	Char         string   `json:"char"`
+	Pinyin       string   `json:"pinyin,omitempty"`
+	Eum          string   `json:"eum,omitempty"`
+	EumRomanized string   `json:"eum_romanized,omitempty"`
+	On           []string `json:"on,omitempty"`
+	Kun          []string `json:"kun,omitempty"`
+	Meanings     []string `json:"meanings,omitempty"`
+}
+
+type glyphsResponse struct {
+	TranslationID int64           `json:"translation_id"`
+	Username      string          `json:"username"`
+	Glyphs        []glyphResponse `json:"glyphs"`
+}
+
+// Get returns a glyph for each Han character in the translation's gameName.
+// Names without any have an empty list.
+func (h *GlyphHandler) Get(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	t, err := h.repo.GetPublicTranslation(r.Context(), id)
+	if err == nil && t.Hidden {
+		err = db.ErrNoRows
+	}
+	if err != nil {
+		if db.IsNoRows(err) {
+			writeError(w, http.StatusNotFound, "translation not found")
+			return
+		}
+		h.log.ErrorContext(r.Context(), "getting translation", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	gameName, _, _ := strings.Cut(t.Username, "#")
+	glyphs := lo.Map(h.dict.Glyphs(gameName), func(g dictionary.Glyph, _ int) glyphResponse {
+		return glyphResponse{
+			Char:         g.Char,
+			Pinyin:       g.Pinyin,
+			Eum:          g.Eum,
+			EumRomanized: transliteration.Transliterate(g.Eum),
+			On:           g.On,
+			Kun:          g.Kun,
+			Meanings:     g.Meanings,
+		}
+	})
+	if glyphs == nil {
+		glyphs = []glyphResponse{}
+	}
+	writeJSON(w, http.StatusOK, glyphsResponse{
+		TranslationID: t.ID,
+		Username:      t.Username,
+		Glyphs:        glyphs,
+	})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/samber/lo"
+)
+
+// maxRankHistory caps how many of a player's most recent ranks a profile
+// charts.
+const maxRankHistory = 500
+
+// PlayerHandler serves the profiles of translated players: their latest stats
+// and the solo queue rank history the worker records.
+type PlayerHandler struct {
+	repo db.Repository
+	log  *slog.Logger
+}
+
+func NewPlayerHandler(repo db.Repository, log *slog.Logger) *PlayerHandler {
+	return &PlayerHandler{repo: repo, log: log}
+}
+
+type rankResponse struct {
+	Tier         string `json:"tier"`
+	Division     string `json:"division"`
+	LeaguePoints int32  `json:"league_points"`
+	RecordedAt   string `json:"recorded_at"`
+}
+
+type playerResponse struct {
+	Username     string               `json:"username"`
+	Region       string               `json:"region"`
+	Rank         *string              `json:"rank,omitempty"`
+	TopChampions []string             `json:"top_champions,omitempty"`
+	FirstSeen    string               `json:"first_seen"`
+	LastUpdated  string               `json:"last_updated"`
+	Translation  *translationResponse `json:"translation,omitempty"`
+	// History is oldest first, for charting.
+	History []rankResponse `json:"history"`
+	Peak    *rankResponse  `json:"peak,omitempty"`
+}
+
+func toRankResponse(r db.PlayerRank) rankResponse {
+	return rankResponse{
+		Tier:         r.Tier,
+		Division:     r.Division,
+		LeaguePoints: r.LeaguePoints,
+		RecordedAt:   r.RecordedAt.Format(time.RFC3339),
+	}
+}
+
+// Get returns the player's profile with their translation, rank history and
+// peak rank. A player whose translation a moderator hid is not found.
+func (h *PlayerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+
+	style, ok := transliteration.ParseStyle(r.URL.Query().Get("romanization"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "romanization must be one of plain, tones or zhuyin")
+		return
+	}
+
+	player, err := h.repo.GetPlayer(r.Context(), username)
+	if err != nil {
+		if db.IsNoRows(err) {
+			writeError(w, http.StatusNotFound, "player not found")
+			return
+		}
+		h.log.ErrorContext(r.Context(), "getting player", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := playerResponse{
+		Username:    player.Username,
+		Region:      player.Region,
+		FirstSeen:   player.FirstSeen.Format(time.RFC3339),
+		LastUpdated: player.LastUpdated.Format(time.RFC3339),
+	}
+	if player.Rank.Valid {
+		resp.Rank = &player.Rank.String
+	}
+	if player.TopChampions.Valid && player.TopChampions.String != "" {
+		var champs []string
+		if err := json.Unmarshal([]byte(player.TopChampions.String), &champs); err == nil {
+			resp.TopChampions = champs
+		}
+	}
+
+	t, err := h.repo.GetPublicTranslationByUsername(r.Context(), player.Username)
+	switch {
+	case err == nil && t.Hidden:
+		writeError(w, http.StatusNotFound, "player not found")
+		return
+	case err == nil:
+		translation := toTranslationResponse(t, style)
+		resp.Translation = &translation
+	case !db.IsNoRows(err):
+		h.log.ErrorContext(r.Context(), "getting translation", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	history, err := h.repo.ListPlayerRankHistory(r.Context(), db.ListPlayerRankHistoryParams{
+		PlayerUsername: player.Username,
+		Limit:          maxRankHistory,
+	})
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "listing rank history", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	slices.Reverse(history)
+	resp.History = lo.Map(history, func(rank db.PlayerRank, _ int) rankResponse { return toRankResponse(rank) })
+
+	peak, err := h.repo.GetPeakPlayerRank(r.Context(), player.Username)
+	switch {
+	case err == nil:
+		p := toRankResponse(peak)
+		resp.Peak = &p
+	case !db.IsNoRows(err):
+		h.log.ErrorContext(r.Context(), "getting peak rank", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
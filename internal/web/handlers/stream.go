@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/metrics"
+	"github.com/jusunglee/leagueofren/internal/web/stream"
+)
+
+const (
+	// heartbeatInterval keeps idle streams from being closed by proxies.
+	heartbeatInterval = 15 * time.Second
+
+	// streamRetry is how long browsers wait before reconnecting.
+	streamRetry = 5 * time.Second
+)
+
+type StreamHandler struct {
+	hub *stream.Hub
+	log *slog.Logger
+}
+
+func NewStreamHandler(hub *stream.Hub, log *slog.Logger) *StreamHandler {
+	return &StreamHandler{hub: hub, log: log}
+}
+
+// Stream serves translation events as Server-Sent Events. A client that
+// reconnects with a Last-Event-ID header first gets the events it missed,
+// as far back as stream.Retention. That includes events with lower IDs that
+// committed after the one it last saw, but none it already has.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// The server's write timeout would otherwise end every stream.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.log.ErrorContext(r.Context(), "clearing stream write deadline", "error", err)
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	// Subscribe before replaying so that nothing published in between is
+	// lost; events in both are sent once, as are events the Hub replays.
+	events, unsubscribe := h.hub.Subscribe()
+	defer unsubscribe()
+
+	var missed []stream.Event
+	if lastID, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64); err == nil && lastID > 0 {
+		missed, err = h.hub.Replay(r.Context(), lastID)
+		if err != nil {
+			h.log.ErrorContext(r.Context(), "replaying stream events", "error", err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+	}
+
+	metrics.StreamConnections.Inc()
+	defer metrics.StreamConnections.Dec()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	seen := stream.NewSeen()
+	for _, e := range missed {
+		if seen.Add(e.ID) {
+			writeEvent(w, e)
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				// Fell behind or shutting down; the client reconnects and
+				// resumes from its last event.
+				return
+			}
+			if !seen.Add(e.ID) {
+				continue
+			}
+			writeEvent(w, e)
+		case <-heartbeat.C:
+			io.WriteString(w, ": heartbeat\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, e stream.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/web/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamRepo implements the stream event methods of db.Repository in memory.
+type streamRepo struct {
+	db.Repository
+	events []db.StreamEvent
+}
+
+func (r *streamRepo) ListStreamEventsAfter(_ context.Context, arg db.ListStreamEventsAfterParams) ([]db.StreamEvent, error) {
+	var out []db.StreamEvent
+	for _, e := range r.events {
+		if e.ID > arg.AfterID && len(out) < int(arg.Limit) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestStreamReconnectSkipsDeliveredEvents(t *testing.T) {
+	repo := &streamRepo{}
+	for id := int64(1); id <= 15; id++ {
+		repo.events = append(repo.events, db.StreamEvent{ID: id, Type: "translation", Payload: []byte(`{}`)})
+	}
+	hub := stream.NewHub(nil, repo, slog.Default())
+	srv := httptest.NewServer(http.HandlerFunc(NewStreamHandler(hub, slog.Default()).Stream))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "12")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The stream stays open, so read up to the last saved event.
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+			ids = append(ids, id)
+			if id == "15" {
+				break
+			}
+		}
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []string{"13", "14", "15"}, ids)
+}
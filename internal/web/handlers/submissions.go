@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/jobs"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// Submission statuses, coarser than River's job states: a job waiting for a
+// worker or for a retry is queued, and one River gave up on or a moderator
+// cancelled has failed.
+const (
+	submissionQueued    = "queued"
+	submissionRunning   = "running"
+	submissionFailed    = "failed"
+	submissionCompleted = "completed"
+)
+
+// submissionResponse reports a website submission. ID is the River job ID,
+// absent when the name was already translated and nothing was queued.
+type submissionResponse struct {
+	ID            int64  `json:"id,omitempty"`
+	Status        string `json:"status"`
+	TranslationID int64  `json:"translation_id,omitempty"`
+}
+
+func submissionStatus(state rivertype.JobState) string {
+	switch state {
+	case rivertype.JobStateRunning:
+		return submissionRunning
+	case rivertype.JobStateCompleted:
+		return submissionCompleted
+	case rivertype.JobStateCancelled, rivertype.JobStateDiscarded:
+		return submissionFailed
+	default:
+		return submissionQueued
+	}
+}
+
+// Submission reports the status of a submission by the ID Create returned.
+// River deletes completed jobs after a day and failed ones after a week, after
+// which the submission is no longer found.
+func (h *TranslationHandler) Submission(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	job, err := h.riverClient.JobGet(r.Context(), id)
+	if err == nil && job.Kind != (jobs.TranslateUsernameArgs{}).Kind() {
+		err = river.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, river.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "submission not found")
+			return
+		}
+		h.log.ErrorContext(r.Context(), "getting submission job", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := submissionResponse{ID: job.ID, Status: submissionStatus(job.State)}
+	if resp.Status == submissionCompleted {
+		resp.TranslationID = h.submittedTranslationID(r, job)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// submittedTranslationID returns the ID of the translation a completed job
+// saved. Jobs that finished before they recorded their output are looked up
+// by the name they translated; 0 means the translation is gone.
+func (h *TranslationHandler) submittedTranslationID(r *http.Request, job *rivertype.JobRow) int64 {
+	var output jobs.TranslateUsernameOutput
+	if out := job.Output(); out != nil && json.Unmarshal(out, &output) == nil && output.TranslationID != 0 {
+		return output.TranslationID
+	}
+
+	var args jobs.TranslateUsernameArgs
+	if err := json.Unmarshal(job.EncodedArgs, &args); err != nil {
+		h.log.WarnContext(r.Context(), "decoding submission job args", "id", job.ID, "error", err)
+		return 0
+	}
+	t, err := h.repo.GetPublicTranslationByUsernameKey(r.Context(), transliteration.CanonicalKey(args.Username))
+	if err != nil || t.Hidden {
+		if err != nil && !db.IsNoRows(err) {
+			h.log.WarnContext(r.Context(), "looking up submitted translation", "id", job.ID, "error", err)
+		}
+		return 0
+	}
+	return t.ID
+}
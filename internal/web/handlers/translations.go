@@ -3,17 +3,18 @@ package handlers
 import (
 	"encoding/json"
 	"log/slog"
-	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/jobs"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/transliteration"
-	"github.com/jusunglee/leagueofren/internal/jobs"
 	"github.com/riverqueue/river"
+	"github.com/samber/lo"
 )
 
 type TranslationHandler struct {
@@ -28,21 +29,31 @@ func NewTranslationHandler(repo db.Repository, log *slog.Logger, riotClient *rio
 }
 
 type translationResponse struct {
-	ID              int64    `json:"id"`
-	Username        string   `json:"username"`
-	Transliteration string   `json:"transliteration"`
-	Translation     string   `json:"translation"`
-	Explanation     *string  `json:"explanation,omitempty"`
-	Language        string   `json:"language"`
-	Region          string   `json:"region"`
-	RiotVerified    bool     `json:"riot_verified"`
-	Rank            *string  `json:"rank,omitempty"`
-	TopChampions    []string `json:"top_champions,omitempty"`
-	Upvotes         int32    `json:"upvotes"`
-	Downvotes       int32    `json:"downvotes"`
-	Score           float64  `json:"score,omitempty"`
-	CreatedAt       string   `json:"created_at"`
-	FirstSeen       string   `json:"first_seen,omitempty"`
+	ID              int64             `json:"id"`
+	Username        string            `json:"username"`
+	Transliteration string            `json:"transliteration"`
+	Zhuyin          string            `json:"zhuyin,omitempty"`
+	Segments        []segmentResponse `json:"segments,omitempty"`
+	Translation     string            `json:"translation"`
+	Explanation     *string           `json:"explanation,omitempty"`
+	Language        string            `json:"language"`
+	Region          string            `json:"region"`
+	RiotVerified    bool              `json:"riot_verified"`
+	Rank            *string           `json:"rank,omitempty"`
+	TopChampions    []string          `json:"top_champions,omitempty"`
+	Upvotes         int32             `json:"upvotes"`
+	Downvotes       int32             `json:"downvotes"`
+	Score           float64           `json:"score,omitempty"`
+	CreatedAt       string            `json:"created_at"`
+	FirstSeen       string            `json:"first_seen,omitempty"`
+}
+
+// segmentResponse is one script run of a username, so the frontend can
+// annotate each run with its own romanization.
+type segmentResponse struct {
+	Text      string `json:"text"`
+	Script    string `json:"script"`
+	Romanized string `json:"romanized"`
 }
 
 type paginationMeta struct {
@@ -51,16 +62,23 @@ type paginationMeta struct {
 	Total int64 `json:"total"`
 }
 
+// listResponse is a page of translations. Pages fetched by offset carry
+// pagination; every full page carries the cursor of the next.
 type listResponse struct {
 	Data       []translationResponse `json:"data"`
-	Pagination paginationMeta        `json:"pagination"`
+	Pagination *paginationMeta       `json:"pagination,omitempty"`
+	NextCursor string                `json:"next_cursor,omitempty"`
 }
 
-func toTranslationResponse(t db.PublicTranslation) translationResponse {
+// toTranslationResponse converts t for the API, romanizing Chinese names in
+// style. Chinese names from TW also carry Zhuyin, which Taiwanese players read
+// more easily than pinyin.
+func toTranslationResponse(t db.PublicTranslation, style transliteration.Style) translationResponse {
 	resp := translationResponse{
 		ID:              t.ID,
 		Username:        t.Username,
-		Transliteration: transliteration.Transliterate(t.Username),
+		Transliteration: transliteration.TransliterateStyle(t.Username, style),
+		Segments:        toSegmentResponses(transliteration.Segments(t.Username, style)),
 		Translation:     t.Translation,
 		Language:        t.Language,
 		Region:          t.Region,
@@ -70,6 +88,9 @@ func toTranslationResponse(t db.PublicTranslation) translationResponse {
 		CreatedAt:       t.CreatedAt.Format(time.RFC3339),
 		FirstSeen:       t.FirstSeen.Format(time.RFC3339),
 	}
+	if t.Language == transliteration.ScriptChinese && strings.EqualFold(t.Region, "TW") {
+		resp.Zhuyin = transliteration.TransliterateStyle(t.Username, transliteration.StyleZhuyin)
+	}
 	if t.Explanation.Valid {
 		resp.Explanation = &t.Explanation.String
 	}
@@ -85,15 +106,15 @@ func toTranslationResponse(t db.PublicTranslation) translationResponse {
 	return resp
 }
 
-func hotScore(upvotes, downvotes int32, createdAt time.Time) float64 {
-	diff := int64(upvotes) - int64(downvotes)
-	absDiff := diff
-	if absDiff < 0 {
-		absDiff = -absDiff
+// toSegmentResponses returns nil for Latin-only names, which have nothing to
+// annotate.
+func toSegmentResponses(segments []transliteration.Segment) []segmentResponse {
+	if !lo.ContainsBy(segments, func(s transliteration.Segment) bool { return s.Script != transliteration.ScriptLatin }) {
+		return nil
 	}
-	magnitude := math.Log10(math.Max(float64(absDiff), 1))
-	epochSeconds := float64(createdAt.Unix())
-	return magnitude + epochSeconds/45000
+	return lo.Map(segments, func(s transliteration.Segment, _ int) segmentResponse {
+		return segmentResponse{Text: s.Text, Script: s.Script, Romanized: s.Romanized}
+	})
 }
 
 func (h *TranslationHandler) List(w http.ResponseWriter, r *http.Request) {
@@ -109,6 +130,11 @@ func (h *TranslationHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 	region := q.Get("region")
 	language := q.Get("language")
+	style, ok := transliteration.ParseStyle(q.Get("romanization"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "romanization must be one of plain, tones or zhuyin")
+		return
+	}
 
 	page, _ := strconv.Atoi(q.Get("page"))
 	if page < 1 {
@@ -120,117 +146,211 @@ func (h *TranslationHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 	offset := (page - 1) * limit
 
+	// new and hot span every period; the vote-ranked sorts are limited to one.
+	var cutoff time.Time
+	switch sort {
+	case "top", "best", "controversial":
+		cutoff = periodCutoff(period)
+	case "hot", "new":
+	default:
+		sort = "new"
+	}
+
+	// A cursor resumes where the last page ended, so it needs neither an
+	// offset nor, since the client already has the first page, a count.
 	var (
-		total int64
-		err   error
+		after      listCursor
+		pagination *paginationMeta
 	)
-	if sort != "hot" {
-		total, err = h.repo.CountPublicTranslations(r.Context(), db.CountPublicTranslationsParams{
-			Region:   region,
-			Language: language,
+	if token := q.Get("cursor"); token != "" {
+		var err error
+		if after, err = decodeCursor(token, sort); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		offset = 0
+	} else {
+		total, err := h.repo.CountPublicTranslations(r.Context(), db.CountPublicTranslationsParams{
+			Region:    region,
+			Language:  language,
+			CreatedAt: cutoff,
 		})
 		if err != nil {
 			h.log.ErrorContext(r.Context(), "counting translations", "error", err)
 			writeError(w, http.StatusInternalServerError, "internal error")
 			return
 		}
+		pagination = &paginationMeta{Page: page, Limit: limit, Total: total}
 	}
 
-	var translations []db.PublicTranslation
-
+	var (
+		data []translationResponse
+		last listCursor // where the page's last row sits in the list
+		err  error
+	)
+	scored := func(t db.PublicTranslation, score float64) translationResponse {
+		last = listCursor{Sort: sort, ID: t.ID, Score: score}
+		resp := toTranslationResponse(t, style)
+		resp.Score = score
+		return resp
+	}
 	switch sort {
 	case "top":
-		cutoff := periodCutoff(period)
+		var translations []db.PublicTranslation
 		translations, err = h.repo.ListPublicTranslationsTop(r.Context(), db.ListPublicTranslationsTopParams{
-			Region:    region,
-			Language:  language,
-			Limit:     int32(limit),
-			Offset:    int32(offset),
-			CreatedAt: cutoff,
+			Region:     region,
+			Language:   language,
+			Limit:      int32(limit),
+			Offset:     int32(offset),
+			CreatedAt:  cutoff,
+			AfterID:    after.ID,
+			AfterScore: after.Score,
+		})
+		data = lo.Map(translations, func(t db.PublicTranslation, _ int) translationResponse {
+			last = listCursor{Sort: sort, ID: t.ID, Score: float64(t.Upvotes - t.Downvotes)}
+			return toTranslationResponse(t, style)
+		})
+	case "best":
+		var rows []db.ListPublicTranslationsBestRow
+		rows, err = h.repo.ListPublicTranslationsBest(r.Context(), db.ListPublicTranslationsBestParams{
+			Region:     region,
+			Language:   language,
+			Limit:      int32(limit),
+			Offset:     int32(offset),
+			CreatedAt:  cutoff,
+			AfterID:    after.ID,
+			AfterScore: after.Score,
+		})
+		data = lo.Map(rows, func(row db.ListPublicTranslationsBestRow, _ int) translationResponse {
+			return scored(row.PublicTranslation, row.BestScore)
+		})
+	case "controversial":
+		var rows []db.ListPublicTranslationsControversialRow
+		rows, err = h.repo.ListPublicTranslationsControversial(r.Context(), db.ListPublicTranslationsControversialParams{
+			Region:     region,
+			Language:   language,
+			Limit:      int32(limit),
+			Offset:     int32(offset),
+			CreatedAt:  cutoff,
+			AfterID:    after.ID,
+			AfterScore: after.Score,
+		})
+		data = lo.Map(rows, func(row db.ListPublicTranslationsControversialRow, _ int) translationResponse {
+			return scored(row.PublicTranslation, row.Controversy)
 		})
 	case "hot":
-		// Fetch recent translations and sort by hot score in Go, always return top 10
-		translations, err = h.repo.ListPublicTranslationsNew(r.Context(), db.ListPublicTranslationsNewParams{
-			Region:   region,
-			Language: language,
-			Limit:    500,
-			Offset:   0,
+		var rows []db.ListPublicTranslationsHotRow
+		rows, err = h.repo.ListPublicTranslationsHot(r.Context(), db.ListPublicTranslationsHotParams{
+			Region:     region,
+			Language:   language,
+			Limit:      int32(limit),
+			Offset:     int32(offset),
+			AfterID:    after.ID,
+			AfterScore: after.Score,
+		})
+		data = lo.Map(rows, func(row db.ListPublicTranslationsHotRow, _ int) translationResponse {
+			return scored(row.PublicTranslation, row.HotScore)
 		})
 	default:
+		var translations []db.PublicTranslation
 		translations, err = h.repo.ListPublicTranslationsNew(r.Context(), db.ListPublicTranslationsNewParams{
-			Region:   region,
-			Language: language,
-			Limit:    int32(limit),
-			Offset:   int32(offset),
+			Region:         region,
+			Language:       language,
+			Limit:          int32(limit),
+			Offset:         int32(offset),
+			AfterID:        after.ID,
+			AfterCreatedAt: after.CreatedAt,
+		})
+		data = lo.Map(translations, func(t db.PublicTranslation, _ int) translationResponse {
+			last = listCursor{Sort: sort, ID: t.ID, CreatedAt: t.CreatedAt}
+			return toTranslationResponse(t, style)
 		})
 	}
-
 	if err != nil {
 		h.log.ErrorContext(r.Context(), "listing translations", "error", err)
 		writeError(w, http.StatusInternalServerError, "internal error")
 		return
 	}
 
-	var data []translationResponse
+	resp := listResponse{Data: data, Pagination: pagination}
+	// A short page is the last one.
+	if len(data) == limit {
+		resp.NextCursor = last.encode()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
 
-	if sort == "hot" {
-		type scored struct {
-			resp  translationResponse
-			score float64
-		}
-		items := make([]scored, len(translations))
-		for i, t := range translations {
-			s := hotScore(t.Upvotes, t.Downvotes, t.CreatedAt)
-			resp := toTranslationResponse(t)
-			resp.Score = s
-			items[i] = scored{resp: resp, score: s}
-		}
-		// Sort descending by score (insertion sort is fine for <= 500 items)
-		for i := 1; i < len(items); i++ {
-			key := items[i]
-			j := i - 1
-			for j >= 0 && items[j].score < key.score {
-				items[j+1] = items[j]
-				j--
-			}
-			items[j+1] = key
-		}
+// maxSearchLength caps the search query, which is matched against every
+// translation.
+const maxSearchLength = 100
 
-		end := 10
-		if end > len(items) {
-			end = len(items)
-		}
-		for _, item := range items[:end] {
-			data = append(data, item.resp)
-		}
-	} else {
-		for _, t := range translations {
-			data = append(data, toTranslationResponse(t))
-		}
+// Search lists the translations matching q, best first. q is matched against
+// usernames, their romanization (so "peikeo" finds 페이커) and the English
+// translations and explanations.
+func (h *TranslationHandler) Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	if len([]rune(query)) > maxSearchLength {
+		writeError(w, http.StatusBadRequest, "q must be at most 100 characters")
+		return
 	}
+	style, ok := transliteration.ParseStyle(q.Get("romanization"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "romanization must be one of plain, tones or zhuyin")
+		return
+	}
+	region := q.Get("region")
+	language := q.Get("language")
 
-	if data == nil {
-		data = []translationResponse{}
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 25
 	}
+	key := transliteration.SearchKey(query)
 
-	var pagination paginationMeta
-	if sort == "hot" {
-		pagination = paginationMeta{
-			Page:  1,
-			Limit: 10,
-			Total: int64(len(data)),
-		}
-	} else {
-		pagination = paginationMeta{
-			Page:  page,
-			Limit: limit,
-			Total: total,
-		}
+	total, err := h.repo.CountSearchPublicTranslations(r.Context(), db.CountSearchPublicTranslationsParams{
+		Query:    query,
+		Key:      key,
+		Region:   region,
+		Language: language,
+	})
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "counting search results", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	rows, err := h.repo.SearchPublicTranslations(r.Context(), db.SearchPublicTranslationsParams{
+		Query:    query,
+		Key:      key,
+		Region:   region,
+		Language: language,
+		Limit:    int32(limit),
+		Offset:   int32((page - 1) * limit),
+	})
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "searching translations", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
 	}
 
+	data := lo.Map(rows, func(row db.SearchPublicTranslationsRow, _ int) translationResponse {
+		resp := toTranslationResponse(row.PublicTranslation, style)
+		resp.Score = row.SearchRank
+		return resp
+	})
 	writeJSON(w, http.StatusOK, listResponse{
 		Data:       data,
-		Pagination: pagination,
+		Pagination: &paginationMeta{Page: page, Limit: limit, Total: total},
 	})
 }
 
@@ -242,7 +362,16 @@ func (h *TranslationHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	style, ok := transliteration.ParseStyle(r.URL.Query().Get("romanization"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "romanization must be one of plain, tones or zhuyin")
+		return
+	}
+
 	t, err := h.repo.GetPublicTranslation(r.Context(), id)
+	if err == nil && t.Hidden {
+		err = db.ErrNoRows
+	}
 	if err != nil {
 		if db.IsNoRows(err) {
 			writeError(w, http.StatusNotFound, "translation not found")
@@ -253,7 +382,7 @@ func (h *TranslationHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toTranslationResponse(t))
+	writeJSON(w, http.StatusOK, toTranslationResponse(t, style))
 }
 
 type createTranslationRequest struct {
@@ -281,6 +410,21 @@ func (h *TranslationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Names already on the site aren't translated again.
+	existing, err := h.repo.GetPublicTranslationByUsernameKey(r.Context(), transliteration.CanonicalKey(req.Username))
+	switch {
+	case err == nil && existing.Hidden:
+		writeError(w, http.StatusConflict, "this name's translation was removed by a moderator")
+		return
+	case err == nil:
+		writeJSON(w, http.StatusOK, submissionResponse{Status: submissionCompleted, TranslationID: existing.ID})
+		return
+	case !db.IsNoRows(err):
+		h.log.ErrorContext(r.Context(), "looking up existing translation", "username", req.Username, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
 	// Validate via Riot API before enqueueing (fast, prevents garbage jobs)
 	_, err = h.riot.GetAccountByRiotID(gameName, tagLine, req.Region)
 	if err != nil {
@@ -289,8 +433,9 @@ func (h *TranslationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Enqueue translation job for async processing
-	_, err = h.riverClient.Insert(r.Context(), jobs.TranslateUsernameArgs{
+	// Enqueue translation job for async processing. A name already queued
+	// gets the existing job back.
+	res, err := h.riverClient.Insert(r.Context(), jobs.TranslateUsernameArgs{
 		Username: req.Username,
 		Region:   req.Region,
 	}, nil)
@@ -300,8 +445,8 @@ func (h *TranslationHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.log.InfoContext(r.Context(), "translation job enqueued", "username", req.Username, "region", req.Region)
-	writeJSON(w, http.StatusAccepted, map[string]string{"status": "queued"})
+	h.log.InfoContext(r.Context(), "translation job enqueued", "username", req.Username, "region", req.Region, "jobID", res.Job.ID)
+	writeJSON(w, http.StatusAccepted, submissionResponse{ID: res.Job.ID, Status: submissionStatus(res.Job.State)})
 }
 
 func periodCutoff(period string) time.Time {
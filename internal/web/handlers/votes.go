@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
@@ -8,12 +9,14 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"strconv"
 	"time"
 
 	"github.com/jusunglee/leagueofren/internal/db"
 	"github.com/jusunglee/leagueofren/internal/metrics"
 	"github.com/jusunglee/leagueofren/internal/web/middleware"
+	"github.com/jusunglee/leagueofren/internal/web/stream"
 )
 
 const visitorCookieName = "lor_visitor"
@@ -22,10 +25,11 @@ type VoteHandler struct {
 	repo          db.Repository
 	log           *slog.Logger
 	maxVotesPerIP int
+	banSecret     []byte
 }
 
-func NewVoteHandler(repo db.Repository, log *slog.Logger, maxVotesPerIP int) *VoteHandler {
-	return &VoteHandler{repo: repo, log: log, maxVotesPerIP: maxVotesPerIP}
+func NewVoteHandler(repo db.Repository, log *slog.Logger, maxVotesPerIP int, banSecret []byte) *VoteHandler {
+	return &VoteHandler{repo: repo, log: log, maxVotesPerIP: maxVotesPerIP, banSecret: banSecret}
 }
 
 type voteRequest struct {
@@ -56,18 +60,8 @@ func (h *VoteHandler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ipHash := hashIP(middleware.ClientIP(r))
-	visitorID := getOrSetVisitorCookie(w, r)
-
-	// Check IP-level rate limit before processing the vote
-	ipCount, err := h.repo.CountVotesByIP(r.Context(), ipHash)
-	if err != nil {
-		h.log.ErrorContext(r.Context(), "counting votes by IP", "error", err)
-		writeError(w, http.StatusInternalServerError, "internal error")
-		return
-	}
-	if int(ipCount) >= h.maxVotesPerIP {
-		writeError(w, http.StatusTooManyRequests, "too many votes from this network")
+	ipHash, visitorID, ok := admitVoter(w, r, h.repo, h.log, h.maxVotesPerIP, h.banSecret)
+	if !ok {
 		return
 	}
 
@@ -147,18 +141,76 @@ func (h *VoteHandler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The vote is already counted, so the stream missing it isn't worth
+	// failing the request over.
+	if !t.Hidden {
+		if err := stream.Publish(r.Context(), h.repo, stream.TypeTranslationVoted, stream.TranslationVoted{
+			ID:        t.ID,
+			Upvotes:   t.Upvotes,
+			Downvotes: t.Downvotes,
+		}); err != nil {
+			h.log.WarnContext(r.Context(), "publishing vote event", "translationID", t.ID, "error", err)
+		}
+	}
+
 	writeJSON(w, http.StatusOK, voteResponse{
 		Upvotes:   t.Upvotes,
 		Downvotes: t.Downvotes,
 	})
 }
 
+// admitVoter identifies the visitor behind r by IP hash and cookie and checks
+// they may vote: not banned, and under the per-IP vote limit. When they may
+// not, it writes the error response and returns false.
+func admitVoter(w http.ResponseWriter, r *http.Request, repo db.Repository, log *slog.Logger, maxVotesPerIP int, banSecret []byte) (ipHash, visitorID string, ok bool) {
+	ip := middleware.ClientIP(r)
+	ipHash = hashIP(ip)
+	visitorID = getOrSetVisitorCookie(w, r)
+
+	banned, err := repo.IsVoteBanned(r.Context(), db.IsVoteBannedParams{IpKey: ipKey(banSecret, ip), VisitorID: visitorID})
+	if err != nil {
+		log.ErrorContext(r.Context(), "checking vote bans", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return "", "", false
+	}
+	if banned {
+		writeError(w, http.StatusForbidden, "voting is disabled for this visitor")
+		return "", "", false
+	}
+
+	// Check IP-level rate limit before processing the vote
+	ipCount, err := repo.CountVotesByIP(r.Context(), ipHash)
+	if err != nil {
+		log.ErrorContext(r.Context(), "counting votes by IP", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return "", "", false
+	}
+	if int(ipCount) >= maxVotesPerIP {
+		writeError(w, http.StatusTooManyRequests, "too many votes from this network")
+		return "", "", false
+	}
+	return ipHash, visitorID, true
+}
+
 func hashIP(ip string) string {
 	dailySalt := time.Now().Format("2006-01-02")
 	h := sha256.Sum256([]byte(ip + dailySalt))
 	return fmt.Sprintf("%x", h)
 }
 
+// ipKey is the key IP bans are stored against. Unlike hashIP it doesn't
+// change from day to day, so it's an HMAC under the ban secret rather than a
+// plain hash, which anyone could reverse by hashing every IPv4 address. The
+// address is normalized first, so a ban matches however it was written.
+func ipKey(secret []byte, ip string) string {
+	if addr, err := netip.ParseAddr(ip); err == nil {
+		ip = addr.Unmap().String()
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func getOrSetVisitorCookie(w http.ResponseWriter, r *http.Request) string {
 	if cookie, err := r.Cookie(visitorCookieName); err == nil && cookie.Value != "" {
 		return cookie.Value
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/jobs"
+	"github.com/riverqueue/river"
+)
+
+// PromoteWorker promotes translation alternatives. An alternative starts
+// leading when its net votes are at least margin ahead of its translation's,
+// and replaces the translation once it has led for hold; the translation's
+// previous version is kept as a revision. Leads are only measured when the
+// job runs, so they start and end up to one job interval late.
+type PromoteWorker struct {
+	river.WorkerDefaults[jobs.PromoteAlternativesArgs]
+	repo   db.Repository
+	log    *slog.Logger
+	margin int32
+	hold   time.Duration
+}
+
+func NewPromoteWorker(repo db.Repository, log *slog.Logger, margin int32, hold time.Duration) *PromoteWorker {
+	return &PromoteWorker{repo: repo, log: log, margin: margin, hold: hold}
+}
+
+func (w *PromoteWorker) Work(ctx context.Context, job *river.Job[jobs.PromoteAlternativesArgs]) error {
+	if _, err := w.repo.UpdateAlternativeLeads(ctx, w.margin); err != nil {
+		return fmt.Errorf("updating alternative leads: %w", err)
+	}
+
+	alternatives, err := w.repo.ListPromotableAlternatives(ctx, time.Now().Add(-w.hold))
+	if err != nil {
+		return fmt.Errorf("listing promotable alternatives: %w", err)
+	}
+
+	for _, a := range alternatives {
+		err := w.repo.WithTx(ctx, func(txRepo db.Repository) error {
+			promoted, err := txRepo.PromoteTranslationAlternative(ctx, a.ID)
+			if err != nil {
+				return fmt.Errorf("promoting: %w", err)
+			}
+			if promoted == 0 {
+				return nil
+			}
+			// The translation's votes were cast on the old version; its
+			// voters are now the alternative's.
+			if err := txRepo.DeleteTranslationVotes(ctx, a.TranslationID); err != nil {
+				return fmt.Errorf("clearing votes: %w", err)
+			}
+			if err := txRepo.CopyAlternativeVotes(ctx, a.ID); err != nil {
+				return fmt.Errorf("copying votes: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("promoting alternative %d: %w", a.ID, err)
+		}
+		w.log.InfoContext(ctx, "promoted translation alternative",
+			"alternativeID", a.ID, "translationID", a.TranslationID,
+			"upvotes", a.Upvotes, "downvotes", a.Downvotes)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/jobs"
+	"github.com/jusunglee/leagueofren/internal/web/stream"
+	"github.com/riverqueue/river"
+)
+
+// PruneStreamEventsWorker deletes stream events older than stream.Retention.
+type PruneStreamEventsWorker struct {
+	river.WorkerDefaults[jobs.PruneStreamEventsArgs]
+	repo db.Repository
+	log  *slog.Logger
+}
+
+func NewPruneStreamEventsWorker(repo db.Repository, log *slog.Logger) *PruneStreamEventsWorker {
+	return &PruneStreamEventsWorker{repo: repo, log: log}
+}
+
+func (w *PruneStreamEventsWorker) Work(ctx context.Context, job *river.Job[jobs.PruneStreamEventsArgs]) error {
+	deleted, err := w.repo.DeleteOldStreamEvents(ctx, time.Now().Add(-stream.Retention))
+	if err != nil {
+		return fmt.Errorf("deleting old stream events: %w", err)
+	}
+	if deleted > 0 {
+		w.log.InfoContext(ctx, "pruned stream events", "count", deleted)
+	}
+	return nil
+}
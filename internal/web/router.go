@@ -6,9 +6,11 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/dictionary"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/web/handlers"
 	"github.com/jusunglee/leagueofren/internal/web/middleware"
+	"github.com/jusunglee/leagueofren/internal/web/stream"
 	"github.com/riverqueue/river"
 )
 
@@ -18,6 +20,17 @@ type RateLimitConfig struct {
 	MaxVotesPerIP int
 }
 
+// AdminConfig selects how /api/v1/admin requests authenticate: Auth is
+// "basic" (user "admin" with Password) or "api-key" (an X-API-Key header
+// matching APIKey). The admin routes aren't served for any other Auth, such
+// as "none". BanSecret keys the IP hashes that vote bans are stored against.
+type AdminConfig struct {
+	Auth      string
+	Password  string
+	APIKey    string
+	BanSecret string
+}
+
 type Router struct {
 	repo           db.Repository
 	log            *slog.Logger
@@ -25,9 +38,13 @@ type Router struct {
 	riverClient    *river.Client[pgx.Tx]
 	allowedOrigins []string
 	rateLimit      RateLimitConfig
+	admin          AdminConfig
+	dict           *dictionary.Dictionary
+	hub            *stream.Hub
+	siteURL        string
 }
 
-func NewRouter(repo db.Repository, log *slog.Logger, riotClient *riot.DirectClient, riverClient *river.Client[pgx.Tx], allowedOrigins []string, rateLimit RateLimitConfig) *Router {
+func NewRouter(repo db.Repository, log *slog.Logger, riotClient *riot.DirectClient, riverClient *river.Client[pgx.Tx], allowedOrigins []string, rateLimit RateLimitConfig, admin AdminConfig, dict *dictionary.Dictionary, hub *stream.Hub, siteURL string) *Router {
 	return &Router{
 		repo:           repo,
 		log:            log,
@@ -35,6 +52,10 @@ func NewRouter(repo db.Repository, log *slog.Logger, riotClient *riot.DirectClie
 		riverClient:    riverClient,
 		allowedOrigins: allowedOrigins,
 		rateLimit:      rateLimit,
+		admin:          admin,
+		dict:           dict,
+		hub:            hub,
+		siteURL:        siteURL,
 	}
 }
 
@@ -42,8 +63,13 @@ func (r *Router) Handler() http.Handler {
 	mux := http.NewServeMux()
 
 	translationHandler := handlers.NewTranslationHandler(r.repo, r.log, r.riot, r.riverClient)
-	voteHandler := handlers.NewVoteHandler(r.repo, r.log, r.rateLimit.MaxVotesPerIP)
+	voteHandler := handlers.NewVoteHandler(r.repo, r.log, r.rateLimit.MaxVotesPerIP, []byte(r.admin.BanSecret))
 	feedbackHandler := handlers.NewFeedbackHandler(r.repo, r.log)
+	glyphHandler := handlers.NewGlyphHandler(r.repo, r.log, r.dict)
+	playerHandler := handlers.NewPlayerHandler(r.repo, r.log)
+	alternativeHandler := handlers.NewAlternativeHandler(r.repo, r.log, r.rateLimit.MaxVotesPerIP, []byte(r.admin.BanSecret))
+	streamHandler := handlers.NewStreamHandler(r.hub, r.log)
+	feedHandler := handlers.NewFeedHandler(r.repo, r.log, r.siteURL)
 
 	rateLimiter := middleware.NewRateLimiter(r.rateLimit.Max, r.rateLimit.WindowSeconds)
 
@@ -56,6 +82,15 @@ func (r *Router) Handler() http.Handler {
 		),
 	)
 
+	mux.Handle("GET /api/v1/search",
+		middleware.Chain(
+			http.HandlerFunc(translationHandler.Search),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("public, s-maxage=5, max-age=0"),
+		),
+	)
+
 	mux.Handle("GET /api/v1/translations/{id}",
 		middleware.Chain(
 			http.HandlerFunc(translationHandler.Get),
@@ -65,6 +100,74 @@ func (r *Router) Handler() http.Handler {
 		),
 	)
 
+	// The breakdown only changes with the bundled dictionary, so it can be
+	// cached much longer than the translation itself.
+	mux.Handle("GET /api/v1/translations/{id}/glyphs",
+		middleware.Chain(
+			http.HandlerFunc(glyphHandler.Get),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("public, s-maxage=3600, max-age=300"),
+		),
+	)
+
+	mux.Handle("GET /api/v1/translations/{id}/alternatives",
+		middleware.Chain(
+			http.HandlerFunc(alternativeHandler.List),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("public, s-maxage=5, max-age=0"),
+		),
+	)
+
+	mux.Handle("GET /api/v1/translations/{id}/revisions",
+		middleware.Chain(
+			http.HandlerFunc(alternativeHandler.Revisions),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("public, s-maxage=60, max-age=0"),
+		),
+	)
+
+	// The worker refreshes players a cycle at a time, so profiles can be
+	// cached for a minute.
+	mux.Handle("GET /api/v1/players/{username}",
+		middleware.Chain(
+			http.HandlerFunc(playerHandler.Get),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("public, s-maxage=60, max-age=0"),
+		),
+	)
+
+	mux.Handle("GET /api/v1/stream",
+		middleware.Chain(
+			http.HandlerFunc(streamHandler.Stream),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("no-cache"),
+		),
+	)
+
+	// Feed readers poll, and the ETag lets them do it cheaply.
+	mux.Handle("GET /feeds/translations.atom",
+		middleware.Chain(
+			http.HandlerFunc(feedHandler.Atom),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("public, s-maxage=300, max-age=300"),
+		),
+	)
+
+	mux.Handle("GET /feeds/translations.json",
+		middleware.Chain(
+			http.HandlerFunc(feedHandler.JSON),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("public, s-maxage=300, max-age=300"),
+		),
+	)
+
 	mux.Handle("POST /api/v1/translations",
 		middleware.Chain(
 			http.HandlerFunc(translationHandler.Create),
@@ -74,6 +177,16 @@ func (r *Router) Handler() http.Handler {
 		),
 	)
 
+	// Submission status changes as the job runs, so it isn't cached.
+	mux.Handle("GET /api/v1/submissions/{id}",
+		middleware.Chain(
+			http.HandlerFunc(translationHandler.Submission),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.CacheControl("no-store"),
+		),
+	)
+
 	mux.Handle("POST /api/v1/translations/{id}/vote",
 		middleware.Chain(
 			http.HandlerFunc(voteHandler.Vote),
@@ -92,5 +205,67 @@ func (r *Router) Handler() http.Handler {
 		),
 	)
 
+	mux.Handle("POST /api/v1/translations/{id}/alternatives",
+		middleware.Chain(
+			http.HandlerFunc(alternativeHandler.Create),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.RateLimit(rateLimiter),
+		),
+	)
+
+	mux.Handle("POST /api/v1/alternatives/{id}/vote",
+		middleware.Chain(
+			http.HandlerFunc(alternativeHandler.Vote),
+			middleware.PrometheusMetrics(),
+			middleware.RequestLogger(r.log),
+			middleware.RateLimit(rateLimiter),
+		),
+	)
+
+	r.adminRoutes(mux, feedbackHandler, rateLimiter)
+
 	return middleware.CORS(r.allowedOrigins)(mux)
 }
+
+// adminRoutes registers the moderation API behind the configured auth. Rate
+// limiting comes first, so that failed logins count against it too.
+func (r *Router) adminRoutes(mux *http.ServeMux, feedbackHandler *handlers.FeedbackHandler, rateLimiter *middleware.IPRateLimiter) {
+	var auth middleware.Middleware
+	switch r.admin.Auth {
+	case "basic":
+		auth = middleware.BasicAuth(r.admin.Password)
+	case "api-key":
+		auth = middleware.APIKeyAuth(r.admin.APIKey)
+	default:
+		return
+	}
+
+	adminHandler := handlers.NewAdminHandler(r.repo, r.log, r.riverClient, []byte(r.admin.BanSecret))
+	routes := map[string]http.HandlerFunc{
+		"GET /api/v1/admin/feedback":                   feedbackHandler.List,
+		"POST /api/v1/admin/feedback/{id}/resolve":     feedbackHandler.Resolve,
+		"PATCH /api/v1/admin/translations/{id}":        adminHandler.UpdateTranslation,
+		"DELETE /api/v1/admin/translations/{id}":       adminHandler.DeleteTranslation,
+		"POST /api/v1/admin/translations/{id}/hide":    adminHandler.HideTranslation,
+		"POST /api/v1/admin/translations/{id}/unhide":  adminHandler.UnhideTranslation,
+		"POST /api/v1/admin/translations/{id}/requeue": adminHandler.RequeueTranslation,
+		"POST /api/v1/admin/jobs/{id}/retry":           adminHandler.RetryJob,
+		"GET /api/v1/admin/bans":                       adminHandler.ListBans,
+		"POST /api/v1/admin/bans":                      adminHandler.CreateBan,
+		"DELETE /api/v1/admin/bans/{id}":               adminHandler.DeleteBan,
+		"GET /api/v1/admin/audit":                      adminHandler.ListAudit,
+	}
+	for pattern, h := range routes {
+		mux.Handle(pattern,
+			middleware.Chain(
+				h,
+				middleware.PrometheusMetrics(),
+				middleware.RequestLogger(r.log),
+				middleware.RateLimit(rateLimiter),
+				auth,
+				middleware.CacheControl("no-store"),
+			),
+		)
+	}
+}
@@ -0,0 +1,375 @@
+// Package stream pushes translation events to the website's live stream.
+// Events are saved to stream_events, so that reconnecting clients can catch up,
+// and announced with NOTIFY, so that every web instance's Hub hears about
+// them whichever instance published them.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/jusunglee/leagueofren/internal/metrics"
+)
+
+const (
+	TypeTranslationCreated = "translation.created"
+	TypeTranslationVoted   = "translation.voted"
+)
+
+const (
+	// channel is the NOTIFY channel NotifyStreamEvent sends on.
+	channel = "stream_events"
+
+	// Retention is how long events are kept for clients to catch up on.
+	Retention = 24 * time.Hour
+
+	// maxReplay caps how many missed events a client catches up on.
+	maxReplay = 500
+
+	// replayLookback is how many IDs before the last event seen a replay
+	// starts at. IDs are taken when an event is inserted but only become
+	// visible when its transaction commits, so an event can commit after one
+	// with a higher ID; replaying from the last ID alone would skip it.
+	replayLookback = 100
+
+	// seenSize is how many of the latest event IDs a Seen remembers. It's
+	// well over replayLookback, so that events replayed again are dropped.
+	seenSize = 4096
+
+	// maxNotifyPayload keeps notifications under Postgres' 8000 byte limit.
+	// Larger events are announced without their data, which the Hub reads
+	// back from the table.
+	maxNotifyPayload = 7900
+
+	// subscriberBuffer is how many events a subscriber can fall behind by
+	// before it is dropped.
+	subscriberBuffer = 64
+
+	maxReconnectDelay = time.Minute
+)
+
+// Event is one message on the stream. Data is the JSON of a
+// TranslationCreated or TranslationVoted, depending on Type.
+type Event struct {
+	ID   int64           `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type TranslationCreated struct {
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	Translation string `json:"translation"`
+	Explanation string `json:"explanation,omitempty"`
+	Language    string `json:"language"`
+	Region      string `json:"region"`
+}
+
+type TranslationVoted struct {
+	ID        int64 `json:"id"`
+	Upvotes   int32 `json:"upvotes"`
+	Downvotes int32 `json:"downvotes"`
+}
+
+// Publish saves an event and announces it. Called with a transaction's
+// repository, the event is only announced if the transaction commits.
+func Publish(ctx context.Context, repo db.Repository, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding %s event: %w", eventType, err)
+	}
+
+	saved, err := repo.CreateStreamEvent(ctx, db.CreateStreamEventParams{Type: eventType, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("saving %s event: %w", eventType, err)
+	}
+
+	e := toEvent(saved)
+	msg, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding %s event: %w", eventType, err)
+	}
+	if len(msg) > maxNotifyPayload {
+		e.Data = nil
+		if msg, err = json.Marshal(e); err != nil {
+			return fmt.Errorf("encoding %s event: %w", eventType, err)
+		}
+	}
+
+	if err := repo.NotifyStreamEvent(ctx, string(msg)); err != nil {
+		return fmt.Errorf("announcing %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+func toEvent(e db.StreamEvent) Event {
+	return Event{ID: e.ID, Type: e.Type, Data: e.Payload}
+}
+
+// Hub listens for announced events on a dedicated connection and fans them
+// out to subscribers.
+type Hub struct {
+	pool *pgxpool.Pool
+	repo db.Repository
+	log  *slog.Logger
+
+	mu     sync.Mutex
+	subs   map[chan Event]struct{}
+	seen   *Seen
+	lastID int64
+	closed bool
+}
+
+func NewHub(pool *pgxpool.Pool, repo db.Repository, log *slog.Logger) *Hub {
+	return &Hub{pool: pool, repo: repo, log: log, subs: make(map[chan Event]struct{}), seen: NewSeen()}
+}
+
+// Run listens until ctx is done, reconnecting after errors, and then closes
+// every subscription so that open streams end.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.close()
+
+	delay := time.Second
+	for {
+		started := time.Now()
+		err := h.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > maxReconnectDelay {
+			delay = time.Second
+		}
+		h.log.WarnContext(ctx, "stream listener disconnected", "error", err, "retryIn", delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func (h *Hub) listen(ctx context.Context) error {
+	conn, err := h.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring connection: %w", err)
+	}
+	// The connection stays subscribed to the channel, so it's closed rather
+	// than returned to the pool.
+	pgConn := conn.Hijack()
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		pgConn.Close(closeCtx)
+	}()
+
+	if _, err := pgConn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return fmt.Errorf("listening: %w", err)
+	}
+
+	// Events announced while the listener was down are only in the table.
+	if err := h.catchUp(ctx); err != nil {
+		return err
+	}
+
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for notification: %w", err)
+		}
+
+		var e Event
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			h.log.WarnContext(ctx, "decoding stream notification", "error", err)
+			continue
+		}
+		if e.Data == nil {
+			fetched, err := h.fetch(ctx, e.ID)
+			if err != nil {
+				h.log.WarnContext(ctx, "reading stream event", "id", e.ID, "error", err)
+				continue
+			}
+			e = fetched
+		}
+		h.broadcast(e)
+	}
+}
+
+// catchUp broadcasts the saved events the Hub missed while it wasn't
+// listening. The replay reaches back before the last event broadcast, and
+// broadcast drops the ones it has already sent.
+func (h *Hub) catchUp(ctx context.Context) error {
+	h.mu.Lock()
+	lastID := h.lastID
+	h.mu.Unlock()
+	if lastID == 0 {
+		return nil
+	}
+
+	missed, err := h.replay(ctx, lastID)
+	if err != nil {
+		return fmt.Errorf("catching up: %w", err)
+	}
+	for _, e := range missed {
+		h.broadcast(e)
+	}
+	return nil
+}
+
+func (h *Hub) fetch(ctx context.Context, id int64) (Event, error) {
+	events, err := h.repo.ListStreamEventsAfter(ctx, db.ListStreamEventsAfterParams{AfterID: id - 1, Limit: 1})
+	if err != nil {
+		return Event{}, err
+	}
+	if len(events) == 0 || events[0].ID != id {
+		return Event{}, db.ErrNoRows
+	}
+	return toEvent(events[0]), nil
+}
+
+// Replay returns the saved events that someone who last saw lastID may have
+// missed, oldest first, up to a limit. Subscribers get events in the order the
+// Hub broadcasts them, so the ones broadcast up to lastID are left out even if
+// their IDs are lower. What remains of those can only be events that committed
+// out of ID order after it. If the Hub doesn't remember lastID, it can't tell
+// those apart, so every event up to lastID is left out.
+func (h *Hub) Replay(ctx context.Context, lastID int64) ([]Event, error) {
+	events, err := h.replay(ctx, lastID)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	last := h.seen.order(lastID)
+	missed := events[:0]
+	for _, e := range events {
+		if e.ID <= lastID {
+			if last == 0 {
+				continue
+			}
+			if o := h.seen.order(e.ID); o != 0 && o <= last {
+				continue
+			}
+		}
+		missed = append(missed, e)
+	}
+	return missed, nil
+}
+
+// replay returns the saved events after lastID, oldest first, up to a limit.
+// Since events can commit out of ID order, it starts replayLookback IDs
+// earlier, so it also returns events the caller may already have.
+func (h *Hub) replay(ctx context.Context, lastID int64) ([]Event, error) {
+	saved, err := h.repo.ListStreamEventsAfter(ctx, db.ListStreamEventsAfterParams{
+		AfterID: max(lastID-replayLookback, 0),
+		Limit:   maxReplay,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, len(saved))
+	for i, e := range saved {
+		events[i] = toEvent(e)
+	}
+	return events, nil
+}
+
+// Subscribe returns a channel of live events and a function that ends the
+// subscription. The channel is closed if the subscriber falls too far
+// behind or the Hub stops; clients then reconnect and resume with
+// Last-Event-ID.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	h.subs[ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// broadcast sends e to every subscriber, unless it has been sent already: a
+// catch-up replay can overlap the events announced with NOTIFY.
+func (h *Hub) broadcast(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.seen.Add(e.ID) {
+		return
+	}
+	metrics.StreamEventsTotal.WithLabelValues(e.Type).Inc()
+	h.lastID = max(h.lastID, e.ID)
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for ch := range h.subs {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
+
+// Seen remembers the IDs of the latest events, to drop ones that arrive
+// twice. It holds at most seenSize IDs, forgetting the oldest added first, and
+// isn't safe for concurrent use.
+type Seen struct {
+	// ids maps each ID to when it was added, counting from 1.
+	ids   map[int64]uint64
+	ring  []int64
+	next  int
+	added uint64
+}
+
+func NewSeen() *Seen {
+	return &Seen{ids: make(map[int64]uint64, seenSize), ring: make([]int64, 0, seenSize)}
+}
+
+// Add remembers id and reports whether it is new.
+func (s *Seen) Add(id int64) bool {
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+	if len(s.ring) < seenSize {
+		s.ring = append(s.ring, id)
+	} else {
+		delete(s.ids, s.ring[s.next])
+		s.ring[s.next] = id
+		s.next = (s.next + 1) % seenSize
+	}
+	s.added++
+	s.ids[id] = s.added
+	return true
+}
+
+// order reports when id was added, counting from 1, or 0 if it isn't
+// remembered.
+func (s *Seen) order(id int64) uint64 {
+	return s.ids[id]
+}
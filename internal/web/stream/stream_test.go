@@ -0,0 +1,81 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/jusunglee/leagueofren/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeen(t *testing.T) {
+	seen := NewSeen()
+	assert.True(t, seen.Add(7))
+	assert.True(t, seen.Add(5), "events can arrive out of ID order")
+	assert.False(t, seen.Add(7))
+
+	for id := int64(100); id < 100+seenSize; id++ {
+		seen.Add(id)
+	}
+	assert.True(t, seen.Add(7), "the oldest IDs are forgotten")
+	assert.False(t, seen.Add(100+seenSize-1))
+	assert.Len(t, seen.ids, seenSize)
+}
+
+// fakeRepo implements the stream event methods of db.Repository in memory.
+type fakeRepo struct {
+	db.Repository
+	events []db.StreamEvent
+}
+
+func (r *fakeRepo) ListStreamEventsAfter(_ context.Context, arg db.ListStreamEventsAfterParams) ([]db.StreamEvent, error) {
+	var out []db.StreamEvent
+	for _, e := range r.events {
+		if e.ID > arg.AfterID && len(out) < int(arg.Limit) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func savedEvents(ids ...int64) []db.StreamEvent {
+	events := make([]db.StreamEvent, len(ids))
+	for i, id := range ids {
+		events[i] = db.StreamEvent{ID: id, Type: "translation", Payload: []byte("{}")}
+	}
+	return events
+}
+
+func eventIDs(events []Event) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeRepo{events: savedEvents(8, 9, 10, 11, 12, 13, 14)}
+	hub := NewHub(nil, repo, slog.Default())
+
+	// 11 and 13 commit late: 11 after 12 was broadcast, 13 not yet.
+	for _, id := range []int64{8, 9, 10, 12, 11, 14} {
+		hub.broadcast(Event{ID: id, Type: "translation"})
+	}
+
+	missed, err := hub.Replay(ctx, 12)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{11, 13, 14}, eventIDs(missed), "only events broadcast up to 12 are left out")
+
+	missed, err = hub.Replay(ctx, 9)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{10, 11, 12, 13, 14}, eventIDs(missed))
+
+	fresh := NewHub(nil, repo, slog.Default())
+	missed, err = fresh.Replay(ctx, 12)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{13, 14}, eventIDs(missed), "without 12 to go by, nothing up to it is replayed")
+}
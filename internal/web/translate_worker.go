@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"log/slog"
 	"time"
-	"unicode"
 
 	"github.com/jusunglee/leagueofren/internal/db"
 	"github.com/jusunglee/leagueofren/internal/jobs"
 	"github.com/jusunglee/leagueofren/internal/metrics"
 	"github.com/jusunglee/leagueofren/internal/riot"
 	"github.com/jusunglee/leagueofren/internal/translation"
+	"github.com/jusunglee/leagueofren/internal/transliteration"
+	"github.com/jusunglee/leagueofren/internal/web/stream"
 	"github.com/riverqueue/river"
 )
 
@@ -44,8 +45,13 @@ func (w *TranslateWorker) Work(ctx context.Context, job *river.Job[jobs.Translat
 	}
 	puuid := account.PUUID
 
+	translate := w.translator.TranslateUsernames
+	if job.Args.Retranslate {
+		translate = w.translator.Retranslate
+	}
+
 	llmStart := time.Now()
-	translations, err := w.translator.TranslateUsernames(ctx, []string{gameName})
+	translations, err := translate(ctx, []string{gameName}, translation.DefaultLanguage)
 	metrics.LLMTranslationDuration.Observe(time.Since(llmStart).Seconds())
 	if err != nil || len(translations) == 0 {
 		metrics.TranslationSubmissions.WithLabelValues("failed").Inc()
@@ -55,6 +61,7 @@ func (w *TranslateWorker) Work(ctx context.Context, job *river.Job[jobs.Translat
 	t := translations[0]
 	language := detectLanguageFromName(gameName)
 
+	var pt db.PublicTranslation
 	err = w.repo.WithTx(ctx, func(txRepo db.Repository) error {
 		_, err := txRepo.UpsertPlayer(ctx, db.UpsertPlayerParams{
 			Username: username,
@@ -65,19 +72,38 @@ func (w *TranslateWorker) Work(ctx context.Context, job *river.Job[jobs.Translat
 			return err
 		}
 
+		key := transliteration.CanonicalKey(username)
+		_, err = txRepo.GetPublicTranslationByUsernameKey(ctx, key)
+		if err != nil && !db.IsNoRows(err) {
+			return fmt.Errorf("checking for an existing translation: %w", err)
+		}
+		created := err != nil
+
 		params := db.UpsertPublicTranslationParams{
 			Username:       username,
 			Translation:    t.Translated,
 			Language:       language,
 			PlayerUsername: username,
 			RiotVerified:   tagLine != "",
+			UsernameKey:    key,
+			Romanized:      transliteration.SearchKey(username),
 		}
 		if t.Explanation != "" {
 			params.Explanation = sql.NullString{String: t.Explanation, Valid: true}
 		}
 
-		_, err = txRepo.UpsertPublicTranslation(ctx, params)
-		return err
+		pt, err = txRepo.UpsertPublicTranslation(ctx, params)
+		if err != nil || !created {
+			return err
+		}
+		return stream.Publish(ctx, txRepo, stream.TypeTranslationCreated, stream.TranslationCreated{
+			ID:          pt.ID,
+			Username:    pt.Username,
+			Translation: pt.Translation,
+			Explanation: pt.Explanation.String,
+			Language:    pt.Language,
+			Region:      region,
+		})
 	})
 	if err != nil {
 		metrics.TranslationSubmissions.WithLabelValues("failed").Inc()
@@ -86,14 +112,102 @@ func (w *TranslateWorker) Work(ctx context.Context, job *river.Job[jobs.Translat
 
 	metrics.TranslationSubmissions.WithLabelValues("success").Inc()
 	w.log.InfoContext(ctx, "translated username", "username", username, "region", region, "translation", t.Translated)
+
+	// Submission status reads the translation's ID back from the job. The
+	// translation is saved either way, so a failure here isn't worth a retry.
+	if err := river.RecordOutput(ctx, jobs.TranslateUsernameOutput{TranslationID: pt.ID}); err != nil {
+		w.log.WarnContext(ctx, "recording job output", "username", username, "error", err)
+	}
 	return nil
 }
 
 func detectLanguageFromName(name string) string {
-	for _, r := range name {
-		if unicode.Is(unicode.Hangul, r) {
-			return "korean"
+	if script := transliteration.DetectScript(name); script != transliteration.ScriptLatin {
+		return script
+	}
+	return transliteration.ScriptChinese
+}
+
+// backfillBatchSize is how many public translations the backfills read at a
+// time.
+const backfillBatchSize = 500
+
+// BackfillUsernameKeys sets the username key of public translations written
+// before submissions were deduplicated on it, so that resubmitting one of
+// those names updates it rather than failing on its username. A row whose key
+// another row already has is a lookalike duplicate of it: its votes and
+// feedback are merged into that row and it is deleted. It returns how many
+// rows it keyed or merged.
+func BackfillUsernameKeys(ctx context.Context, repo db.Repository) (int, error) {
+	return backfill(ctx,
+		func(afterID int64) ([]db.PublicTranslation, error) {
+			return repo.ListUnkeyedPublicTranslations(ctx, db.ListUnkeyedPublicTranslationsParams{
+				AfterID: afterID,
+				Limit:   backfillBatchSize,
+			})
+		},
+		func(row db.PublicTranslation) error {
+			key := transliteration.CanonicalKey(row.Username)
+			return repo.WithTx(ctx, func(txRepo db.Repository) error {
+				existing, err := txRepo.GetPublicTranslationByUsernameKey(ctx, key)
+				if db.IsNoRows(err) {
+					return txRepo.SetPublicTranslationUsernameKey(ctx, db.SetPublicTranslationUsernameKeyParams{
+						ID:          row.ID,
+						UsernameKey: key,
+					})
+				}
+				if err != nil {
+					return fmt.Errorf("checking for an existing translation: %w", err)
+				}
+
+				if err := txRepo.MergePublicTranslation(ctx, db.MergePublicTranslationParams{FromID: row.ID, IntoID: existing.ID}); err != nil {
+					return fmt.Errorf("merging into translation %d: %w", existing.ID, err)
+				}
+				_, err = txRepo.DeletePublicTranslation(ctx, row.ID)
+				return err
+			})
+		},
+	)
+}
+
+// BackfillSearchKeys sets the romanized search key of public translations
+// written before search existed. It returns how many rows it visited.
+func BackfillSearchKeys(ctx context.Context, repo db.Repository) (int, error) {
+	return backfill(ctx,
+		func(afterID int64) ([]db.PublicTranslation, error) {
+			return repo.ListUnromanizedPublicTranslations(ctx, db.ListUnromanizedPublicTranslationsParams{
+				AfterID: afterID,
+				Limit:   backfillBatchSize,
+			})
+		},
+		func(row db.PublicTranslation) error {
+			return repo.SetPublicTranslationRomanized(ctx, db.SetPublicTranslationRomanizedParams{
+				ID:        row.ID,
+				Romanized: transliteration.SearchKey(row.Username),
+			})
+		},
+	)
+}
+
+// backfill pages through the rows list returns, in ID order, and calls set on
+// each.
+func backfill(ctx context.Context, list func(afterID int64) ([]db.PublicTranslation, error), set func(db.PublicTranslation) error) (int, error) {
+	var afterID int64
+	total := 0
+	for {
+		rows, err := list(afterID)
+		if err != nil {
+			return total, fmt.Errorf("listing public translations: %w", err)
+		}
+		for _, row := range rows {
+			if err := set(row); err != nil {
+				return total, fmt.Errorf("updating public translation %d: %w", row.ID, err)
+			}
+			afterID = row.ID
+			total++
+		}
+		if len(rows) < backfillBatchSize {
+			return total, nil
 		}
 	}
-	return "chinese"
 }
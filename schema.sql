@@ -1,3 +1,6 @@
+-- Trigram matching for /api/v1/search
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 -- Subscriptions table
 CREATE TABLE subscriptions (
     id BIGSERIAL PRIMARY KEY,
@@ -30,13 +33,23 @@ CREATE INDEX idx_evals_subscription_game ON evals(subscription_id, game_id);
 -- Translations table (cached username translations)
 CREATE TABLE translations (
     id BIGSERIAL PRIMARY KEY,
-    username TEXT NOT NULL UNIQUE,
+    username TEXT NOT NULL,
     translation TEXT NOT NULL,
     provider TEXT NOT NULL,
     model TEXT NOT NULL,
-    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    prompt_version TEXT NOT NULL DEFAULT '',
+    seen_count BIGINT NOT NULL DEFAULT 1,
+    examples_version TEXT NOT NULL DEFAULT '',
+    target_language TEXT NOT NULL DEFAULT 'en',
+    -- When the translation was last written. created_at stays put so that
+    -- retranslated rows still age out under the retention policy.
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (username, target_language)
 );
 
+CREATE INDEX idx_translations_seen_count ON translations(seen_count);
+
 -- Translation to evals junction table
 CREATE TABLE translation_to_evals (
     translation_id BIGINT NOT NULL REFERENCES translations(id) ON DELETE CASCADE,
@@ -83,6 +96,30 @@ CREATE TABLE riot_game_cache (
 
 CREATE INDEX idx_riot_game_cache_expires ON riot_game_cache(expires_at);
 
+-- Per-Discord-server settings
+CREATE TABLE server_configs (
+    server_id TEXT PRIMARY KEY,
+    target_language TEXT NOT NULL DEFAULT 'en',
+    -- Comma-separated transliteration script names to translate; empty means all.
+    scripts TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- LLM token usage, one row per call per Discord server it was made for.
+-- server_id is empty for calls not made on a server's behalf (website, retranslate).
+CREATE TABLE llm_usage (
+    id BIGSERIAL PRIMARY KEY,
+    server_id TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    input_tokens BIGINT NOT NULL,
+    output_tokens BIGINT NOT NULL,
+    cost_microusd BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_llm_usage_server_created ON llm_usage(server_id, created_at);
+
 -- ===========================================
 -- Companion Website Tables
 -- ===========================================
@@ -100,6 +137,20 @@ CREATE TABLE players (
 
 CREATE INDEX idx_players_region ON players(region);
 
+-- Solo queue rank over time, one row each time the worker sees the tier,
+-- division or LP change
+CREATE TABLE player_rank_history (
+    id BIGSERIAL PRIMARY KEY,
+    player_username TEXT NOT NULL REFERENCES players(username) ON DELETE CASCADE,
+    tier TEXT NOT NULL,
+    -- I to IV; Master and above have a single division, I
+    division TEXT NOT NULL,
+    league_points INT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_player_rank_history_player ON player_rank_history(player_username, recorded_at);
+
 -- Public translations submitted by bot users (opt-in)
 CREATE TABLE public_translations (
     id BIGSERIAL PRIMARY KEY,
@@ -112,12 +163,48 @@ CREATE TABLE public_translations (
     riot_verified BOOLEAN NOT NULL DEFAULT false,
     upvotes INT NOT NULL DEFAULT 0,
     downvotes INT NOT NULL DEFAULT 0,
-    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Canonical form of username (NFKC, invisibles stripped, lookalikes folded)
+    -- that submissions are deduplicated on; NULL until backfilled.
+    username_key TEXT,
+    -- Hidden by a moderator: kept (so resubmissions don't bring it back) but
+    -- left out of every public listing.
+    hidden BOOLEAN NOT NULL DEFAULT false,
+    -- Plain romanization of the gameName folded for search (transliteration.SearchKey),
+    -- so "peikeo" finds 페이커; empty until backfilled.
+    romanized TEXT NOT NULL DEFAULT '',
+    -- Hot ranking, log10(max(|upvotes - downvotes|, 1)) + created_at epoch / 45000,
+    -- set on insert and by every vote query; 0 until backfilled.
+    hot_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    -- Lower bound of the 95% Wilson score interval for the share of upvotes,
+    -- so a 3-0 translation doesn't outrank a 95-10 one.
+    best_score DOUBLE PRECISION GENERATED ALWAYS AS (
+        CASE WHEN upvotes + downvotes = 0 THEN 0
+        ELSE ((upvotes + 1.9208) / (upvotes + downvotes)
+              - 1.96 * SQRT(upvotes::float8 * downvotes / (upvotes + downvotes) + 0.9604) / (upvotes + downvotes))
+             / (1 + 3.8416 / (upvotes + downvotes))
+        END
+    ) STORED,
+    -- Many votes split close to evenly: (upvotes + downvotes) raised to the
+    -- ratio of the smaller count to the larger, 0 without votes both ways.
+    controversy DOUBLE PRECISION GENERATED ALWAYS AS (
+        CASE WHEN upvotes <= 0 OR downvotes <= 0 THEN 0
+        ELSE POWER((upvotes + downvotes)::float8,
+                   LEAST(upvotes, downvotes)::float8 / GREATEST(upvotes, downvotes))
+        END
+    ) STORED
 );
 
 CREATE UNIQUE INDEX idx_public_translations_username ON public_translations(username);
-CREATE INDEX idx_public_translations_hot ON public_translations(upvotes, downvotes, created_at);
-CREATE INDEX idx_public_translations_created ON public_translations(created_at);
+CREATE UNIQUE INDEX idx_public_translations_username_key ON public_translations(username_key);
+CREATE INDEX idx_public_translations_hot ON public_translations(hot_score DESC, id DESC);
+CREATE INDEX idx_public_translations_best ON public_translations(best_score DESC, id DESC);
+CREATE INDEX idx_public_translations_controversial ON public_translations(controversy DESC, id DESC);
+CREATE INDEX idx_public_translations_created ON public_translations(created_at DESC, id DESC);
+CREATE INDEX idx_public_translations_username_trgm ON public_translations USING gin (username gin_trgm_ops);
+CREATE INDEX idx_public_translations_romanized_trgm ON public_translations USING gin (romanized gin_trgm_ops);
+CREATE INDEX idx_public_translations_text_search ON public_translations
+    USING gin (to_tsvector('english', translation || ' ' || COALESCE(explanation, '')));
 
 -- IP-based vote tracking (no login required, one vote per IP per translation)
 CREATE TABLE votes (
@@ -132,14 +219,103 @@ CREATE TABLE votes (
 
 CREATE INDEX idx_votes_translation ON votes(translation_id);
 
+-- Alternative translations suggested by visitors, one per visitor per
+-- translation. One that leads the translation by the promotion margin for long
+-- enough replaces it; promoted alternatives are kept but no longer listed.
+CREATE TABLE translation_alternatives (
+    id BIGSERIAL PRIMARY KEY,
+    translation_id BIGINT NOT NULL REFERENCES public_translations(id) ON DELETE CASCADE,
+    translation TEXT NOT NULL,
+    explanation TEXT,
+    ip_hash TEXT NOT NULL,
+    visitor_id TEXT NOT NULL,
+    upvotes INT NOT NULL DEFAULT 0,
+    downvotes INT NOT NULL DEFAULT 0,
+    -- When the alternative last started leading the translation by the
+    -- promotion margin; NULL while it doesn't.
+    leading_since TIMESTAMPTZ,
+    promoted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(translation_id, visitor_id)
+);
+
+CREATE INDEX idx_translation_alternatives_leading ON translation_alternatives(leading_since)
+    WHERE promoted_at IS NULL;
+
+-- Votes on alternatives, with the same one-vote-per-visitor rule as votes
+CREATE TABLE alternative_votes (
+    id BIGSERIAL PRIMARY KEY,
+    alternative_id BIGINT NOT NULL REFERENCES translation_alternatives(id) ON DELETE CASCADE,
+    ip_hash TEXT NOT NULL,
+    visitor_id TEXT NOT NULL,
+    vote SMALLINT NOT NULL CHECK (vote IN (-1, 1)),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(alternative_id, visitor_id)
+);
+
+CREATE INDEX idx_alternative_votes_ip_hash ON alternative_votes(ip_hash);
+
+-- Earlier versions of a public translation, each saved when a promoted
+-- alternative replaced it.
+CREATE TABLE translation_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    translation_id BIGINT NOT NULL REFERENCES public_translations(id) ON DELETE CASCADE,
+    translation TEXT NOT NULL,
+    explanation TEXT,
+    alternative_id BIGINT REFERENCES translation_alternatives(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_translation_revisions_translation ON translation_revisions(translation_id, created_at);
+
+-- Events pushed to /api/v1/stream, kept for a day so that clients can resume
+-- from the last one they saw. Each is also sent on the stream_events NOTIFY
+-- channel, which every web instance listens on.
+CREATE TABLE stream_events (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_stream_events_created ON stream_events(created_at);
+
 -- Public feedback on translations (visible in admin panel only)
 CREATE TABLE public_feedback (
     id BIGSERIAL PRIMARY KEY,
     translation_id BIGINT NOT NULL REFERENCES public_translations(id) ON DELETE CASCADE,
     ip_hash TEXT NOT NULL,
     feedback_text TEXT NOT NULL,
-    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
 );
 
 CREATE INDEX idx_public_feedback_translation ON public_feedback(translation_id);
 CREATE INDEX idx_public_feedback_created ON public_feedback(created_at);
+
+-- Voters barred from voting by a moderator, by IP key or by visitor cookie.
+-- An IP key is an HMAC of the address under the server's ban secret: unlike
+-- the day-salted ip_hash on votes, it matches the same address every day.
+CREATE TABLE vote_bans (
+    id BIGSERIAL PRIMARY KEY,
+    ip_key TEXT,
+    visitor_id TEXT,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((ip_key IS NULL) <> (visitor_id IS NULL))
+);
+
+CREATE INDEX idx_vote_bans_ip_key ON vote_bans(ip_key);
+CREATE INDEX idx_vote_bans_visitor_id ON vote_bans(visitor_id);
+
+-- Every action taken through the admin API
+CREATE TABLE admin_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target_id BIGINT,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_admin_audit_log_created ON admin_audit_log(created_at);
@@ -69,6 +69,9 @@ var samples = []translation{
 	{"미드갱킹", "Mid Ganking", "Literal gameplay description as a name", "korean", "KR"},
 	{"刀锋意志", "Will of the Blade", "Irelia's Chinese title", "chinese", "EUW"},
 	{"솔로킬장인", "Solo Kill Artisan", "Claims mastery of 1v1s", "korean", "KR"},
+	{"ゆきんこ", "Snow Child", "Dialect word for a child playing in the snow", "japanese", "JP"},
+	{"東京タワー", "Tokyo Tower", "", "japanese", "JP"},
+	{"闇の炎", "Flame of Darkness", "Classic chuunibyou edgy name", "japanese", "JP"},
 }
 
 var ranks = []string{"IRON", "BRONZE", "SILVER", "GOLD", "PLATINUM", "EMERALD", "DIAMOND", "MASTER", "GRANDMASTER", "CHALLENGER"}
@@ -1,4 +1,4 @@
-import type { TranslationListResponse, Translation, SortOption, PeriodOption } from './schemas'
+import type { TranslationListResponse, Translation, GlyphsResponse, Player, Submission, Alternative, Revision, TranslationCreatedEvent, TranslationVotedEvent, SortOption, PeriodOption, RomanizationOption } from './schemas'
 
 export class RateLimitError extends Error {
   constructor() {
@@ -14,8 +14,11 @@ interface ListParams {
   period?: PeriodOption
   region?: string
   language?: string
+  romanization?: RomanizationOption
   page?: number
   limit?: number
+  // cursor continues from a previous page's next_cursor, in place of page.
+  cursor?: string
 }
 
 export async function listTranslations(params: ListParams = {}): Promise<TranslationListResponse> {
@@ -24,20 +27,94 @@ export async function listTranslations(params: ListParams = {}): Promise<Transla
   if (params.period) searchParams.set('period', params.period)
   if (params.region) searchParams.set('region', params.region)
   if (params.language) searchParams.set('language', params.language)
+  if (params.romanization) searchParams.set('romanization', params.romanization)
   if (params.page) searchParams.set('page', String(params.page))
   if (params.limit) searchParams.set('limit', String(params.limit))
+  if (params.cursor) searchParams.set('cursor', params.cursor)
 
   const res = await fetch(`${API_BASE}/translations?${searchParams}`)
   if (!res.ok) throw new Error('Failed to fetch translations')
   return res.json()
 }
 
-export async function getTranslation(id: number): Promise<Translation> {
-  const res = await fetch(`${API_BASE}/translations/${id}`)
+interface SearchParams {
+  q: string
+  region?: string
+  language?: string
+  romanization?: RomanizationOption
+  page?: number
+  limit?: number
+}
+
+export async function searchTranslations(params: SearchParams): Promise<TranslationListResponse> {
+  const searchParams = new URLSearchParams({ q: params.q })
+  if (params.region) searchParams.set('region', params.region)
+  if (params.language) searchParams.set('language', params.language)
+  if (params.romanization) searchParams.set('romanization', params.romanization)
+  if (params.page) searchParams.set('page', String(params.page))
+  if (params.limit) searchParams.set('limit', String(params.limit))
+
+  const res = await fetch(`${API_BASE}/search?${searchParams}`)
+  if (!res.ok) throw new Error('Failed to search translations')
+  return res.json()
+}
+
+export async function getTranslation(id: number, romanization?: RomanizationOption): Promise<Translation> {
+  const query = romanization ? `?romanization=${romanization}` : ''
+  const res = await fetch(`${API_BASE}/translations/${id}${query}`)
   if (!res.ok) throw new Error('Failed to fetch translation')
   return res.json()
 }
 
+export async function getGlyphs(id: number): Promise<GlyphsResponse> {
+  const res = await fetch(`${API_BASE}/translations/${id}/glyphs`)
+  if (!res.ok) throw new Error('Failed to fetch character breakdown')
+  return res.json()
+}
+
+export async function getPlayer(username: string, romanization?: RomanizationOption): Promise<Player> {
+  const query = romanization ? `?romanization=${romanization}` : ''
+  const res = await fetch(`${API_BASE}/players/${encodeURIComponent(username)}${query}`)
+  if (!res.ok) throw new Error('Failed to fetch player')
+  return res.json()
+}
+
+export async function submitTranslation(username: string, region: string): Promise<Submission> {
+  const res = await fetch(`${API_BASE}/translations`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ username, region }),
+  })
+  if (res.status === 429) throw new RateLimitError()
+  if (!res.ok) throw new Error('Failed to submit username')
+  return res.json()
+}
+
+export async function getSubmission(id: number): Promise<Submission> {
+  const res = await fetch(`${API_BASE}/submissions/${id}`)
+  if (!res.ok) throw new Error('Failed to fetch submission')
+  return res.json()
+}
+
+interface StreamHandlers {
+  onCreated?: (event: TranslationCreatedEvent) => void
+  onVoted?: (event: TranslationVotedEvent) => void
+}
+
+// subscribeToStream listens for live translation events. The browser
+// reconnects on its own and resumes from the last event it saw. It returns a
+// function that closes the stream.
+export function subscribeToStream({ onCreated, onVoted }: StreamHandlers): () => void {
+  const source = new EventSource(`${API_BASE}/stream`)
+  if (onCreated) {
+    source.addEventListener('translation.created', e => onCreated(JSON.parse((e as MessageEvent).data)))
+  }
+  if (onVoted) {
+    source.addEventListener('translation.voted', e => onVoted(JSON.parse((e as MessageEvent).data)))
+  }
+  return () => source.close()
+}
+
 export async function vote(translationId: number, direction: 1 | -1): Promise<{ upvotes: number; downvotes: number }> {
   const res = await fetch(`${API_BASE}/translations/${translationId}/vote`, {
     method: 'POST',
@@ -57,3 +134,39 @@ export async function submitFeedback(translationId: number, text: string): Promi
   })
   if (!res.ok) throw new Error('Failed to submit feedback')
 }
+
+export async function listAlternatives(translationId: number): Promise<Alternative[]> {
+  const res = await fetch(`${API_BASE}/translations/${translationId}/alternatives`)
+  if (!res.ok) throw new Error('Failed to fetch alternatives')
+  const body: { data: Alternative[] } = await res.json()
+  return body.data
+}
+
+export async function suggestAlternative(translationId: number, translation: string, explanation?: string): Promise<Alternative> {
+  const res = await fetch(`${API_BASE}/translations/${translationId}/alternatives`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ translation, explanation }),
+  })
+  if (res.status === 429) throw new RateLimitError()
+  if (!res.ok) throw new Error('Failed to suggest alternative')
+  return res.json()
+}
+
+export async function voteAlternative(alternativeId: number, direction: 1 | -1): Promise<{ upvotes: number; downvotes: number }> {
+  const res = await fetch(`${API_BASE}/alternatives/${alternativeId}/vote`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ vote: direction }),
+  })
+  if (res.status === 429) throw new RateLimitError()
+  if (!res.ok) throw new Error('Failed to vote')
+  return res.json()
+}
+
+export async function listRevisions(translationId: number): Promise<Revision[]> {
+  const res = await fetch(`${API_BASE}/translations/${translationId}/revisions`)
+  if (!res.ok) throw new Error('Failed to fetch revisions')
+  const body: { data: Revision[] } = await res.json()
+  return body.data
+}
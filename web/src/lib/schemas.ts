@@ -1,9 +1,19 @@
 import { z } from 'zod'
 
+export const segmentSchema = z.object({
+  text: z.string(),
+  script: z.string(),
+  romanized: z.string(),
+})
+
+export type Segment = z.infer<typeof segmentSchema>
+
 export const translationSchema = z.object({
   id: z.number(),
   username: z.string(),
   transliteration: z.string(),
+  zhuyin: z.string().optional(),
+  segments: z.array(segmentSchema).optional(),
   translation: z.string(),
   explanation: z.string().nullable(),
   language: z.string(),
@@ -13,6 +23,7 @@ export const translationSchema = z.object({
   top_champions: z.array(z.string()).nullable().optional(),
   upvotes: z.number(),
   downvotes: z.number(),
+  score: z.number().optional(),
   created_at: z.string(),
   first_seen: z.string().optional(),
 })
@@ -27,11 +38,57 @@ export const paginationSchema = z.object({
 
 export const translationListResponseSchema = z.object({
   data: z.array(translationSchema),
-  pagination: paginationSchema,
+  // Only pages fetched by page number carry pagination; cursor pages don't.
+  pagination: paginationSchema.optional(),
+  next_cursor: z.string().optional(),
 })
 
 export type TranslationListResponse = z.infer<typeof translationListResponseSchema>
 
+export const glyphSchema = z.object({
+  char: z.string(),
+  pinyin: z.string().optional(),
+  eum: z.string().optional(),
+  eum_romanized: z.string().optional(),
+  on: z.array(z.string()).optional(),
+  kun: z.array(z.string()).optional(),
+  meanings: z.array(z.string()).optional(),
+})
+
+export type Glyph = z.infer<typeof glyphSchema>
+
+export const glyphsResponseSchema = z.object({
+  translation_id: z.number(),
+  username: z.string(),
+  glyphs: z.array(glyphSchema),
+})
+
+export type GlyphsResponse = z.infer<typeof glyphsResponseSchema>
+
+export const rankPointSchema = z.object({
+  tier: z.string(),
+  division: z.string(),
+  league_points: z.number(),
+  recorded_at: z.string(),
+})
+
+export type RankPoint = z.infer<typeof rankPointSchema>
+
+export const playerSchema = z.object({
+  username: z.string(),
+  region: z.string(),
+  rank: z.string().optional(),
+  top_champions: z.array(z.string()).optional(),
+  first_seen: z.string(),
+  last_updated: z.string(),
+  translation: translationSchema.optional(),
+  // Oldest first.
+  history: z.array(rankPointSchema),
+  peak: rankPointSchema.optional(),
+})
+
+export type Player = z.infer<typeof playerSchema>
+
 export const voteRequestSchema = z.object({
   vote: z.union([z.literal(1), z.literal(-1)]),
 })
@@ -51,5 +108,61 @@ export const feedbackSchema = z.object({
 
 export type Feedback = z.infer<typeof feedbackSchema>
 
-export type SortOption = 'hot' | 'new' | 'top'
+export const submissionSchema = z.object({
+  id: z.number().optional(),
+  status: z.enum(['queued', 'running', 'failed', 'completed']),
+  translation_id: z.number().optional(),
+})
+
+export type Submission = z.infer<typeof submissionSchema>
+
+export const alternativeRequestSchema = z.object({
+  translation: z.string().min(1).max(200),
+  explanation: z.string().max(1000).optional(),
+})
+
+export const alternativeSchema = z.object({
+  id: z.number(),
+  translation_id: z.number(),
+  translation: z.string(),
+  explanation: z.string().optional(),
+  upvotes: z.number(),
+  downvotes: z.number(),
+  leading_since: z.string().optional(),
+  created_at: z.string(),
+})
+
+export type Alternative = z.infer<typeof alternativeSchema>
+
+export const revisionSchema = z.object({
+  id: z.number(),
+  translation: z.string(),
+  explanation: z.string().optional(),
+  alternative_id: z.number().optional(),
+  created_at: z.string(),
+})
+
+export type Revision = z.infer<typeof revisionSchema>
+
+export const translationCreatedEventSchema = z.object({
+  id: z.number(),
+  username: z.string(),
+  translation: z.string(),
+  explanation: z.string().optional(),
+  language: z.string(),
+  region: z.string(),
+})
+
+export type TranslationCreatedEvent = z.infer<typeof translationCreatedEventSchema>
+
+export const translationVotedEventSchema = z.object({
+  id: z.number(),
+  upvotes: z.number(),
+  downvotes: z.number(),
+})
+
+export type TranslationVotedEvent = z.infer<typeof translationVotedEventSchema>
+
+export type SortOption = 'hot' | 'new' | 'top' | 'best' | 'controversial'
 export type PeriodOption = 'hour' | 'day' | 'week' | 'month' | 'year' | 'all'
+export type RomanizationOption = 'plain' | 'tones' | 'zhuyin'
@@ -1,11 +1,23 @@
 import { useState, useRef, useEffect } from 'react'
 import { useSearchParams } from 'react-router-dom'
-import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import { useInfiniteQuery, useMutation, useQuery, useQueryClient, type InfiniteData } from '@tanstack/react-query'
 import { ChevronUp, ChevronDown, MessageCircleQuestion, ChevronDown as ChevronDownIcon, X, MessageSquarePlus, Send, SlidersHorizontal } from 'lucide-react'
-import { listTranslations, vote, submitFeedback, RateLimitError } from '../lib/api'
+import { listTranslations, getTranslation, vote, submitFeedback, subscribeToStream, RateLimitError } from '../lib/api'
 import type { SortOption, PeriodOption, Translation } from '../lib/schemas'
 import type { TranslationListResponse } from '../lib/schemas'
 
+type TranslationPages = InfiniteData<TranslationListResponse, string | undefined>
+
+// updateTranslation applies fn to translation id wherever it appears in the
+// loaded pages.
+function updateTranslation(old: TranslationPages | undefined, id: number, fn: (t: Translation) => Translation): TranslationPages | undefined {
+  if (!old) return old
+  return {
+    ...old,
+    pages: old.pages.map(page => ({ ...page, data: page.data.map(t => (t.id === id ? fn(t) : t)) })),
+  }
+}
+
 // ── Filled SVG icons for sort tabs ──
 
 function FlameIcon({ filled, color }: { filled: boolean; color: string }) {
@@ -32,6 +44,21 @@ function TrophyIcon({ filled, color }: { filled: boolean; color: string }) {
   )
 }
 
+function MedalIcon({ filled, color }: { filled: boolean; color: string }) {
+  return filled ? (
+    <svg width="14" height="14" viewBox="0 0 24 24" fill={color} stroke={color} strokeWidth="2"><path d="m15.477 12.89 1.515 8.526a.5.5 0 0 1-.81.47l-3.58-2.687a1 1 0 0 0-1.197 0l-3.586 2.686a.5.5 0 0 1-.81-.469l1.514-8.526"/><circle cx="12" cy="8" r="6"/></svg>
+  ) : (
+    <svg width="14" height="14" viewBox="0 0 24 24" fill="none" stroke={color} strokeWidth="2" strokeLinecap="round" strokeLinejoin="round"><path d="m15.477 12.89 1.515 8.526a.5.5 0 0 1-.81.47l-3.58-2.687a1 1 0 0 0-1.197 0l-3.586 2.686a.5.5 0 0 1-.81-.469l1.514-8.526"/><circle cx="12" cy="8" r="6"/></svg>
+  )
+}
+
+// Swords are all strokes, so the filled variant draws them heavier instead.
+function SwordsIcon({ filled, color }: { filled: boolean; color: string }) {
+  return (
+    <svg width="14" height="14" viewBox="0 0 24 24" fill="none" stroke={color} strokeWidth={filled ? 3 : 2} strokeLinecap="round" strokeLinejoin="round"><polyline points="14.5 17.5 3 6 3 3 6 3 17.5 14.5"/><line x1="13" x2="19" y1="19" y2="13"/><line x1="16" x2="20" y1="16" y2="20"/><line x1="19" x2="21" y1="21" y2="19"/><polyline points="14.5 6.5 18 3 21 3 21 6 17.5 9.5"/><line x1="5" x2="9" y1="14" y2="18"/><line x1="7" x2="4" y1="17" y2="20"/><line x1="3" x2="5" y1="19" y2="21"/></svg>
+  )
+}
+
 function OpggIcon() {
   return (
     <svg width="12" height="12" viewBox="0 0 24 24" aria-hidden="true">
@@ -50,11 +77,15 @@ function PorofessorIcon() {
   )
 }
 
-function SortIcon({ type, filled, color }: { type: 'flame' | 'sparkles' | 'trophy'; filled: boolean; color: string }) {
+type SortIconType = 'flame' | 'sparkles' | 'trophy' | 'medal' | 'swords'
+
+function SortIcon({ type, filled, color }: { type: SortIconType; filled: boolean; color: string }) {
   switch (type) {
     case 'flame': return <FlameIcon filled={filled} color={color} />
     case 'sparkles': return <SparklesIcon filled={filled} color={color} />
     case 'trophy': return <TrophyIcon filled={filled} color={color} />
+    case 'medal': return <MedalIcon filled={filled} color={color} />
+    case 'swords': return <SwordsIcon filled={filled} color={color} />
   }
 }
 
@@ -151,12 +182,17 @@ function PixelDropdown({ options, value, onChange, placeholder }: {
 
 // ── Constants ──
 
-const SORT_OPTIONS: { value: SortOption; label: string; color: string; icon: 'flame' | 'sparkles' | 'trophy' }[] = [
+const SORT_OPTIONS: { value: SortOption; label: string; color: string; icon: SortIconType }[] = [
   { value: 'hot', label: 'Hot', color: '#E85D75', icon: 'flame' },
   { value: 'new', label: 'New', color: '#FFD93D', icon: 'sparkles' },
   { value: 'top', label: 'Top', color: '#F2A65A', icon: 'trophy' },
+  { value: 'best', label: 'Best', color: '#6BCB77', icon: 'medal' },
+  { value: 'controversial', label: 'Controversial', color: '#9B72CF', icon: 'swords' },
 ]
 
+// Sorts ranked by votes, which list one period at a time.
+const PERIOD_SORTS: SortOption[] = ['top', 'best', 'controversial']
+
 const PERIOD_OPTIONS: { value: PeriodOption; label: string }[] = [
   { value: 'hour', label: 'Hour' },
   { value: 'day', label: 'Day' },
@@ -171,7 +207,15 @@ const REGION_EMOJI: Record<string, string> = {
   BR: '🇧🇷', LAN: '🌎', LAS: '🌎', OCE: '🇦🇺', TR: '🇹🇷', RU: '🇷🇺', TW: '🇹🇼',
 }
 
-const LANGUAGE_EMOJI: Record<string, string> = { korean: '🇰🇷', chinese: '🇨🇳' }
+const LANGUAGE_EMOJI: Record<string, string> = {
+  korean: '🇰🇷',
+  chinese: '🇨🇳',
+  japanese: '🇯🇵',
+  thai: '🇹🇭',
+  cyrillic: '🇷🇺',
+  arabic: '🇸🇦',
+  vietnamese: '🇻🇳',
+}
 
 const RANK_ICON: Record<string, string> = {
   IRON: '/iron.png',
@@ -192,6 +236,11 @@ const REGION_OPTIONS: DropdownOption[] = ['NA', 'EUW', 'EUNE', 'KR', 'JP', 'BR',
 const LANGUAGE_OPTIONS: DropdownOption[] = [
   { value: 'korean', label: 'Korean', icon: '🇰🇷' },
   { value: 'chinese', label: 'Chinese', icon: '🇨🇳' },
+  { value: 'japanese', label: 'Japanese', icon: '🇯🇵' },
+  { value: 'thai', label: 'Thai', icon: '🇹🇭' },
+  { value: 'cyrillic', label: 'Cyrillic', icon: '🇷🇺' },
+  { value: 'arabic', label: 'Arabic', icon: '🇸🇦' },
+  { value: 'vietnamese', label: 'Vietnamese', icon: '🇻🇳' },
 ]
 
 const RANK_OPTIONS: DropdownOption[] = Object.entries(RANK_ICON)
@@ -294,9 +343,18 @@ function TranslationCard({ t, index, onVote, onFeedback, voteAnimation }: {
             {t.transliteration && (
               <>
                 <span className="text-[var(--border)] font-bold">&rarr;</span>
-                <span className="mono-font text-sm lg:text-base text-[var(--foreground-muted)] tracking-wide break-all">{t.transliteration}</span>
+                <span className="mono-font text-sm lg:text-base text-[var(--foreground-muted)] tracking-wide break-all inline-flex flex-wrap gap-x-[1ch]">
+                  {t.segments && t.segments.length > 1
+                    ? t.segments.map((s, i) => (
+                        <span key={i} title={s.script === 'latin' ? undefined : `${s.text} (${s.script})`}>{s.romanized}</span>
+                      ))
+                    : t.transliteration}
+                </span>
               </>
             )}
+            {t.zhuyin && (
+              <span className="text-sm lg:text-base text-[var(--foreground-muted)] tracking-wide break-all">{t.zhuyin}</span>
+            )}
           </div>
           <div className="font-bold text-base lg:text-lg break-words">{t.translation}</div>
         </div>
@@ -411,33 +469,6 @@ function SectionMarker({ label }: { label: string }) {
   )
 }
 
-// ── Pagination ──
-
-function Pagination({ page, totalPages, onPageChange }: { page: number; totalPages: number; onPageChange: (page: number) => void }) {
-  if (totalPages <= 1) return null
-  return (
-    <div className="flex items-center justify-center gap-4">
-      <button
-        disabled={page <= 1}
-        onClick={() => onPageChange(page - 1)}
-        className="pixel-font text-xs px-4 py-2 pixel-border bg-[var(--card)] pixel-shadow-sm disabled:opacity-40 hover:-translate-y-0.5 hover:shadow-[4px_4px_0px_0px_var(--border)] transition-all duration-150 btn-press tracking-wide focus-visible:ring-2 focus-visible:ring-[var(--ring)]"
-      >
-        &larr; Prev
-      </button>
-      <span className="mono-font text-xs tracking-widest text-[var(--foreground-muted)]">
-        {page} / {totalPages}
-      </span>
-      <button
-        disabled={page >= totalPages}
-        onClick={() => onPageChange(page + 1)}
-        className="pixel-font text-xs px-4 py-2 pixel-border bg-[var(--card)] pixel-shadow-sm disabled:opacity-40 hover:-translate-y-0.5 hover:shadow-[4px_4px_0px_0px_var(--border)] transition-all duration-150 btn-press tracking-wide focus-visible:ring-2 focus-visible:ring-[var(--ring)]"
-      >
-        Next &rarr;
-      </button>
-    </div>
-  )
-}
-
 // ── Main Component ──
 
 export function Leaderboard() {
@@ -450,7 +481,8 @@ export function Leaderboard() {
   const language = searchParams.get('language') || ''
   const rank = searchParams.get('rank') || ''
   const champion = searchParams.get('champion') || ''
-  const page = Number(searchParams.get('page')) || 1
+  // Feeds link each entry to ?translation=ID, shown above the listing.
+  const linkedID = Number(searchParams.get('translation')) || 0
 
   const setParam = (updates: Record<string, string | number>) => {
     setSearchParams(prev => {
@@ -459,7 +491,6 @@ export function Leaderboard() {
         const str = String(v)
         const isDefault = (k === 'sort' && str === 'hot')
           || (k === 'period' && str === 'week')
-          || (k === 'page' && str === '1')
           || str === ''
         if (isDefault) next.delete(k)
         else next.set(k, str)
@@ -471,13 +502,44 @@ export function Leaderboard() {
   const [filtersOpen, setFiltersOpen] = useState(false)
   const [voteAnimations, setVoteAnimations] = useState<Record<number, 'up' | 'down' | 'shake'>>({})
 
-  const queryKey = ['translations', sort, period, region, language, page]
+  const queryKey = ['translations', sort, period, region, language]
 
-  const { data, isLoading } = useQuery({
+  const { data, isLoading, fetchNextPage, hasNextPage, isFetchingNextPage } = useInfiniteQuery({
     queryKey,
-    queryFn: () => listTranslations({ sort, period, region, language, page, limit: 25 }),
+    queryFn: ({ pageParam }) => listTranslations({ sort, period, region, language, romanization: 'tones', limit: 25, cursor: pageParam }),
+    initialPageParam: undefined as string | undefined,
+    getNextPageParam: lastPage => lastPage.next_cursor,
     refetchInterval: 60_000,
   })
+  const translations = data?.pages.flatMap(page => page.data)
+
+  const { data: linked } = useQuery({
+    queryKey: ['translation', linkedID],
+    queryFn: () => getTranslation(linkedID, 'tones'),
+    enabled: linkedID > 0,
+  })
+
+  // Apply live vote counts to every loaded listing, and refetch listings when
+  // a name is translated.
+  useEffect(() => subscribeToStream({
+    onVoted: e => queryClient.setQueriesData<TranslationPages>({ queryKey: ['translations'] }, old =>
+      updateTranslation(old, e.id, t => ({ ...t, upvotes: e.upvotes, downvotes: e.downvotes }))
+    ),
+    onCreated: () => queryClient.invalidateQueries({ queryKey: ['translations'] }),
+  }), [queryClient])
+
+  // Load the next page as the end of the list scrolls into view.
+  const scrollRef = useRef<HTMLDivElement>(null)
+  const sentinelRef = useRef<HTMLDivElement>(null)
+  useEffect(() => {
+    const sentinel = sentinelRef.current
+    if (!sentinel || !hasNextPage) return
+    const observer = new IntersectionObserver(entries => {
+      if (entries[0].isIntersecting && !isFetchingNextPage) fetchNextPage()
+    }, { root: scrollRef.current, rootMargin: '200px' })
+    observer.observe(sentinel)
+    return () => observer.disconnect()
+  }, [hasNextPage, isFetchingNextPage, fetchNextPage])
 
   const triggerVoteAnimation = (id: number, type: 'up' | 'down' | 'shake') => {
     setVoteAnimations(prev => ({ ...prev, [id]: type }))
@@ -494,33 +556,24 @@ export function Leaderboard() {
     mutationFn: ({ id, direction }: { id: number; direction: 1 | -1 }) => vote(id, direction),
     onMutate: async ({ id, direction }) => {
       await queryClient.cancelQueries({ queryKey })
-      const previous = queryClient.getQueryData<TranslationListResponse>(queryKey)
-      queryClient.setQueryData<TranslationListResponse>(queryKey, old => {
-        if (!old) return old
-        return {
-          ...old,
-          data: old.data.map(t =>
-            t.id === id
-              ? { ...t, upvotes: t.upvotes + (direction === 1 ? 1 : 0), downvotes: t.downvotes + (direction === -1 ? 1 : 0) }
-              : t
-          ),
-        }
-      })
+      const previous = queryClient.getQueryData<TranslationPages>(queryKey)
+      queryClient.setQueryData<TranslationPages>(queryKey, old =>
+        updateTranslation(old, id, t => ({
+          ...t,
+          upvotes: t.upvotes + (direction === 1 ? 1 : 0),
+          downvotes: t.downvotes + (direction === -1 ? 1 : 0),
+        }))
+      )
       triggerVoteAnimation(id, direction === 1 ? 'up' : 'down')
       return { previous }
     },
     onSuccess: (data, { id }) => {
-      queryClient.setQueryData<TranslationListResponse>(queryKey, old => {
-        if (!old) return old
-        return {
-          ...old,
-          data: old.data.map(t =>
-            t.id === id
-              ? { ...t, upvotes: data.upvotes, downvotes: data.downvotes }
-              : t
-          ),
-        }
-      })
+      queryClient.setQueryData<TranslationPages>(queryKey, old =>
+        updateTranslation(old, id, t => ({ ...t, upvotes: data.upvotes, downvotes: data.downvotes }))
+      )
+      queryClient.setQueryData<Translation>(['translation', id], t =>
+        t && { ...t, upvotes: data.upvotes, downvotes: data.downvotes }
+      )
     },
     onError: (err, { id }, context) => {
       if (context?.previous) {
@@ -536,7 +589,7 @@ export function Leaderboard() {
     mutationFn: ({ id, text }: { id: number; text: string }) => submitFeedback(id, text),
   })
 
-  const filteredData = data?.data.filter(t => {
+  const filteredData = translations?.filter(t => {
     if (rank && t.rank?.toUpperCase() !== rank) return false
     if (champion && !t.top_champions?.includes(champion)) return false
     return true
@@ -544,15 +597,14 @@ export function Leaderboard() {
 
   // Build champion options from loaded data
   const championOptions: DropdownOption[] = (() => {
-    if (!data?.data) return []
+    if (!translations) return []
     const champs = new Set<string>()
-    for (const t of data.data) {
+    for (const t of translations) {
       t.top_champions?.forEach(c => champs.add(c))
     }
     return Array.from(champs).sort().map(c => ({ value: c, label: c, icon: '⚔️' }))
   })()
 
-  const totalPages = data ? Math.ceil(data.pagination.total / 25) : 0
   const hasFilters = region || language || rank || champion
 
   return (
@@ -566,7 +618,7 @@ export function Leaderboard() {
           return (
             <button
               key={opt.value}
-              onClick={() => setParam({ sort: opt.value, page: 1 })}
+              onClick={() => setParam({ sort: opt.value })}
               className={`pixel-font text-xs px-4 lg:px-5 py-2 pixel-border transition-all duration-150 btn-press inline-flex items-center gap-2 focus-visible:ring-2 focus-visible:ring-[var(--ring)] focus-visible:ring-offset-2 ${
                 isActive
                   ? 'bg-[var(--violet)] text-white pixel-shadow-sm'
@@ -583,21 +635,21 @@ export function Leaderboard() {
 
         {/* Desktop: inline filters */}
         <div className="hidden lg:flex flex-wrap items-center gap-3">
-          {sort === 'top' && (
+          {PERIOD_SORTS.includes(sort) && (
             <PixelDropdown
               options={PERIOD_OPTIONS.map(p => ({ value: p.value, label: p.label }))}
               value={period}
-              onChange={v => setParam({ period: v, page: 1 })}
+              onChange={v => setParam({ period: v })}
               placeholder="Period"
             />
           )}
-          <PixelDropdown options={REGION_OPTIONS} value={region} onChange={v => setParam({ region: v, page: 1 })} placeholder="All Regions" />
-          <PixelDropdown options={LANGUAGE_OPTIONS} value={language} onChange={v => setParam({ language: v, page: 1 })} placeholder="All Languages" />
-          <PixelDropdown options={RANK_OPTIONS} value={rank} onChange={v => setParam({ rank: v, page: 1 })} placeholder="All Ranks" />
-          <PixelDropdown options={championOptions} value={champion} onChange={v => setParam({ champion: v, page: 1 })} placeholder="All Champions" />
+          <PixelDropdown options={REGION_OPTIONS} value={region} onChange={v => setParam({ region: v })} placeholder="All Regions" />
+          <PixelDropdown options={LANGUAGE_OPTIONS} value={language} onChange={v => setParam({ language: v })} placeholder="All Languages" />
+          <PixelDropdown options={RANK_OPTIONS} value={rank} onChange={v => setParam({ rank: v })} placeholder="All Ranks" />
+          <PixelDropdown options={championOptions} value={champion} onChange={v => setParam({ champion: v })} placeholder="All Champions" />
           {hasFilters && (
             <button
-              onClick={() => setParam({ region: '', language: '', rank: '', champion: '', page: 1 })}
+              onClick={() => setParam({ region: '', language: '', rank: '', champion: '' })}
               className="pixel-font text-[10px] px-3 py-2 bg-[var(--destructive)] text-white border-4 border-[var(--border)] rounded-[8px] pixel-shadow-sm tracking-wide uppercase hover:bg-[#b83a30] hover:-translate-x-0.5 hover:-translate-y-0.5 transition-all duration-150 btn-press inline-flex items-center gap-1"
             >
               <X size={12} strokeWidth={3} />
@@ -623,21 +675,21 @@ export function Leaderboard() {
       {/* Mobile: collapsible filters */}
       {filtersOpen && (
         <div className="flex lg:hidden flex-wrap items-center gap-3 animate-fade-in relative z-20">
-          {sort === 'top' && (
+          {PERIOD_SORTS.includes(sort) && (
             <PixelDropdown
               options={PERIOD_OPTIONS.map(p => ({ value: p.value, label: p.label }))}
               value={period}
-              onChange={v => setParam({ period: v, page: 1 })}
+              onChange={v => setParam({ period: v })}
               placeholder="Period"
             />
           )}
-          <PixelDropdown options={REGION_OPTIONS} value={region} onChange={v => setParam({ region: v, page: 1 })} placeholder="All Regions" />
-          <PixelDropdown options={LANGUAGE_OPTIONS} value={language} onChange={v => setParam({ language: v, page: 1 })} placeholder="All Languages" />
-          <PixelDropdown options={RANK_OPTIONS} value={rank} onChange={v => setParam({ rank: v, page: 1 })} placeholder="All Ranks" />
-          <PixelDropdown options={championOptions} value={champion} onChange={v => setParam({ champion: v, page: 1 })} placeholder="All Champions" />
+          <PixelDropdown options={REGION_OPTIONS} value={region} onChange={v => setParam({ region: v })} placeholder="All Regions" />
+          <PixelDropdown options={LANGUAGE_OPTIONS} value={language} onChange={v => setParam({ language: v })} placeholder="All Languages" />
+          <PixelDropdown options={RANK_OPTIONS} value={rank} onChange={v => setParam({ rank: v })} placeholder="All Ranks" />
+          <PixelDropdown options={championOptions} value={champion} onChange={v => setParam({ champion: v })} placeholder="All Champions" />
           {hasFilters && (
             <button
-              onClick={() => setParam({ region: '', language: '', rank: '', champion: '', page: 1 })}
+              onClick={() => setParam({ region: '', language: '', rank: '', champion: '' })}
               className="pixel-font text-[10px] px-3 py-2 bg-[var(--destructive)] text-white border-4 border-[var(--border)] rounded-[8px] pixel-shadow-sm tracking-wide uppercase hover:bg-[#b83a30] hover:-translate-x-0.5 hover:-translate-y-0.5 transition-all duration-150 btn-press inline-flex items-center gap-1"
             >
               <X size={12} strokeWidth={3} />
@@ -647,11 +699,20 @@ export function Leaderboard() {
         </div>
       )}
 
-      {/* Pagination — top */}
-      <Pagination page={page} totalPages={totalPages} onPageChange={p => setParam({ page: p })} />
+      {linked && (
+        <div className="animate-fade-in">
+          <TranslationCard
+            t={linked}
+            index={0}
+            onVote={(id, dir) => voteMutation.mutate({ id, direction: dir })}
+            onFeedback={(id, text) => feedbackMutation.mutate({ id, text })}
+            voteAnimation={voteAnimations[linked.id]}
+          />
+        </div>
+      )}
 
-      {/* Translation Cards — scrollable container */}
-      <div className="overflow-y-auto overflow-x-hidden max-h-[70vh] pixel-border bg-[var(--background-alt)] p-3 space-y-3">
+      {/* Translation Cards — scrollable container, loading more at the end */}
+      <div ref={scrollRef} className="overflow-y-auto overflow-x-hidden max-h-[70vh] pixel-border bg-[var(--background-alt)] p-3 space-y-3">
         {isLoading ? (
           <div className="pixel-border bg-[var(--card)] pixel-shadow-md p-12 text-center">
             <p className="pixel-font text-sm text-[var(--foreground-muted)] tracking-wide">Loading translations...</p>
@@ -662,7 +723,7 @@ export function Leaderboard() {
               <TranslationCard
                 key={t.id}
                 t={t}
-                index={(page - 1) * 25 + i}
+                index={i}
                 onVote={(id, dir) => voteMutation.mutate({ id, direction: dir })}
                 onFeedback={(id, text) => feedbackMutation.mutate({ id, text })}
                 voteAnimation={voteAnimations[t.id]}
@@ -675,6 +736,11 @@ export function Leaderboard() {
                 <p className="text-sm text-[var(--foreground-muted)]">Try adjusting your filters or check back later.</p>
               </div>
             )}
+
+            <div ref={sentinelRef} />
+            {isFetchingNextPage && (
+              <p className="pixel-font text-xs text-center text-[var(--foreground-muted)] tracking-wide py-2">Loading more...</p>
+            )}
           </>
         )}
       </div>